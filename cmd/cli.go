@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -49,33 +51,49 @@ func newApp() *cli.App {
 				Name:    "list-adapters",
 				Aliases: []string{"l"},
 				Usage:   "List available adapters.",
-				Action: func(*cli.Context, bool) error {
-					var sb strings.Builder
-
-					s := session.NewSession()
-					_, _, err := s.Start(nil, scfg.New())
-					if err != nil {
-						return err
-					}
-					defer s.Stop()
-
-					adapters, err := s.Adapters()
-					if err != nil {
-						return err
-					}
-
-					sb.WriteString("List of adapters:")
-					for _, adapter := range adapters {
-						sb.WriteString("\n")
-						sb.WriteString("- ")
-						sb.WriteString(getAdapterDisplayName(adapter))
-					}
-
-					fmt.Println(sb.String())
-
-					return nil
+				Action: func(cliCtx *cli.Context, _ bool) error {
+					return listAdapters(cliCtx)
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "list-devices",
+				Usage: "List devices of all adapters, or of the adapter specified with --adapter.",
+				Action: func(cliCtx *cli.Context, _ bool) error {
+					return listDevices(cliCtx)
+				},
+			},
+			&cli.BoolFlag{
+				Name: "status-line",
+				Usage: "Print a single line summary of the adapters and their connected devices, and exit. " +
+					"The format is '<adapter>: <on|off>[, scanning][, discoverable][; <device> connected[ <battery>%]]...', " +
+					"and multiple adapters are separated by ' | '.",
+				Action: func(cliCtx *cli.Context, _ bool) error {
+					return printStatusLine(cliCtx)
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "Print the summary again whenever it changes, when used with --status-line.",
+			},
+			&cli.StringFlag{
+				Name:  "connect",
+				Usage: "Connect to the device with the specified address or name (or a unique part of it), and exit.",
+				Action: func(cliCtx *cli.Context, query string) error {
+					return connectDevice(cliCtx, query)
 				},
 			},
+			&cli.StringFlag{
+				Name:  "disconnect",
+				Usage: "Disconnect from the device with the specified address or name (or a unique part of it), and exit.",
+				Action: func(cliCtx *cli.Context, query string) error {
+					return disconnectDevice(cliCtx, query)
+				},
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Value: "text",
+				Usage: "Specify the output format of the listing commands. (One of 'text' or 'json')",
+			},
 			&cli.StringFlag{
 				Name:    "adapter",
 				Aliases: []string{"a"},
@@ -86,31 +104,71 @@ func newApp() *cli.App {
 				Name:    "receive-dir",
 				Aliases: []string{"r"},
 				EnvVars: []string{"BLUETUITH_RECEIVE_DIR"},
-				Usage:   "Specify a directory to store received files.",
+				Usage:   "Specify a directory to store received files. (Default is $XDG_STATE_HOME/bluetuith/received, or ~/bluetuith if it exists)",
+			},
+			&cli.BoolFlag{
+				Name:    "receive-dir-per-device",
+				EnvVars: []string{"BLUETUITH_RECEIVE_DIR_PER_DEVICE"},
+				Usage:   "Store received files in a subdirectory named after the sending device.",
+			},
+			&cli.BoolFlag{
+				Name:    "verify-received",
+				EnvVars: []string{"BLUETUITH_VERIFY_RECEIVED"},
+				Usage:   "Display the SHA-256 checksum of received files, and warn if their size differs from the announced size.",
+			},
+			&cli.BoolFlag{
+				Name:    "receive-accept-trusted",
+				EnvVars: []string{"BLUETUITH_RECEIVE_ACCEPT_TRUSTED"},
+				Usage:   "Automatically accept files which are sent from trusted devices.",
 			},
 			&cli.StringFlag{
 				Name:    "gsm-apn",
 				Aliases: []string{"m"},
 				EnvVars: []string{"BLUETUITH_GSM_APN"},
-				Usage:   "Specify GSM APN to connect to. (Required for DUN)",
+				Usage:   "Specify the GSM APN which is suggested for DUN connections.",
 			},
 			&cli.StringFlag{
 				Name:    "gsm-number",
 				Aliases: []string{"b"},
 				EnvVars: []string{"BLUETUITH_GSM_NUMBER"},
-				Usage:   "Specify GSM number to dial. (Required for DUN)",
+				Usage:   "Specify the GSM number which is suggested for DUN connections.",
 			},
 			&cli.StringFlag{
 				Name:    "adapter-states",
 				Aliases: []string{"s"},
 				EnvVars: []string{"BLUETUITH_ADAPTER_STATES"},
-				Usage:   "Specify adapter states to enable/disable. (For example, 'powered:yes,discoverable:yes,pairable:yes,scan:no')",
+				Usage:   "Specify adapter states to enable/disable on startup. States are applied in the order powered, pairable, discoverable, scan. (For example, 'powered:yes,discoverable:yes,pairable:yes,scan:no')",
+			},
+			&cli.StringFlag{
+				Name:    "power",
+				EnvVars: []string{"BLUETUITH_POWER"},
+				Usage:   "Power the adapter on or off on startup. (One of 'yes' or 'no')",
+			},
+			&cli.StringFlag{
+				Name:    "pairable",
+				EnvVars: []string{"BLUETUITH_PAIRABLE"},
+				Usage:   "Make the adapter pairable or not on startup. (One of 'yes' or 'no')",
+			},
+			&cli.StringFlag{
+				Name:    "discoverable",
+				EnvVars: []string{"BLUETUITH_DISCOVERABLE"},
+				Usage:   "Make the adapter discoverable or not on startup. (One of 'yes' or 'no')",
+			},
+			&cli.StringFlag{
+				Name:    "scan",
+				EnvVars: []string{"BLUETUITH_SCAN"},
+				Usage:   "Start or stop scanning for devices on startup. (One of 'yes' or 'no')",
 			},
 			&cli.StringFlag{
 				Name:    "connect-bdaddr",
 				Aliases: []string{"t"},
 				EnvVars: []string{"BLUETUITH_CONNECT_BDADDR"},
-				Usage:   "Specify device address to connect (For example, 'AA:BB:CC:DD:EE:FF')",
+				Usage:   "Specify the address or name (or a unique part of it) of the device to connect (For example, 'AA:BB:CC:DD:EE:FF')",
+			},
+			&cli.BoolFlag{
+				Name:    "remember-last-device",
+				EnvVars: []string{"BLUETUITH_REMEMBER_LAST_DEVICE"},
+				Usage:   "Remember the most recently connected device across sessions, so that it can be connected to with the 'Connect Last' action right after launch.",
 			},
 			&cli.BoolFlag{
 				Name:    "no-warning",
@@ -130,6 +188,171 @@ func newApp() *cli.App {
 				EnvVars: []string{"BLUETUITH_CONFIRM_ON_QUIT"},
 				Usage:   "Ask for confirmation before quitting the application.",
 			},
+			&cli.BoolFlag{
+				Name:    "confirm-on-disconnect",
+				EnvVars: []string{"BLUETUITH_CONFIRM_ON_DISCONNECT"},
+				Usage:   "Ask for confirmation before disconnecting from a device.",
+			},
+			&cli.BoolFlag{
+				Name:    "confirm-on-block",
+				EnvVars: []string{"BLUETUITH_CONFIRM_ON_BLOCK"},
+				Usage:   "Ask for confirmation before blocking a device.",
+			},
+			&cli.BoolFlag{
+				Name:    "no-confirm-on-remove",
+				EnvVars: []string{"BLUETUITH_NO_CONFIRM_ON_REMOVE"},
+				Usage:   "Do not ask for confirmation before removing a device.",
+			},
+			&cli.StringFlag{
+				Name:    "on-connect",
+				EnvVars: []string{"BLUETUITH_ON_CONNECT"},
+				Usage:   "Specify a command to run when a device connects. (The device's address and name are passed as '$1' and '$2', and set as $BLUETUITH_DEVICE_ADDRESS and $BLUETUITH_DEVICE_NAME)",
+			},
+			&cli.StringFlag{
+				Name:    "on-disconnect",
+				EnvVars: []string{"BLUETUITH_ON_DISCONNECT"},
+				Usage:   "Specify a command to run when a device disconnects. (The device's address and name are passed as '$1' and '$2', and set as $BLUETUITH_DEVICE_ADDRESS and $BLUETUITH_DEVICE_NAME)",
+			},
+			&cli.IntFlag{
+				Name:    "hook-timeout",
+				EnvVars: []string{"BLUETUITH_HOOK_TIMEOUT"},
+				Usage:   "Specify the time in seconds after which the connect and disconnect commands are stopped. (Default is 10)",
+			},
+			&cli.BoolFlag{
+				Name:    "disable-mouse",
+				EnvVars: []string{"BLUETUITH_DISABLE_MOUSE"},
+				Usage:   "Do not capture the mouse, so that text can be selected in the terminal.",
+			},
+			&cli.IntFlag{
+				Name:    "mouse-scroll-lines",
+				EnvVars: []string{"BLUETUITH_MOUSE_SCROLL_LINES"},
+				Usage:   "Specify the number of lines to move the selection by for each mouse wheel notch. (Default is 1)",
+			},
+			&cli.BoolFlag{
+				Name:    "menu-remember-last",
+				EnvVars: []string{"BLUETUITH_MENU_REMEMBER_LAST"},
+				Usage:   "Open the menu at the most recently used submenu, instead of the adapter menu.",
+			},
+			&cli.BoolFlag{
+				Name:    "file-picker-remember-dir",
+				EnvVars: []string{"BLUETUITH_FILE_PICKER_REMEMBER_DIR"},
+				Usage:   "Reopen the file picker in the last used directory.",
+			},
+			&cli.StringFlag{
+				Name:    "log-file",
+				EnvVars: []string{"BLUETUITH_LOG_FILE"},
+				Usage:   "Specify a file to log all status messages, errors, events and actions to. (A file name without a directory is stored in $XDG_STATE_HOME/bluetuith)",
+			},
+			&cli.IntFlag{
+				Name:    "log-max-size",
+				EnvVars: []string{"BLUETUITH_LOG_MAX_SIZE"},
+				Usage:   "Specify the size in kilobytes after which the log file is rotated. (Default is 1024)",
+			},
+			&cli.BoolFlag{
+				Name:    "log-no-addresses",
+				EnvVars: []string{"BLUETUITH_LOG_NO_ADDRESSES"},
+				Usage:   "Mask all device and adapter addresses in the log file.",
+			},
+			&cli.BoolFlag{
+				Name:    "desktop-notifications",
+				EnvVars: []string{"BLUETUITH_DESKTOP_NOTIFICATIONS"},
+				Usage:   "Send desktop notifications for completed transfers and incoming file requests.",
+			},
+			&cli.BoolFlag{
+				Name:    "mpris",
+				EnvVars: []string{"BLUETUITH_MPRIS"},
+				Usage:   "Allow controlling the media player of connected devices via MPRIS2 desktop media controls.",
+			},
+			&cli.BoolFlag{
+				Name:    "album-art",
+				EnvVars: []string{"BLUETUITH_ALBUM_ART"},
+				Usage:   "Display the cover art of the playing track in the media player, using the kitty graphics protocol if the terminal supports it, or colored text otherwise.",
+			},
+			&cli.IntFlag{
+				Name:    "battery-threshold",
+				EnvVars: []string{"BLUETUITH_BATTERY_THRESHOLD"},
+				Usage:   "Specify the battery percentage below which connected devices are alerted for. (Default is 15)",
+			},
+			&cli.StringFlag{
+				Name:    "device-sort",
+				EnvVars: []string{"BLUETUITH_DEVICE_SORT"},
+				Usage:   "Specify the sort mode of the devices list. (One of 'connected', 'rssi' or 'name')",
+			},
+			&cli.StringFlag{
+				Name:    "theme-file",
+				EnvVars: []string{"BLUETUITH_THEME_FILE"},
+				Usage:   "Specify a file to load the theme from. (The theme configuration in the configuration file overrides it)",
+			},
+			&cli.StringFlag{
+				Name:    "color-mode",
+				EnvVars: []string{"BLUETUITH_COLOR_MODE"},
+				Usage:   "Specify the color mode of the theme. (One of 'auto', 'truecolor', '256' or '16')",
+			},
+			&cli.BoolFlag{
+				Name:    "safe-mode",
+				EnvVars: []string{"BLUETUITH_SAFE_MODE"},
+				Usage:   "Automatically block unknown devices which attempt to pair, unless they are in the pairing allowlist.",
+			},
+			&cli.StringFlag{
+				Name:    "agent-capability",
+				EnvVars: []string{"BLUETUITH_AGENT_CAPABILITY"},
+				Usage:   "Specify the IO capability of the pairing agent, which determines how pairing requests are authenticated. (One of 'KeyboardDisplay', 'DisplayOnly', 'DisplayYesNo', 'KeyboardOnly' or 'NoInputNoOutput', default is 'KeyboardDisplay')",
+			},
+			&cli.IntFlag{
+				Name:    "discoverable-timeout",
+				EnvVars: []string{"BLUETUITH_DISCOVERABLE_TIMEOUT"},
+				Usage:   "Specify the time in seconds after which the adapter is made undiscoverable. (0 disables the timeout)",
+			},
+			&cli.IntFlag{
+				Name:    "pairable-timeout",
+				EnvVars: []string{"BLUETUITH_PAIRABLE_TIMEOUT"},
+				Usage:   "Specify the time in seconds after which the adapter is made unpairable. (0 disables the timeout)",
+			},
+			&cli.StringFlag{
+				Name:    "vendor-file",
+				EnvVars: []string{"BLUETUITH_VENDOR_FILE"},
+				Usage:   "Specify a file to load the manufacturer names of device addresses from. (Each line holds an OUI followed by a name, like the IEEE 'oui.txt' registry)",
+			},
+			&cli.IntFlag{
+				Name:    "min-rssi",
+				EnvVars: []string{"BLUETUITH_MIN_RSSI"},
+				Usage:   "Specify the signal strength in dBm below which discovered devices are hidden. (0 shows all devices)",
+			},
+			&cli.IntFlag{
+				Name:    "scan-timeout",
+				EnvVars: []string{"BLUETUITH_SCAN_TIMEOUT"},
+				Usage:   "Specify the time in seconds after which scanning for devices is stopped. (0 disables the timeout)",
+			},
+			&cli.IntFlag{
+				Name:    "scan-name-wait",
+				EnvVars: []string{"BLUETUITH_SCAN_NAME_WAIT"},
+				Usage:   "Specify the time in milliseconds to wait for the name of a discovered device before it is listed. (0 lists devices immediately)",
+			},
+			&cli.StringFlag{
+				Name:    "auto-reconnect",
+				EnvVars: []string{"BLUETUITH_AUTO_RECONNECT"},
+				Usage:   "Specify device addresses to reconnect to when they disconnect. (For example, 'AA:BB:CC:DD:EE:FF,11:22:33:44:55:66')",
+			},
+			&cli.IntFlag{
+				Name:    "connect-retries",
+				EnvVars: []string{"BLUETUITH_CONNECT_RETRIES"},
+				Usage:   "Specify the number of times to retry connecting to a device if the connection fails. (Default is 0)",
+			},
+			&cli.IntFlag{
+				Name:    "connect-retry-delay",
+				EnvVars: []string{"BLUETUITH_CONNECT_RETRY_DELAY"},
+				Usage:   "Specify the time in seconds to wait before the first connection retry, which is doubled for each retry. (Default is 2)",
+			},
+			&cli.IntFlag{
+				Name:    "key-sequence-timeout",
+				EnvVars: []string{"BLUETUITH_KEY_SEQUENCE_TIMEOUT"},
+				Usage:   "Specify the time in milliseconds to wait for the second key of a key sequence. (Default is 500)",
+			},
+			&cli.StringFlag{
+				Name:    "keybinding-preset",
+				EnvVars: []string{"BLUETUITH_KEYBINDING_PRESET"},
+				Usage:   "Specify the built-in keybinding preset, which keybindings from the configuration are applied on top of. (One of 'default' or 'vim')",
+			},
 			&cli.BoolFlag{
 				Name:    "disable-obex-services",
 				Aliases: []string{"o"},
@@ -158,9 +381,76 @@ func newApp() *cli.App {
 					return err
 				},
 			},
+			&cli.StringFlag{
+				Name:  "dump-config",
+				Usage: "Write the current keybindings and theme to the specified file, in the configuration file format. (Use '-' to write to standard output)",
+				Action: func(cliCtx *cli.Context, path string) error {
+					k := koanf.New(".")
+
+					cliCtx.Command.Name = "global"
+
+					conf := config.NewConfig()
+					if err := conf.Load(k, cliCtx); err != nil {
+						return err
+					}
+					if err := conf.ValidateValues(); err != nil {
+						return err
+					}
+
+					data, err := conf.Dump(cliCtx.Bool("minimal"))
+					if err != nil {
+						return err
+					}
+
+					if path == "-" {
+						_, err = os.Stdout.Write(data)
+						return err
+					}
+
+					return os.WriteFile(path, data, 0o644)
+				},
+			},
+			&cli.BoolFlag{
+				Name: "check-config",
+				Usage: "Check the configuration file and options for problems, like conflicting keybindings, unknown keys " +
+					"and incorrect theme colors, without connecting to Bluetooth, and exit. (Exits with an error if problems are found)",
+				Action: func(cliCtx *cli.Context, _ bool) error {
+					k := koanf.New(".")
+
+					cliCtx.Command.Name = "global"
+
+					conf := config.NewConfig()
+					if err := conf.Load(k, cliCtx); err != nil {
+						return err
+					}
+
+					errs := conf.Check()
+					if len(errs) == 0 {
+						fmt.Println("No problems were found in the configuration.")
+						return nil
+					}
+
+					var report strings.Builder
+
+					report.WriteString("The following problems were found in the configuration:")
+					for _, err := range errs {
+						report.WriteString("\n- ")
+						report.WriteString(strings.ReplaceAll(err.Error(), "\n", "\n  "))
+					}
+
+					fmt.Println(report.String())
+
+					return errors.New(strconv.Itoa(len(errs)) + " problem(s) were found in the configuration")
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "minimal",
+				Usage: "Only write values that differ from the defaults when dumping the configuration.",
+			},
 		}, getPlatformSpecificFlags()...),
 		Action: func(cliCtx *cli.Context) error {
-			if cliCtx.Bool("list-adapters") || cliCtx.Bool("generate") {
+			if cliCtx.Bool("list-adapters") || cliCtx.Bool("list-devices") || cliCtx.Bool("status-line") || cliCtx.Bool("generate") ||
+				cliCtx.Bool("check-config") || cliCtx.IsSet("dump-config") || cliCtx.IsSet("connect") || cliCtx.IsSet("disconnect") {
 				return nil
 			}
 
@@ -183,14 +473,22 @@ func newApp() *cli.App {
 			if err != nil {
 				return err
 			}
-			defer s.Stop()
+			defer app.StopSession(s)
 
 			if err := cfg.ValidateSessionValues(s); err != nil {
 				return err
 			}
 			printUnsupportedFeatures(cfg, featureSet)
 
-			return app.Start(s, featureSet, cfg)
+			if path := cliCtx.String("serve"); path != "" {
+				srv, err := startServer(s, path)
+				if err != nil {
+					return err
+				}
+				defer srv.stop()
+			}
+
+			return app.Start(s, sessionCfg, featureSet, cfg)
 		},
 		ExitErrHandler: func(_ *cli.Context, err error) {
 			if err == nil {
@@ -202,7 +500,8 @@ func newApp() *cli.App {
 	}
 }
 
-// printUnsupportedFeatures prints all unsupported features of the session.
+// printUnsupportedFeatures prints all unsupported features of the session, the configuration
+// values which were reset to their defaults, and whether the theme file could not be loaded.
 func printUnsupportedFeatures(cfg *config.Config, featureSet *appfeatures.FeatureSet) {
 	if cfg.Values.NoWarning {
 		return
@@ -210,23 +509,56 @@ func printUnsupportedFeatures(cfg *config.Config, featureSet *appfeatures.Featur
 
 	var warn strings.Builder
 
-	featErrors, exists := featureSet.Errors.Exists()
-	if !exists {
-		return
+	if err := cfg.DecodeError(); err != nil {
+		warn.WriteString(err.Error())
+	}
+
+	if err := cfg.Values.ThemeFileError; err != nil {
+		if warn.Len() > 0 {
+			warn.WriteString("\n")
+		}
+
+		warn.WriteString(err.Error())
 	}
 
-	warn.WriteString("The following features are not available:")
-	for feature, errors := range featErrors {
-		warn.WriteString("\n")
-		warn.WriteString(feature.String())
-		warn.WriteString(": ")
-		warn.WriteString(errors.Error())
+	if featErrors, exists := featureSet.Errors.Exists(); exists {
+		if warn.Len() > 0 {
+			warn.WriteString("\n")
+		}
+
+		warn.WriteString("The following features are not available:")
+		for feature, errors := range featErrors {
+			warn.WriteString("\n")
+			warn.WriteString(feature.String())
+			warn.WriteString(": ")
+			warn.WriteString(errors.Error())
+		}
+	}
+
+	if warn.Len() == 0 {
+		return
 	}
 
 	printWarn(warn.String())
 	time.Sleep(1 * time.Second)
 }
 
+// startSession starts a session for the non-interactive commands.
+// OBEX services are not required by these commands, and are disabled.
+// The session must only be stopped if it has been started successfully.
+func startSession(cliCtx *cli.Context) (bluetooth.Session, error) {
+	sessionCfg := scfg.New()
+	populateSessionConfig(cliCtx, &sessionCfg)
+	sessionCfg.EnableObexServices = false
+
+	s := session.NewSession()
+	if _, _, err := s.Start(nil, sessionCfg); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 func populateSessionConfig(cliCtx *cli.Context, sessionCfg *scfg.Configuration) {
 	sessionCfg.EnableObexServices = true
 	if cliCtx.Bool("disable-obex-services") {
@@ -239,6 +571,10 @@ func populateSessionConfig(cliCtx *cli.Context, sessionCfg *scfg.Configuration)
 
 // getAdapterDisplayName returns the display name of the adapter.
 func getAdapterDisplayName(adapterData bluetooth.AdapterData) string {
+	if alias, ok := adapterData.Alias.Get(); ok && alias != "" {
+		return alias
+	}
+
 	if name, ok := adapterData.Name.Get(); ok {
 		return name
 	}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/urfave/cli/v2"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// connectDevice connects to the device which matches the provided address or name.
+func connectDevice(cliCtx *cli.Context, query string) error {
+	return deviceCommand(cliCtx, query, "Connected to", func(device bluetooth.Device) error {
+		return device.Connect()
+	})
+}
+
+// disconnectDevice disconnects from the device which matches the provided address or name.
+func disconnectDevice(cliCtx *cli.Context, query string) error {
+	return deviceCommand(cliCtx, query, "Disconnected from", func(device bluetooth.Device) error {
+		return device.Disconnect()
+	})
+}
+
+// deviceCommand starts a session, invokes the provided function on the device which
+// matches the provided address or name, and prints the result.
+func deviceCommand(cliCtx *cli.Context, query, result string, command func(bluetooth.Device) error) error {
+	s, err := startSession(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer s.Stop()
+
+	device, _, err := btdevice.Find(s, cliCtx.String("adapter"), query)
+	if err != nil {
+		return err
+	}
+
+	if err := command(s.Device(device.DeviceAddress)); err != nil {
+		return fmt.Errorf("%s: %w", btdevice.DisplayName(device.DeviceEventData), err)
+	}
+
+	fmt.Println(result, btdevice.DisplayName(device.DeviceEventData), "("+device.Address.String()+")")
+
+	return nil
+}
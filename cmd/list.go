@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/urfave/cli/v2"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// adapterOutput describes the adapter information that is listed in the JSON output.
+type adapterOutput struct {
+	Address      string `json:"address"`
+	UniqueName   string `json:"unique_name"`
+	Name         string `json:"name"`
+	Alias        string `json:"alias,omitempty"`
+	Powered      bool   `json:"powered"`
+	Discovering  bool   `json:"discovering"`
+	Discoverable bool   `json:"discoverable"`
+	Pairable     bool   `json:"pairable"`
+}
+
+// deviceOutput describes the device information that is listed in the JSON output.
+type deviceOutput struct {
+	Address   string  `json:"address"`
+	Adapter   string  `json:"adapter"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type,omitempty"`
+	Connected bool    `json:"connected"`
+	Paired    bool    `json:"paired"`
+	Trusted   bool    `json:"trusted"`
+	RSSI      *int16  `json:"rssi,omitempty"`
+	Battery   *uint32 `json:"battery,omitempty"`
+}
+
+// listAdapters lists all the adapters in the system, in the specified output format.
+func listAdapters(cliCtx *cli.Context) error {
+	var sb strings.Builder
+
+	jsonOutput, err := isJSONOutput(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	s, err := startSession(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer s.Stop()
+
+	adapters, err := s.Adapters()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		output := make([]adapterOutput, 0, len(adapters))
+		for _, adapter := range adapters {
+			output = append(output, newAdapterOutput(adapter))
+		}
+
+		return printJSON(output)
+	}
+
+	sb.WriteString("List of adapters:")
+	for _, adapter := range adapters {
+		sb.WriteString("\n")
+		sb.WriteString("- ")
+		sb.WriteString(getAdapterDisplayName(adapter))
+	}
+
+	fmt.Println(sb.String())
+
+	return nil
+}
+
+// listDevices lists all the devices of the adapters in the system, in the specified output format.
+// If an adapter is specified, only the devices of that adapter are listed.
+func listDevices(cliCtx *cli.Context) error {
+	var sb strings.Builder
+
+	jsonOutput, err := isJSONOutput(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	s, err := startSession(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer s.Stop()
+
+	adapters, err := s.Adapters()
+	if err != nil {
+		return err
+	}
+
+	selected := cliCtx.String("adapter")
+	output := []deviceOutput{}
+
+	for _, adapter := range adapters {
+		if selected != "" && adapter.UniqueName != selected && adapter.Address.String() != selected {
+			continue
+		}
+
+		devices, err := s.Adapter(adapter.AdapterAddress).Devices()
+		if err != nil {
+			return err
+		}
+
+		if !jsonOutput {
+			sb.WriteString("Devices on ")
+			sb.WriteString(getAdapterDisplayName(adapter))
+			sb.WriteString(":")
+		}
+
+		for _, device := range devices {
+			if jsonOutput {
+				output = append(output, newDeviceOutput(adapter, device))
+				continue
+			}
+
+			sb.WriteString("\n")
+			sb.WriteString("- ")
+			sb.WriteString(btdevice.DisplayName(device.DeviceEventData))
+			sb.WriteString(" (")
+			sb.WriteString(device.Address.String())
+			sb.WriteString(")")
+
+			if connected, ok := device.Connected.Get(); ok && connected {
+				sb.WriteString(" [connected]")
+			}
+		}
+
+		if !jsonOutput {
+			sb.WriteString("\n")
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(output)
+	}
+
+	fmt.Print(sb.String())
+
+	return nil
+}
+
+// isJSONOutput returns whether the specified output format is JSON.
+func isJSONOutput(cliCtx *cli.Context) (bool, error) {
+	switch output := cliCtx.String("output"); output {
+	case "", "text":
+		return false, nil
+
+	case "json":
+		return true, nil
+
+	default:
+		return false, fmt.Errorf("provided output format '%s' is incorrect.\nValid output formats are 'text, json'", output)
+	}
+}
+
+// printJSON prints the provided value as indented JSON to the standard output.
+func printJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+// newAdapterOutput returns the JSON output for the provided adapter.
+func newAdapterOutput(adapter bluetooth.AdapterData) adapterOutput {
+	output := adapterOutput{
+		Address:    adapter.Address.String(),
+		UniqueName: adapter.UniqueName,
+		Name:       getAdapterDisplayName(adapter),
+	}
+
+	output.Alias, _ = adapter.Alias.Get()
+	output.Powered, _ = adapter.Powered.Get()
+	output.Discovering, _ = adapter.Discovering.Get()
+	output.Discoverable, _ = adapter.Discoverable.Get()
+	output.Pairable, _ = adapter.Pairable.Get()
+
+	return output
+}
+
+// newDeviceOutput returns the JSON output for the provided device.
+func newDeviceOutput(adapter bluetooth.AdapterData, device bluetooth.DeviceData) deviceOutput {
+	output := deviceOutput{
+		Address: device.Address.String(),
+		Adapter: adapter.UniqueName,
+		Name:    btdevice.DisplayName(device.DeviceEventData),
+		Type:    device.Type,
+	}
+
+	output.Connected, _ = device.Connected.Get()
+	output.Paired, _ = device.Paired.Get()
+	output.Trusted, _ = device.Trusted.Get()
+
+	if rssi, ok := device.RSSI.Get(); ok {
+		output.RSSI = &rssi
+	}
+	if percentage, ok := device.Percentage.Get(); ok {
+		output.Battery = &percentage
+	}
+
+	return output
+}
@@ -5,5 +5,16 @@ package cmd
 import "github.com/urfave/cli/v2"
 
 func getPlatformSpecificFlags() []cli.Flag {
-	return nil
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "serve",
+			EnvVars: []string{"BLUETUITH_SERVE"},
+			Usage:   "Serve commands and events over a unix socket at the specified path, while the application is running. (A socket name without a directory is created in $XDG_CACHE_HOME/bluetuith)",
+		},
+		&cli.StringFlag{
+			Name:    "audio-backend",
+			EnvVars: []string{"BLUETUITH_AUDIO_BACKEND"},
+			Usage:   "Specify the sound server which manages the audio profiles of devices. (One of 'auto', 'pulseaudio' or 'pipewire')",
+		},
+	}
 }
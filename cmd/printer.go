@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/fatih/color"
 )
 
@@ -15,5 +17,5 @@ func printWarn(message string) {
 func printError(err error) {
 	message := "[!] " + err.Error()
 
-	color.New(color.FgRed, color.Bold).Println(message)
+	color.New(color.FgRed, color.Bold).Fprintln(os.Stderr, message)
 }
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+	"github.com/darkhz/bluetuith/ui/config"
+)
+
+// serverRequest describes a command sent by a client of the server.
+type serverRequest struct {
+	Command string `json:"command"`
+	Address string `json:"address,omitempty"`
+	Adapter string `json:"adapter,omitempty"`
+}
+
+// serverResponse describes the reply to a command, or an event sent to a subscribed client.
+type serverResponse struct {
+	Command string `json:"command,omitempty"`
+	Event   string `json:"event,omitempty"`
+	Action  string `json:"action,omitempty"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// server exposes the session of the application over a unix socket, so that
+// external tools can query and control it while the application is running.
+// Each request and response is a single line of JSON.
+type server struct {
+	session  bluetooth.Session
+	listener net.Listener
+	path     string
+
+	conns map[net.Conn]struct{}
+	mu    sync.Mutex
+}
+
+// startServer listens on the socket at the provided path, and serves requests
+// using the provided session. A stale socket file at the path is removed.
+// If only a socket name is provided, the socket is created in the cache directory.
+func startServer(session bluetooth.Session, path string) (*server, error) {
+	if filepath.Base(path) == path {
+		cacheDir, err := config.CacheDir()
+		if err != nil {
+			return nil, err
+		}
+
+		path = filepath.Join(cacheDir, path)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", path)
+		}
+
+		os.Remove(path)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen on socket %s: %w", path, err)
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("cannot set permissions of socket %s: %w", path, err)
+	}
+
+	s := &server{
+		session:  session,
+		listener: listener,
+		path:     path,
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	go s.accept()
+
+	return s, nil
+}
+
+// stop closes all client connections and the listener, and removes the socket file.
+func (s *server) stop() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	os.Remove(s.path)
+}
+
+// accept accepts client connections until the listener is closed.
+func (s *server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			continue
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// handle reads requests from the client connection and replies to them.
+func (s *server) handle(conn net.Conn) {
+	var (
+		encoder = json.NewEncoder(conn)
+		writeMu sync.Mutex
+		done    = make(chan struct{})
+	)
+
+	defer func() {
+		close(done)
+		conn.Close()
+
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	write := func(response serverResponse) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		return encoder.Encode(response)
+	}
+
+	subscribed := false
+	scanner := bufio.NewScanner(conn)
+
+	for scanner.Scan() {
+		var request serverRequest
+
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			if write(serverResponse{Error: "invalid request: " + err.Error()}) != nil {
+				return
+			}
+
+			continue
+		}
+
+		response := serverResponse{Command: request.Command}
+
+		if request.Command == "subscribe" {
+			if !subscribed {
+				subscribed = true
+				go s.events(write, done)
+			}
+
+			response.OK = true
+		} else {
+			data, err := s.command(request)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.OK, response.Data = true, data
+			}
+		}
+
+		if write(response) != nil {
+			return
+		}
+	}
+}
+
+// command runs the command of the request, and returns its result.
+func (s *server) command(request serverRequest) (any, error) {
+	switch request.Command {
+	case "list-adapters":
+		adapters, err := s.session.Adapters()
+		if err != nil {
+			return nil, err
+		}
+
+		output := make([]adapterOutput, 0, len(adapters))
+		for _, adapter := range adapters {
+			output = append(output, newAdapterOutput(adapter))
+		}
+
+		return output, nil
+
+	case "list-devices":
+		adapters, err := s.session.Adapters()
+		if err != nil {
+			return nil, err
+		}
+
+		output := []deviceOutput{}
+		for _, adapter := range adapters {
+			if request.Adapter != "" && adapter.UniqueName != request.Adapter && adapter.Address.String() != request.Adapter {
+				continue
+			}
+
+			devices, err := s.session.Adapter(adapter.AdapterAddress).Devices()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, device := range devices {
+				output = append(output, newDeviceOutput(adapter, device))
+			}
+		}
+
+		return output, nil
+
+	case "connect", "disconnect":
+		device, _, err := btdevice.Find(s.session, request.Adapter, request.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		if request.Command == "connect" {
+			err = s.session.Device(device.DeviceAddress).Connect()
+		} else {
+			err = s.session.Device(device.DeviceAddress).Disconnect()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", btdevice.DisplayName(device.DeviceEventData), err)
+		}
+
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown command '%s'.\nValid commands are 'list-adapters, list-devices, connect, disconnect, subscribe'", request.Command)
+}
+
+// events sends adapter and device events to the client, until the client disconnects.
+func (s *server) events(write func(serverResponse) error, done chan struct{}) {
+	adapterSub, ok := bluetooth.AdapterEvents().Subscribe()
+	if !ok {
+		write(serverResponse{Event: "adapter", Error: "cannot subscribe to adapter events"})
+		return
+	}
+	defer adapterSub.Unsubscribe()
+
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		write(serverResponse{Event: "device", Error: "cannot subscribe to device events"})
+		return
+	}
+	defer deviceSub.Unsubscribe()
+
+	for {
+		var response serverResponse
+
+		select {
+		case <-done:
+			return
+
+		case <-adapterSub.Done:
+			return
+
+		case <-deviceSub.Done:
+			return
+
+		case ev, ok := <-adapterSub.AddedEvents:
+			if !ok {
+				return
+			}
+
+			response = serverResponse{Event: "adapter", Action: bluetooth.EventActionAdded.String(), Data: ev}
+
+		case ev, ok := <-adapterSub.UpdatedEvents:
+			if !ok {
+				return
+			}
+
+			response = serverResponse{Event: "adapter", Action: bluetooth.EventActionUpdated.String(), Data: ev}
+
+		case ev, ok := <-adapterSub.RemovedEvents:
+			if !ok {
+				return
+			}
+
+			response = serverResponse{Event: "adapter", Action: bluetooth.EventActionRemoved.String(), Data: ev}
+
+		case ev, ok := <-deviceSub.AddedEvents:
+			if !ok {
+				return
+			}
+
+			response = serverResponse{Event: "device", Action: bluetooth.EventActionAdded.String(), Data: ev}
+
+		case ev, ok := <-deviceSub.UpdatedEvents:
+			if !ok {
+				return
+			}
+
+			response = serverResponse{Event: "device", Action: bluetooth.EventActionUpdated.String(), Data: ev}
+
+		case ev, ok := <-deviceSub.RemovedEvents:
+			if !ok {
+				return
+			}
+
+			response = serverResponse{Event: "device", Action: bluetooth.EventActionRemoved.String(), Data: ev}
+		}
+
+		response.OK = true
+		if write(response) != nil {
+			return
+		}
+	}
+}
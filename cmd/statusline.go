@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/urfave/cli/v2"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// printStatusLine prints a single line summary of the adapters and their connected devices.
+// If the "watch" flag is set, the summary is printed again whenever it changes, until the
+// application is interrupted.
+func printStatusLine(cliCtx *cli.Context) error {
+	s, err := startSession(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer s.Stop()
+
+	selected := cliCtx.String("adapter")
+
+	line, err := statusLine(s, selected)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(line)
+
+	if !cliCtx.Bool("watch") {
+		return nil
+	}
+
+	adapterSub, ok := bluetooth.AdapterEvents().Subscribe()
+	if !ok {
+		return errors.New("cannot subscribe to adapter events")
+	}
+	defer adapterSub.Unsubscribe()
+
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		return errors.New("cannot subscribe to device events")
+	}
+	defer deviceSub.Unsubscribe()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for {
+		var ok bool
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-adapterSub.Done:
+			return nil
+
+		case <-deviceSub.Done:
+			return nil
+
+		case _, ok = <-adapterSub.AddedEvents:
+		case _, ok = <-adapterSub.UpdatedEvents:
+		case _, ok = <-adapterSub.RemovedEvents:
+		case _, ok = <-deviceSub.AddedEvents:
+		case _, ok = <-deviceSub.UpdatedEvents:
+		case _, ok = <-deviceSub.RemovedEvents:
+		}
+		if !ok {
+			return nil
+		}
+
+		current, err := statusLine(s, selected)
+		if err != nil {
+			return err
+		}
+
+		if current != line {
+			line = current
+			fmt.Println(line)
+		}
+	}
+}
+
+// statusLine returns a single line summary of the adapters and their connected devices.
+// If an adapter is specified, only that adapter is summarized.
+//
+// The format of the summary is stable, so that it can be parsed by status bars:
+//
+//	<adapter>: <on|off>[, scanning][, discoverable][; <device> connected[ <battery>%]]...
+//
+// where the adapter is denoted by its unique name (for example, "hci0"), and the devices
+// are denoted by their display names. Multiple adapters are separated by " | ".
+func statusLine(s bluetooth.Session, selected string) (string, error) {
+	adapters, err := s.Adapters()
+	if err != nil {
+		return "", err
+	}
+
+	summaries := make([]string, 0, len(adapters))
+	for _, adapter := range adapters {
+		if selected != "" && adapter.UniqueName != selected && adapter.Address.String() != selected {
+			continue
+		}
+
+		states := []string{"off"}
+		if powered, _ := adapter.Powered.Get(); powered {
+			states[0] = "on"
+		}
+		if discovering, _ := adapter.Discovering.Get(); discovering {
+			states = append(states, "scanning")
+		}
+		if discoverable, _ := adapter.Discoverable.Get(); discoverable {
+			states = append(states, "discoverable")
+		}
+
+		summary := []string{adapter.UniqueName + ": " + strings.Join(states, ", ")}
+
+		devices, err := s.Adapter(adapter.AdapterAddress).Devices()
+		if err != nil {
+			return "", err
+		}
+
+		for _, device := range devices {
+			if connected, _ := device.Connected.Get(); !connected {
+				continue
+			}
+
+			text := btdevice.DisplayName(device.DeviceEventData) + " connected"
+			if percentage, ok := device.Percentage.Get(); ok {
+				text += " " + strconv.FormatUint(uint64(percentage), 10) + "%"
+			}
+
+			summary = append(summary, text)
+		}
+
+		summaries = append(summaries, strings.Join(summary, "; "))
+	}
+
+	if len(summaries) == 0 && selected != "" {
+		return "", fmt.Errorf("the adapter '%s' was not found", selected)
+	}
+
+	return strings.Join(summaries, " | "), nil
+}
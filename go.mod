@@ -7,6 +7,7 @@ require (
 	github.com/darkhz/tview v0.0.0-20260701030911-bce6224ff25f
 	github.com/fatih/color v1.18.0
 	github.com/gdamore/tcell/v2 v2.13.10
+	github.com/godbus/dbus/v5 v5.2.2
 	github.com/google/uuid v1.6.0
 	github.com/knadh/koanf/parsers/hjson v1.0.0
 	github.com/knadh/koanf/providers/cliflagv2 v1.0.1
@@ -31,7 +32,6 @@ require (
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
-	github.com/godbus/dbus/v5 v5.2.2 // indirect
 	github.com/hjson/hjson-go/v4 v4.5.0 // indirect
 	github.com/knadh/koanf/maps v0.1.2 // indirect
 	github.com/lucasb-eyer/go-colorful v1.4.0 // indirect
@@ -5,16 +5,20 @@ import (
 
 	"github.com/bluetuith-org/bluetooth-classic/api/appfeatures"
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	scfg "github.com/bluetuith-org/bluetooth-classic/api/config"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"go.uber.org/atomic"
 
 	"github.com/darkhz/bluetuith/ui/app/views"
 	"github.com/darkhz/bluetuith/ui/config"
+	"github.com/darkhz/bluetuith/ui/theme"
 )
 
 // Application holds an application with its views.
 type Application struct {
-	view *views.Views
+	view   *views.Views
+	binder *appBinder
 }
 
 // NewApplication returns a new application.
@@ -25,26 +29,65 @@ func NewApplication() *Application {
 }
 
 // Start starts the application.
-func (a *Application) Start(session bluetooth.Session, featureSet *appfeatures.FeatureSet, cfg *config.Config) error {
+// The session configuration is used to restart the session, if required.
+func (a *Application) Start(session bluetooth.Session, sessionCfg scfg.Configuration, featureSet *appfeatures.FeatureSet, cfg *config.Config) error {
 	binder := &appBinder{
 		session:     session,
+		sessionCfg:  sessionCfg,
+		authorizer:  a.Authorizer(),
 		draws:       make(chan struct{}, 1),
-		featureSet:  featureSet,
 		Application: tview.NewApplication(),
 	}
+	binder.featureSet.Store(featureSet)
+	binder.started.Store(true)
+
+	a.binder = binder
+
+	// The screen is initialized before the views, so that the theme colors
+	// can be adapted to the number of colors supported by the terminal.
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	binder.SetScreen(screen)
+	theme.SetColorMode(theme.ColorMode(cfg.Values.ColorMode), screen.Colors())
 
 	appview, err := a.view.Initialize(binder, cfg)
 	if err != nil {
+		screen.Fini()
 		return err
 	}
 
 	binder.SetInputCapture(appview.InputCapture)
-	binder.SetMouseCapture(appview.MouseFunc)
 	binder.SetBeforeDrawFunc(appview.BeforeDrawFunc)
+	binder.SetAfterDrawFunc(appview.AfterDrawFunc)
+
+	// When the mouse is disabled, no mouse events are received,
+	// and all views remain accessible with the keyboard.
+	enableMouse := !cfg.Values.DisableMouse
+	if enableMouse {
+		binder.SetMouseCapture(appview.MouseFunc)
+	}
 
 	go binder.monitorQueuedDraws()
 
-	return binder.SetRoot(appview.Layout, true).SetFocus(appview.InitialFocus).EnableMouse(true).Run()
+	return binder.SetRoot(appview.Layout, true).SetFocus(appview.InitialFocus).EnableMouse(enableMouse).Run()
+}
+
+// StopSession stops the session, along with the services run by the views.
+// If the session could not be restarted by the application, it is not stopped again,
+// since a session which failed to start cannot be stopped.
+func (a *Application) StopSession(session bluetooth.Session) {
+	if a.view != nil {
+		a.view.Close()
+	}
+
+	if a.binder != nil {
+		a.binder.stopSession()
+		return
+	}
+
+	_ = session.Stop()
 }
 
 // Authorizer returns the session's authorizer.
@@ -55,7 +98,10 @@ func (a *Application) Authorizer() bluetooth.SessionAuthorizer {
 // appBinder holds the bluetooth session and the application.
 type appBinder struct {
 	session       bluetooth.Session
-	featureSet    *appfeatures.FeatureSet
+	sessionCfg    scfg.Configuration
+	authorizer    bluetooth.SessionAuthorizer
+	featureSet    atomic.Pointer[appfeatures.FeatureSet]
+	started       atomic.Bool
 	draws         chan struct{}
 	shouldSuspend bool
 
@@ -69,7 +115,30 @@ func (a *appBinder) Session() bluetooth.Session {
 
 // Features returns the current features of the session.
 func (a *appBinder) Features() *appfeatures.FeatureSet {
-	return a.featureSet
+	return a.featureSet.Load()
+}
+
+// RestartSession stops and starts the current session again,
+// for example when the connection to the Bluetooth daemon has been lost.
+func (a *appBinder) RestartSession() error {
+	a.stopSession()
+
+	featureSet, _, err := a.session.Start(a.authorizer, a.sessionCfg)
+	if err != nil {
+		return err
+	}
+
+	a.featureSet.Store(featureSet)
+	a.started.Store(true)
+
+	return nil
+}
+
+// stopSession stops the current session, only if it has been started successfully.
+func (a *appBinder) stopSession() {
+	if a.started.Swap(false) {
+		_ = a.session.Stop()
+	}
 }
 
 // InstantDraw instantly draws to the screen.
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/appfeatures"
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	scfg "github.com/bluetuith-org/bluetooth-classic/api/config"
+	"github.com/bluetuith-org/bluetooth-classic/api/platforminfo"
+)
+
+// fakeSession is a session which counts the number of times it is stopped.
+// Only the functions which are used by the tests are implemented.
+type fakeSession struct {
+	bluetooth.Session
+
+	startErr error
+	stops    int
+}
+
+func (s *fakeSession) Start(bluetooth.SessionAuthorizer, scfg.Configuration) (*appfeatures.FeatureSet, platforminfo.PlatformInfo, error) {
+	if s.startErr != nil {
+		return nil, platforminfo.PlatformInfo{}, s.startErr
+	}
+
+	return &appfeatures.FeatureSet{}, platforminfo.PlatformInfo{}, nil
+}
+
+func (s *fakeSession) Stop() error {
+	s.stops++
+
+	return nil
+}
+
+func TestStopSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		binder   bool
+		startErr error
+		stops    int
+	}{
+		{name: "application not started", stops: 1},
+		{name: "session restarted", binder: true, stops: 2},
+		{name: "session failed to restart", binder: true, startErr: errors.New("no adapter"), stops: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := &fakeSession{startErr: test.startErr}
+			a := &Application{}
+
+			if test.binder {
+				a.binder = &appBinder{session: session}
+				a.binder.started.Store(true)
+
+				if err := a.binder.RestartSession(); !errors.Is(err, test.startErr) {
+					t.Fatalf("unexpected restart error: %v", err)
+				}
+			}
+
+			a.StopSession(session)
+
+			if session.stops != test.stops {
+				t.Fatalf("expected the session to be stopped %d times, got %d", test.stops, session.stops)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package views
+
+import (
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const bluezAdapterIface = "org.bluez.Adapter1"
+
+// showDetailedInfo shows detailed information about the current adapter.
+// The properties are retrieved before the modal is shown on the UI goroutine,
+// so that drawing is not blocked while they are retrieved.
+func (a *adapterView) showDetailedInfo() {
+	adapter, err := a.currentSession().Properties()
+	if err != nil {
+		a.status.ErrorMessage(err)
+		return
+	}
+
+	// The class and modalias of the adapter are not provided by the session,
+	// and are retrieved from the raw adapter properties, if available.
+	class, modalias := "-", "-"
+	if raw, err := getRawAdapterProperties(adapter.UniqueName); err == nil {
+		if value, ok := raw["Class"].Value().(uint32); ok {
+			class = "0x" + strconv.FormatUint(uint64(value), 16)
+		}
+		if value, ok := raw["Modalias"].Value().(string); ok && value != "" {
+			modalias = value
+		}
+	}
+
+	props := [][]string{
+		{"Name", optGetValueString(adapter.Name)},
+		{"Alias", optGetValueString(adapter.Alias)},
+		{"Unique Name", adapter.UniqueName},
+		{"Address", adapter.Address.String()},
+		{"Class", class},
+		{"Modalias", modalias},
+		{"Powered", optYesNo(adapter.Powered)},
+		{"Discoverable", optYesNo(adapter.Discoverable)},
+		{"Pairable", optYesNo(adapter.Pairable)},
+		{"Discovering", optYesNo(adapter.Discovering)},
+	}
+
+	a.app.QueueDraw(func() {
+		a.showInfoModal("adapterinfo", "Adapter Information", "adapter", props, adapter.UUIDs)
+	})
+}
+
+// getRawAdapterProperties returns all the properties of the adapter with the provided name,
+// as published by BlueZ on the system bus.
+func getRawAdapterProperties(adapterName string) (map[string]dbus.Variant, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var props map[string]dbus.Variant
+	if err := conn.Object(bluezDest, dbus.ObjectPath("/org/bluez/"+adapterName)).
+		Call("org.freedesktop.DBus.Properties.GetAll", 0, bluezAdapterIface).
+		Store(&props); err != nil {
+		return nil, err
+	}
+
+	return props, nil
+}
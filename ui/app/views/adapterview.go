@@ -2,11 +2,13 @@ package views
 
 import (
 	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
 	"github.com/bluetuith-org/bluetooth-classic/api/optional"
@@ -14,9 +16,32 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"go.uber.org/atomic"
 
+	"github.com/darkhz/bluetuith/ui/config"
 	"github.com/darkhz/bluetuith/ui/theme"
 )
 
+// noAdapterMessage is displayed in place of the adapter name if no adapter is present.
+const noAdapterMessage = "No Bluetooth adapter found"
+
+// The number of times, and the interval at which an adapter state is checked after it is set.
+const (
+	adapterStateChecks        = 5
+	adapterStateCheckInterval = 200 * time.Millisecond
+)
+
+// adapterRedrawDelay is the duration within which adapter updates are coalesced into a single redraw.
+const adapterRedrawDelay = 100 * time.Millisecond
+
+// adapterRedraw describes the parts of the adapter view which have to be redrawn.
+type adapterRedraw uint8
+
+// The different parts of the adapter view which can be redrawn.
+const (
+	adapterRedrawHeader adapterRedraw = 1 << iota
+	adapterRedrawStatus
+	adapterRedrawChange
+)
+
 // adapterView holds the adapter view, which contains the displays of:
 // - The adapter name on the left-most side of the menubar.
 // - The adapter statuses on the roght-most side of the menubar.
@@ -25,11 +50,32 @@ type adapterView struct {
 	topStatus      *tview.TextView
 	currentAdapter atomic.Pointer[bluetooth.AdapterData]
 
+	stateTimers  map[adapterStateKey]*adapterStateTimer
+	stateTimerMu sync.Mutex
+
+	redraws     adapterRedraw
+	redrawTimer *time.Timer
+	redrawMu    sync.Mutex
+
 	*Views
 }
 
+// adapterStateKey identifies a timed adapter state (for example, "discoverable") of an adapter.
+type adapterStateKey struct {
+	address bluetooth.AdapterAddress
+	state   string
+}
+
+// adapterStateTimer holds the deadline after which a timed adapter state is disabled.
+type adapterStateTimer struct {
+	deadline time.Time
+	cancel   context.CancelFunc
+}
+
 // Initialize initializes the adapter view.
 func (a *adapterView) Initialize() error {
+	a.stateTimers = make(map[adapterStateKey]*adapterStateTimer)
+
 	a.topStatus = tview.NewTextView()
 	a.topStatus.SetRegions(true)
 	a.topStatus.SetDynamicColors(true)
@@ -69,6 +115,12 @@ func (a *adapterView) SetRootView(v *Views) {
 // refreshHeader displays the selected adapter's name and unique name
 // on the menu bar.
 func (a *adapterView) refreshHeader() {
+	if !a.hasAdapter() {
+		a.menu.setHeader("", false)
+		a.topAdapterName.SetText(theme.ColorWrap(theme.ThemeStatusError, noAdapterMessage, "::b"))
+		return
+	}
+
 	props, err := a.currentSession().Properties()
 	if err != nil {
 		a.menu.setHeader(theme.ColorWrap(theme.ThemeAdapter, "(Connect adapter)", "::bu"), true)
@@ -100,6 +152,21 @@ func (a *adapterView) getAdapter() *bluetooth.AdapterData {
 	return a.currentAdapter.Load()
 }
 
+// hasAdapter returns whether an adapter is currently selected.
+// If no adapters are present, an empty adapter is selected.
+func (a *adapterView) hasAdapter() bool {
+	adapter := a.currentAdapter.Load()
+
+	return adapter != nil && !adapter.Address.IsNil()
+}
+
+// isCurrentAdapter returns whether the adapter with the provided address is currently selected.
+func (a *adapterView) isCurrentAdapter(address bluetooth.MacAddress) bool {
+	adapter := a.currentAdapter.Load()
+
+	return adapter != nil && adapter.Address == address
+}
+
 // currentSession wraps a bluetooth session with the current adapter.
 func (a *adapterView) currentSession() bluetooth.Adapter {
 	return a.app.Session().Adapter(a.currentAdapter.Load().AdapterAddress)
@@ -112,10 +179,10 @@ func (a *adapterView) setAdapter(adapter *bluetooth.AdapterData) {
 }
 
 // selectAdapter selects the first available adapter.
+// If no adapters are present, an empty adapter is selected.
 func (a *adapterView) selectAdapter() bool {
 	adapters, err := a.app.Session().Adapters()
-	if err != nil {
-		a.status.ErrorMessage(err)
+	if err != nil || len(adapters) == 0 {
 		a.setAdapter(&bluetooth.AdapterData{})
 
 		return false
@@ -126,6 +193,33 @@ func (a *adapterView) selectAdapter() bool {
 	return true
 }
 
+// refresh re-reads the properties of the current adapter from the session.
+// If the current adapter is no longer present, the first available adapter is selected.
+func (a *adapterView) refresh() bool {
+	adapters, err := a.app.Session().Adapters()
+	if err != nil || len(adapters) == 0 {
+		a.setAdapter(&bluetooth.AdapterData{})
+
+		return false
+	}
+
+	current := a.getAdapter()
+	index := slices.IndexFunc(adapters, func(adapter bluetooth.AdapterData) bool {
+		return adapter.Address == current.Address
+	})
+	if index < 0 {
+		return a.selectAdapter()
+	}
+
+	adapter, err := a.app.Session().Adapter(adapters[index].AdapterAddress).Properties()
+	if err != nil {
+		adapter = adapters[index]
+	}
+	a.setAdapter(&adapter)
+
+	return true
+}
+
 // change launches a popup with a list of adapters.
 // Changing the selection will change the currently selected adapter.
 func (a *adapterView) change() {
@@ -267,60 +361,171 @@ func (a *adapterView) updateTopStatus() {
 		bgColor := theme.ThemeConfig[status.Color]
 
 		region := strings.ToLower(status.Title)
+		if remaining, ok := a.stateTimeoutRemaining(props.AdapterAddress, region); ok {
+			status.Title += " " + formatDuration(uint32(remaining.Milliseconds()))
+		}
+
 		fmt.Fprintf(a.topStatus, "[\"%s\"][%s:%s:b] %s [-:-:-][\"\"] ", region, textColor, bgColor, status.Title)
 	}
 }
 
-// setStates sets the adapter states which were parsed from
-// the "adapter-states" command-line option.
-func (a *adapterView) setStates() {
-	var lock sync.Mutex
+// startStateTimeout starts a countdown for the provided adapter state, which is displayed
+// in the adapter status display. Once the timeout expires, the state is disabled using
+// the provided disable function.
+func (a *adapterView) startStateTimeout(address bluetooth.AdapterAddress, state string, timeout time.Duration, disable func()) {
+	key := adapterStateKey{address, state}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 
-	properties := a.cfg.Values.AdapterStatesMap
-	if len(properties) == 0 {
-		return
+	a.stateTimerMu.Lock()
+	if timer, ok := a.stateTimers[key]; ok {
+		timer.cancel()
 	}
+	a.stateTimers[key] = &adapterStateTimer{deadline: time.Now().Add(timeout), cancel: cancel}
+	a.stateTimerMu.Unlock()
+
+	go func() {
+		t := time.NewTicker(1 * time.Second)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				expired := errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+				a.stateTimerMu.Lock()
+				if timer, ok := a.stateTimers[key]; ok && expired && timer.deadline.Before(time.Now()) {
+					delete(a.stateTimers, key)
+				} else {
+					expired = false
+				}
+				a.stateTimerMu.Unlock()
+
+				if expired {
+					disable()
+				}
+
+				a.app.QueueDraw(func() {
+					a.updateTopStatus()
+				})
 
-	seq, ok := properties["sequence"]
+				return
+
+			case <-t.C:
+				a.app.QueueDraw(func() {
+					a.updateTopStatus()
+				})
+			}
+		}
+	}()
+}
+
+// stopStateTimeout stops the countdown for the provided adapter state.
+func (a *adapterView) stopStateTimeout(address bluetooth.AdapterAddress, state string) {
+	key := adapterStateKey{address, state}
+
+	a.stateTimerMu.Lock()
+	defer a.stateTimerMu.Unlock()
+
+	if timer, ok := a.stateTimers[key]; ok {
+		timer.cancel()
+		delete(a.stateTimers, key)
+	}
+}
+
+// stateTimeoutRemaining returns the remaining time before the provided adapter state is disabled.
+func (a *adapterView) stateTimeoutRemaining(address bluetooth.AdapterAddress, state string) (time.Duration, bool) {
+	a.stateTimerMu.Lock()
+	defer a.stateTimerMu.Unlock()
+
+	timer, ok := a.stateTimers[adapterStateKey{address, state}]
 	if !ok {
-		a.status.InfoMessage("Cannot get adapter states", false)
+		return 0, false
+	}
+
+	return max(time.Until(timer.deadline), 0), true
+}
+
+// setStates sets the adapter states which were specified on application launch.
+// The states are applied one after another in a fixed order, using the action handlers,
+// and the states which could not be set are reported.
+func (a *adapterView) setStates() {
+	properties := a.cfg.Values.AdapterStatesMap
+	if len(properties) == 0 {
 		return
 	}
 
-	sequence := strings.SplitSeq(seq, ",")
-	for property := range sequence {
-		var handler func(set ...string) bool
+	go func() {
+		var failed []string
 
-		state, ok := properties[property]
-		if !ok {
-			a.status.InfoMessage("Cannot set adapter "+property+" state", false)
-			return
+		for _, property := range config.AdapterStates {
+			var handler func(set ...string) bool
+
+			state, ok := properties[property]
+			if !ok {
+				continue
+			}
+
+			switch property {
+			case "powered":
+				handler = a.actions.power
+
+			case "pairable":
+				handler = a.actions.pairable
+
+			case "discoverable":
+				handler = a.actions.discoverable
+
+			case "scan":
+				handler = a.actions.scan
+			}
+
+			handler(state)
+
+			if !a.hasState(property, state == "yes") {
+				failed = append(failed, property+":"+state)
+			}
+		}
+
+		if len(failed) > 0 {
+			a.status.ErrorMessage(fmt.Errorf("cannot set the adapter states: %s", strings.Join(failed, ", ")))
 		}
+	}()
+}
+
+// hasState checks whether the adapter property has the provided state.
+// Since the adapter properties may be updated after a state is set,
+// the property is checked a few times before giving up.
+func (a *adapterView) hasState(property string, state bool) bool {
+	for range adapterStateChecks {
+		props, err := a.currentSession().Properties()
+		if err != nil {
+			return false
+		}
+
+		var value optional.Optional[bool]
 
 		switch property {
 		case "powered":
-			handler = a.actions.power
+			value = props.Powered
 
-		case "scan":
-			handler = a.actions.scan
+		case "pairable":
+			value = props.Pairable
 
 		case "discoverable":
-			handler = a.actions.discoverable
-
-		case "pairable":
-			handler = a.actions.pairable
+			value = props.Discoverable
 
-		default:
-			continue
+		case "scan":
+			value = props.Discovering
 		}
 
-		go func() {
-			lock.Lock()
-			defer lock.Unlock()
+		if current, ok := value.Get(); ok && current == state {
+			return true
+		}
 
-			handler(state)
-		}()
+		time.Sleep(adapterStateCheckInterval)
 	}
+
+	return false
 }
 
 // event handles adapter-specific events.
@@ -331,26 +536,49 @@ func (a *adapterView) event() {
 		return
 	}
 
+	a.defaults.init()
+
 	for {
 		select {
 		case <-adapterSub.Done:
 			return
 
 		case <-adapterSub.AddedEvents:
-			address := a.currentAdapter.Load().Address
-			if address.IsNil() && !a.selectAdapter() {
+			if !a.hasAdapter() {
+				if !a.selectAdapter() {
+					continue
+				}
+
+				a.defaults.init()
+				a.status.InfoMessage("Bluetooth adapter "+getAdapterDisplayName(*a.getAdapter())+" was added", false)
+
+				go a.app.QueueDraw(func() {
+					a.updateTopStatus()
+					a.device.list()
+				})
+
 				continue
 			}
 
-			go a.app.QueueDraw(func() {
-				a.change()
-			})
+			a.scheduleRedraw(adapterRedrawChange)
 
 		case ev := <-adapterSub.UpdatedEvents:
+			go a.defaults.check(ev)
+
+			if discoverable, ok := ev.Discoverable.Get(); ok && !discoverable {
+				a.stopStateTimeout(ev.AdapterAddress, "discoverable")
+			}
+			if pairable, ok := ev.Pairable.Get(); ok && !pairable {
+				a.stopStateTimeout(ev.AdapterAddress, "pairable")
+			}
+
 			if ev.Address == a.currentAdapter.Load().Address {
-				go a.app.QueueDraw(func() {
-					a.updateTopStatus()
-				})
+				redraw := adapterRedrawStatus
+				if !ev.Alias.IsZero() || !ev.Name.IsZero() {
+					redraw |= adapterRedrawHeader
+				}
+
+				a.scheduleRedraw(redraw)
 			}
 
 		case <-adapterSub.RemovedEvents:
@@ -361,6 +589,7 @@ func (a *adapterView) event() {
 				})
 			} else {
 				go a.app.QueueDraw(func() {
+					a.updateTopStatus()
 					a.device.clear()
 				})
 			}
@@ -368,8 +597,46 @@ func (a *adapterView) event() {
 	}
 }
 
+// scheduleRedraw marks the parts of the adapter view to be redrawn. Adapter properties often change in
+// quick succession (for example, while scanning), so all the redraws which are scheduled within a short
+// duration are coalesced into a single redraw. Since the redraw reads the current adapter properties,
+// the final state is always rendered.
+func (a *adapterView) scheduleRedraw(redraw adapterRedraw) {
+	a.redrawMu.Lock()
+	defer a.redrawMu.Unlock()
+
+	a.redraws |= redraw
+	if a.redrawTimer == nil {
+		a.redrawTimer = time.AfterFunc(adapterRedrawDelay, a.redraw)
+	}
+}
+
+// redraw redraws the parts of the adapter view which were scheduled to be redrawn.
+func (a *adapterView) redraw() {
+	a.redrawMu.Lock()
+	redraws := a.redraws
+	a.redraws, a.redrawTimer = 0, nil
+	a.redrawMu.Unlock()
+
+	a.app.QueueDraw(func() {
+		if redraws&adapterRedrawHeader != 0 {
+			a.refreshHeader()
+		}
+		if redraws&adapterRedrawStatus != 0 {
+			a.updateTopStatus()
+		}
+		if redraws&adapterRedrawChange != 0 {
+			a.change()
+		}
+	})
+}
+
 // getAdapterDisplayName returns the display name of the adapter.
 func getAdapterDisplayName(adapterData bluetooth.AdapterData) string {
+	if alias, ok := adapterData.Alias.Get(); ok && alias != "" {
+		return alias
+	}
+
 	if name, ok := adapterData.Name.Get(); ok {
 		return name
 	}
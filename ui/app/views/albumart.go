@@ -0,0 +1,425 @@
+package views
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/godbus/dbus/v5"
+	"go.uber.org/atomic"
+
+	"github.com/darkhz/bluetuith/ui/config"
+)
+
+const (
+	bluezMediaPlayerIface = "org.bluez.MediaPlayer1"
+
+	obexDest          = "org.bluez.obex"
+	obexClientIface   = "org.bluez.obex.Client1"
+	obexImageIface    = "org.bluez.obex.Image1"
+	obexTransferIface = "org.bluez.obex.Transfer1"
+)
+
+const (
+	// albumArtWidth is the width of the album art in cells.
+	albumArtWidth = 16
+
+	// albumArtKittyID is the identifier of the image which is displayed
+	// using the kitty graphics protocol.
+	albumArtKittyID = 7150
+
+	// albumArtAttempts is the number of times the cover art of a track is looked up,
+	// since the phone may only provide the image handle after the track has changed.
+	albumArtAttempts = 5
+
+	// albumArtTimeout is the maximum amount of time to wait for the cover art to be downloaded.
+	albumArtTimeout = 10 * time.Second
+)
+
+// graphicsProtocol describes a protocol to display images within the terminal.
+type graphicsProtocol int
+
+// The supported graphics protocols. If the terminal does not support the kitty
+// graphics protocol, the image is drawn with colored half-block characters.
+const (
+	graphicsText graphicsProtocol = iota
+	graphicsKitty
+)
+
+// albumArt holds the album art of the playing track, and the region of the screen it is displayed in.
+// It is only accessed from the UI goroutine, except for the generation, which is used to discard
+// album art that was looked up for a previous track.
+type albumArt struct {
+	protocol graphicsProtocol
+	parent   *tview.Flex
+	view     *tview.Box
+	image    image.Image
+	handle   string
+
+	width          int
+	drawn, changed bool
+	region         image.Rectangle
+
+	generation atomic.Uint64
+}
+
+// detectGraphicsProtocol returns the graphics protocol supported by the terminal.
+// Since querying the terminal would interfere with the input handling of the application,
+// the protocol is determined from the environment variables set by the terminal.
+// Terminal multiplexers do not pass the images through by default, so the text fallback is used within them.
+func detectGraphicsProtocol() graphicsProtocol {
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return graphicsText
+
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty",
+		program == "ghostty", program == "WezTerm":
+		return graphicsKitty
+	}
+
+	return graphicsText
+}
+
+// getAlbumArt returns the cover art of the track that is playing on the device, along with its image handle.
+// The image handle is read from the track metadata of the "org.bluez.MediaPlayer1" interface, and the thumbnail
+// of the image is downloaded using the Basic Imaging Profile of the OBEX daemon. If the track has no cover art,
+// a nil image is returned.
+func getAlbumArt(adapterName string, address bluetooth.MacAddress) (image.Image, string, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := conn.Object(bluezDest, "/").
+		Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).
+		Store(&objects); err != nil {
+		return nil, "", err
+	}
+
+	devicePath := bluezDevicePath(adapterName, address)
+
+	var handle string
+	var port uint16
+
+	for _, ifaces := range objects {
+		props, ok := ifaces[bluezMediaPlayerIface]
+		if !ok {
+			continue
+		}
+
+		if device, ok := props["Device"].Value().(dbus.ObjectPath); !ok || device != devicePath {
+			continue
+		}
+
+		track, _ := props["Track"].Value().(map[string]dbus.Variant)
+		handle, _ = track["ImgHandle"].Value().(string)
+		port, _ = props["ObexPort"].Value().(uint16)
+
+		if handle != "" {
+			break
+		}
+	}
+
+	switch {
+	case handle == "":
+		return nil, "", nil
+
+	case port == 0:
+		return nil, "", errors.New("the device does not provide the cover art")
+	}
+
+	path, err := downloadAlbumArt(address, port, handle)
+	if err != nil {
+		return nil, "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return img, handle, nil
+}
+
+// downloadAlbumArt downloads the thumbnail of the image with the provided handle to the cache
+// directory, and returns the path to the downloaded file. Previously downloaded thumbnails are reused.
+func downloadAlbumArt(address bluetooth.MacAddress, port uint16, handle string) (string, error) {
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(cacheDir, "cover-"+strings.ReplaceAll(address.String(), ":", "")+"-"+filepath.Base(handle)+".jpg")
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+
+	conn, err := bluezSessionBus()
+	if err != nil {
+		return "", err
+	}
+
+	client := conn.Object(obexDest, "/org/bluez/obex")
+
+	var session dbus.ObjectPath
+	if err := client.Call(obexClientIface+".CreateSession", 0, address.String(), map[string]dbus.Variant{
+		"Target": dbus.MakeVariant("bip-avrcp"),
+		"PSM":    dbus.MakeVariant(port),
+	}).Store(&session); err != nil {
+		return "", err
+	}
+	defer client.Call(obexClientIface+".RemoveSession", 0, session)
+
+	var transfer dbus.ObjectPath
+	var props map[string]dbus.Variant
+	if err := conn.Object(obexDest, session).
+		Call(obexImageIface+".GetThumbnail", 0, target, handle).
+		Store(&transfer, &props); err != nil {
+		return "", err
+	}
+
+	// The transfer object is removed once the transfer has completed,
+	// so the status cannot always be read.
+	for deadline := time.Now().Add(albumArtTimeout); time.Now().Before(deadline); {
+		time.Sleep(100 * time.Millisecond)
+
+		status, err := conn.Object(obexDest, transfer).GetProperty(obexTransferIface + ".Status")
+		if err != nil {
+			break
+		}
+
+		switch status.Value() {
+		case "complete":
+			return target, nil
+
+		case "error":
+			os.Remove(target)
+			return "", errors.New("the cover art could not be downloaded")
+		}
+	}
+
+	if info, err := os.Stat(target); err != nil || info.Size() == 0 {
+		os.Remove(target)
+		return "", errors.New("the cover art could not be downloaded")
+	}
+
+	return target, nil
+}
+
+// updateAlbumArt looks up the cover art of the playing track, and displays it in the player.
+func (m *mediaPlayer) updateAlbumArt() {
+	generation := m.art.generation.Inc()
+
+	adapter, err := m.app.Session().Adapter(m.address.AdapterAddress()).Properties()
+	if err != nil {
+		return
+	}
+
+	var img image.Image
+	var handle string
+
+	for range albumArtAttempts {
+		img, handle, err = getAlbumArt(adapter.UniqueName, m.address.Address)
+		if img != nil || err != nil || m.art.generation.Load() != generation {
+			break
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	m.app.QueueDraw(func() {
+		if m.art.view == nil || m.art.generation.Load() != generation || (img != nil && handle == m.art.handle) {
+			return
+		}
+
+		m.art.image, m.art.handle = img, handle
+		m.art.changed = true
+
+		width := 0
+		if img != nil {
+			width = albumArtWidth + 2
+		}
+
+		m.art.resize(width)
+	})
+}
+
+// resize sets the width of the album art within the player.
+func (a *albumArt) resize(width int) {
+	a.width = width
+	if a.parent != nil {
+		a.parent.ResizeItem(a.view, width, 0)
+	}
+}
+
+// drawTextAlbumArt draws the album art within its view using colored half-block characters,
+// where each cell holds two vertically adjacent pixels of the scaled image.
+func (m *mediaPlayer) drawTextAlbumArt(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+	img := m.art.image
+	if img == nil || m.art.view == nil {
+		return x, y, width, height
+	}
+
+	left, top, innerWidth, innerHeight := m.art.view.GetInnerRect()
+
+	columns, rows := fitImage(img, image.Rect(left, top, left+innerWidth, top+innerHeight), 1, 2)
+	if columns == 0 {
+		return x, y, width, height
+	}
+
+	bounds := img.Bounds()
+
+	pixel := func(column, row int) tcell.Color {
+		r, g, b, _ := img.At(bounds.Min.X+column*bounds.Dx()/columns, bounds.Min.Y+row*bounds.Dy()/(rows*2)).RGBA()
+		return tcell.NewRGBColor(int32(r>>8), int32(g>>8), int32(b>>8))
+	}
+
+	for row := range rows {
+		for column := range columns {
+			style := tcell.StyleDefault.Foreground(pixel(column, row*2)).Background(pixel(column, row*2+1))
+			screen.SetContent(left+column, top+row, '▀', nil, style)
+		}
+	}
+
+	return x, y, width, height
+}
+
+// drawAlbumArt draws the album art over its region of the screen using the kitty graphics protocol,
+// after the screen has been drawn. The region is locked, so that the screen does not draw over the image.
+// If the album art has been removed or moved, the previously drawn image is cleared first.
+func (m *mediaPlayer) drawAlbumArt(screen tcell.Screen) {
+	art := &m.art
+	if art.protocol != graphicsKitty {
+		return
+	}
+
+	var region image.Rectangle
+	if art.view != nil && art.image != nil {
+		x, y, width, height := art.view.GetInnerRect()
+		region = image.Rect(x, y, x+width, y+height)
+	}
+
+	if art.drawn && region == art.region && !art.changed {
+		return
+	}
+
+	tty, ok := screen.Tty()
+	if !ok {
+		return
+	}
+
+	if art.drawn {
+		clearImage(screen, tty, art.region)
+		art.drawn = false
+	}
+	art.changed = false
+
+	if region.Empty() {
+		return
+	}
+
+	size, err := tty.WindowSize()
+	if err != nil {
+		return
+	}
+
+	cellWidth, cellHeight := size.CellDimensions()
+	if cellWidth == 0 || cellHeight == 0 {
+		cellWidth, cellHeight = 8, 16
+	}
+
+	data := encodeKitty(art.image, region, cellWidth, cellHeight)
+	if data == nil {
+		return
+	}
+
+	screen.LockRegion(region.Min.X, region.Min.Y, region.Dx(), region.Dy(), true)
+	fmt.Fprintf(tty, "\x1b7\x1b[%d;%dH%s\x1b8", region.Min.Y+1, region.Min.X+1, data)
+
+	art.drawn, art.region = true, region
+}
+
+// clearImage removes the image displayed within the region of the screen.
+// The region is unlocked, so that the screen draws over the image.
+func clearImage(screen tcell.Screen, w io.Writer, region image.Rectangle) {
+	fmt.Fprintf(w, "\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", albumArtKittyID)
+	screen.LockRegion(region.Min.X, region.Min.Y, region.Dx(), region.Dy(), false)
+}
+
+// fitImage returns the size of the image in cells, so that it fits within the region while
+// retaining its aspect ratio.
+func fitImage(img image.Image, region image.Rectangle, cellWidth, cellHeight int) (int, int) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0, 0
+	}
+
+	columns, rows := region.Dx(), region.Dy()
+
+	// Compare the aspect ratios of the image and the region in pixels.
+	if bounds.Dx()*rows*cellHeight > bounds.Dy()*columns*cellWidth {
+		rows = max(1, columns*cellWidth*bounds.Dy()/(bounds.Dx()*cellHeight))
+	} else {
+		columns = max(1, rows*cellHeight*bounds.Dx()/(bounds.Dy()*cellWidth))
+	}
+
+	return columns, rows
+}
+
+// encodeKitty encodes the image using the kitty graphics protocol. The terminal scales the image
+// to the size of the region, and the responses of the terminal are suppressed.
+func encodeKitty(img image.Image, region image.Rectangle, cellWidth, cellHeight int) []byte {
+	const chunkSize = 4096
+
+	columns, rows := fitImage(img, region, cellWidth, cellHeight)
+	if columns == 0 {
+		return nil
+	}
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return nil
+	}
+
+	payload := base64.StdEncoding.EncodeToString(encoded.Bytes())
+
+	var buf bytes.Buffer
+	for i := 0; i < len(payload); i += chunkSize {
+		chunk := payload[i:min(i+chunkSize, len(payload))]
+
+		more := 0
+		if i+chunkSize < len(payload) {
+			more = 1
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&buf, "\x1b_Gf=100,a=T,i=%d,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", albumArtKittyID, columns, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&buf, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,438 @@
+package views
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// audioBackendTimeout is the time after which a command sent to the sound server is cancelled.
+const audioBackendTimeout = 5 * time.Second
+
+// audioBackend describes a sound server, which manages the audio profiles of devices,
+// and the default audio output (sink) of the system.
+type audioBackend interface {
+	audioProfiles(address bluetooth.DeviceAddress) ([]bluetooth.AudioProfile, error)
+	setAudioProfile(address bluetooth.DeviceAddress, profile bluetooth.AudioProfile) error
+	defaultSink() (bluetooth.MacAddress, error)
+	setDefaultSink(address bluetooth.DeviceAddress) error
+}
+
+// newAudioBackend returns the audio backend with the provided name.
+// If the name is "auto", the available sound servers are tried in order of preference,
+// and if no sound server is available, nil is returned.
+func newAudioBackend(v *Views, name string) audioBackend {
+	pulseaudio := &pulseAudioBackend{v}
+	pipewire := &pipeWireBackend{}
+
+	switch name {
+	case "pulseaudio":
+		return pulseaudio
+
+	case "pipewire":
+		return pipewire
+	}
+
+	var backends autoAudioBackend
+	if pulseaudio.available() {
+		backends = append(backends, pulseaudio)
+	}
+	if pipewire.available() {
+		backends = append(backends, pipewire)
+	}
+
+	switch len(backends) {
+	case 0:
+		return nil
+
+	case 1:
+		return backends[0]
+	}
+
+	return backends
+}
+
+// autoAudioBackend holds a list of audio backends, which are tried in order until one succeeds.
+type autoAudioBackend []audioBackend
+
+// audioProfiles returns the audio profiles of the device from the first backend which provides them.
+func (a autoAudioBackend) audioProfiles(address bluetooth.DeviceAddress) ([]bluetooth.AudioProfile, error) {
+	var errs []error
+
+	for _, backend := range a {
+		profiles, err := backend.audioProfiles(address)
+		if err == nil {
+			return profiles, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// setAudioProfile sets the audio profile of the device using the first backend which succeeds.
+func (a autoAudioBackend) setAudioProfile(address bluetooth.DeviceAddress, profile bluetooth.AudioProfile) error {
+	var errs []error
+
+	for _, backend := range a {
+		err := backend.setAudioProfile(address, profile)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// defaultSink returns the address of the device which is the default sink, from the first backend which provides it.
+func (a autoAudioBackend) defaultSink() (bluetooth.MacAddress, error) {
+	var errs []error
+
+	for _, backend := range a {
+		address, err := backend.defaultSink()
+		if err == nil {
+			return address, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return bluetooth.MacAddress{}, errors.Join(errs...)
+}
+
+// setDefaultSink sets the device as the default sink using the first backend which succeeds.
+func (a autoAudioBackend) setDefaultSink(address bluetooth.DeviceAddress) error {
+	var errs []error
+
+	for _, backend := range a {
+		err := backend.setDefaultSink(address)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// pulseAudioBackend manages audio profiles using the PulseAudio client of the session,
+// and the default sink using the 'pactl' command-line tool.
+type pulseAudioBackend struct {
+	v *Views
+}
+
+// available returns whether a PulseAudio server (or a compatible server, like pipewire-pulse) is running.
+func (p *pulseAudioBackend) available() bool {
+	return os.Getenv("PULSE_SERVER") != "" || runtimeSocketExists(filepath.Join("pulse", "native"))
+}
+
+// audioProfiles returns the audio profiles of the device.
+func (p *pulseAudioBackend) audioProfiles(address bluetooth.DeviceAddress) ([]bluetooth.AudioProfile, error) {
+	return p.v.app.Session().MediaPlayer(address).AudioProfiles()
+}
+
+// setAudioProfile sets the audio profile of the device.
+func (p *pulseAudioBackend) setAudioProfile(address bluetooth.DeviceAddress, profile bluetooth.AudioProfile) error {
+	return p.v.app.Session().MediaPlayer(address).SetAudioProfile(profile)
+}
+
+// defaultSink returns the address of the device which is the default sink.
+// If the default sink is not a Bluetooth device, an empty address is returned.
+func (p *pulseAudioBackend) defaultSink() (bluetooth.MacAddress, error) {
+	output, err := runAudioCommand("pactl", "info")
+	if err != nil {
+		return bluetooth.MacAddress{}, err
+	}
+
+	for line := range strings.Lines(output) {
+		if name, ok := strings.CutPrefix(line, "Default Sink:"); ok {
+			address, _ := bluezSinkAddress(strings.TrimSpace(name))
+			return address, nil
+		}
+	}
+
+	return bluetooth.MacAddress{}, nil
+}
+
+// setDefaultSink sets the sink of the device as the default sink.
+func (p *pulseAudioBackend) setDefaultSink(address bluetooth.DeviceAddress) error {
+	output, err := runAudioCommand("pactl", "list", "short", "sinks")
+	if err != nil {
+		return err
+	}
+
+	for line := range strings.Lines(output) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		if sinkAddress, ok := bluezSinkAddress(fields[1]); ok && sinkAddress == address.Address {
+			_, err := runAudioCommand("pactl", "set-default-sink", fields[1])
+			return err
+		}
+	}
+
+	return errors.New("cannot find an audio output for " + address.Address.String())
+}
+
+// pipeWireBackend manages audio profiles and the default sink using the PipeWire command-line tools.
+type pipeWireBackend struct{}
+
+// pipeWireObject describes a PipeWire device, node or metadata object, as printed by 'pw-dump'.
+type pipeWireObject struct {
+	ID       uint32         `json:"id"`
+	Type     string         `json:"type"`
+	Props    map[string]any `json:"props"`
+	Metadata []struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	} `json:"metadata"`
+	Info struct {
+		Props  map[string]any `json:"props"`
+		Params struct {
+			EnumProfile []pipeWireProfile `json:"EnumProfile"`
+			Profile     []pipeWireProfile `json:"Profile"`
+		} `json:"params"`
+	} `json:"info"`
+}
+
+// pipeWireProfile describes a profile of a PipeWire device object.
+type pipeWireProfile struct {
+	Index       uint32 `json:"index"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Available   string `json:"available"`
+}
+
+// available returns whether the PipeWire tools are installed and a PipeWire server is running.
+func (p *pipeWireBackend) available() bool {
+	for _, command := range []string{"pw-dump", "pw-cli"} {
+		if _, err := exec.LookPath(command); err != nil {
+			return false
+		}
+	}
+
+	remote := os.Getenv("PIPEWIRE_REMOTE")
+	if remote == "" {
+		remote = "pipewire-0"
+	}
+
+	return runtimeSocketExists(remote)
+}
+
+// audioProfiles returns the audio profiles of the device.
+func (p *pipeWireBackend) audioProfiles(address bluetooth.DeviceAddress) ([]bluetooth.AudioProfile, error) {
+	device, err := p.device(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var active uint32
+	var hasActive bool
+	if len(device.Info.Params.Profile) > 0 {
+		active, hasActive = device.Info.Params.Profile[0].Index, true
+	}
+
+	profiles := make([]bluetooth.AudioProfile, 0, len(device.Info.Params.EnumProfile))
+	for _, profile := range device.Info.Params.EnumProfile {
+		if profile.Available == "no" {
+			continue
+		}
+
+		profiles = append(profiles, bluetooth.AudioProfile{
+			Name:        profile.Name,
+			Description: profile.Description,
+			Index:       profile.Index,
+			Active:      hasActive && profile.Index == active,
+		})
+	}
+
+	return profiles, nil
+}
+
+// setAudioProfile sets the audio profile of the device.
+func (p *pipeWireBackend) setAudioProfile(address bluetooth.DeviceAddress, profile bluetooth.AudioProfile) error {
+	device, err := p.device(address)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), audioBackendTimeout)
+	defer cancel()
+
+	param := "{ index: " + strconv.FormatUint(uint64(profile.Index), 10) + ", save: true }"
+	if output, err := exec.CommandContext(ctx, "pw-cli", "set-param", strconv.FormatUint(uint64(device.ID), 10), "Profile", param).CombinedOutput(); err != nil {
+		return fmt.Errorf("cannot set audio profile of device: %w: %s", err, output)
+	}
+
+	return nil
+}
+
+// defaultSink returns the address of the device which is the default sink.
+// If the default sink is not a Bluetooth device, an empty address is returned.
+func (p *pipeWireBackend) defaultSink() (bluetooth.MacAddress, error) {
+	objects, err := p.objects()
+	if err != nil {
+		return bluetooth.MacAddress{}, fmt.Errorf("cannot fetch the default audio output: %w", err)
+	}
+
+	var sink string
+	for _, object := range objects {
+		if object.Type != "PipeWire:Interface:Metadata" || object.Props["metadata.name"] != "default" {
+			continue
+		}
+
+		for _, metadata := range object.Metadata {
+			if metadata.Key != "default.audio.sink" {
+				continue
+			}
+
+			var value struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(metadata.Value, &value); err == nil {
+				sink = value.Name
+			}
+		}
+	}
+
+	for _, object := range objects {
+		if object.Type != "PipeWire:Interface:Node" || sink == "" || object.Info.Props["node.name"] != sink {
+			continue
+		}
+
+		if value, ok := object.Info.Props["api.bluez5.address"].(string); ok {
+			address, err := bluetooth.ParseMAC(value)
+			if err == nil {
+				return address, nil
+			}
+		}
+	}
+
+	return bluetooth.MacAddress{}, nil
+}
+
+// setDefaultSink sets the sink node of the device as the default sink.
+func (p *pipeWireBackend) setDefaultSink(address bluetooth.DeviceAddress) error {
+	objects, err := p.objects()
+	if err != nil {
+		return fmt.Errorf("cannot set the default audio output: %w", err)
+	}
+
+	for _, object := range objects {
+		if object.Type != "PipeWire:Interface:Node" || object.Info.Props["media.class"] != "Audio/Sink" ||
+			object.Info.Props["api.bluez5.address"] != address.Address.String() {
+			continue
+		}
+
+		name, ok := object.Info.Props["node.name"].(string)
+		if !ok {
+			continue
+		}
+
+		value, err := json.Marshal(map[string]string{"name": name})
+		if err != nil {
+			return err
+		}
+
+		_, err = runAudioCommand("pw-metadata", "-n", "default", "0", "default.configured.audio.sink", string(value), "Spa:String:JSON")
+
+		return err
+	}
+
+	return errors.New("cannot find an audio output for " + address.Address.String())
+}
+
+// objects returns all the objects of the PipeWire server.
+func (p *pipeWireBackend) objects() ([]pipeWireObject, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), audioBackendTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "pw-dump").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []pipeWireObject
+	if err := json.Unmarshal(output, &objects); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// device returns the PipeWire device object of the Bluetooth device.
+func (p *pipeWireBackend) device(address bluetooth.DeviceAddress) (pipeWireObject, error) {
+	objects, err := p.objects()
+	if err != nil {
+		return pipeWireObject{}, fmt.Errorf("cannot fetch audio profiles of device: %w", err)
+	}
+
+	for _, object := range objects {
+		if object.Type != "PipeWire:Interface:Device" || object.Info.Props["device.api"] != "bluez5" {
+			continue
+		}
+
+		if object.Info.Props["api.bluez5.address"] == address.Address.String() {
+			return object, nil
+		}
+	}
+
+	return pipeWireObject{}, errors.New("cannot find an audio device for " + address.Address.String())
+}
+
+// runAudioCommand runs the sound server command with the provided arguments, and returns its output.
+func runAudioCommand(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), audioBackendTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w: %s", name, args[0], err, strings.TrimSpace(string(output)))
+	}
+
+	return string(output), nil
+}
+
+// bluezSinkAddress returns the address of the device from the name of a Bluetooth sink,
+// for example "bluez_output.AA_BB_CC_DD_EE_FF.1" or "bluez_sink.AA_BB_CC_DD_EE_FF.a2dp_sink".
+func bluezSinkAddress(name string) (bluetooth.MacAddress, bool) {
+	if !strings.HasPrefix(name, "bluez_") {
+		return bluetooth.MacAddress{}, false
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return bluetooth.MacAddress{}, false
+	}
+
+	address, err := bluetooth.ParseMAC(strings.ReplaceAll(parts[1], "_", ":"))
+
+	return address, err == nil
+}
+
+// runtimeSocketExists returns whether a socket with the provided name exists in the user's runtime directory.
+func runtimeSocketExists(name string) bool {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return false
+	}
+
+	info, err := os.Stat(filepath.Join(runtimeDir, name))
+
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
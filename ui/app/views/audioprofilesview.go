@@ -2,19 +2,60 @@ package views
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/atomic"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/bluetuith/ui/btdevice"
 	"github.com/darkhz/bluetuith/ui/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	audioProfileChecks        = 3
+	audioProfileCheckInterval = time.Second
+
+	// audioDefaultSinkInterval is the interval at which the default sink is checked,
+	// while any audio devices are connected.
+	audioDefaultSinkInterval = 5 * time.Second
+)
+
+// audioMode describes a set of audio profiles which serve the same purpose.
+type audioMode struct {
+	name     string
+	prefixes []string
+}
+
+var (
+	// audioModeHighQuality describes the high quality (A2DP sink) audio profiles.
+	audioModeHighQuality = audioMode{
+		name:     "high quality",
+		prefixes: []string{"a2dp_sink", "a2dp"},
+	}
+
+	// audioModeHeadset describes the headset (HFP/HSP) audio profiles.
+	audioModeHeadset = audioMode{
+		name:     "headset",
+		prefixes: []string{"headset_head_unit", "handsfree_head_unit", "headset", "handsfree"},
+	}
 )
 
 // audioProfilesView holds the audio profiles viewer.
 type audioProfilesView struct {
 	isSupported atomic.Bool
+	backend     audioBackend
+
+	active      map[bluetooth.MacAddress]string
+	defaultSink bluetooth.MacAddress
+	mu          sync.Mutex
 
 	*Views
 }
@@ -27,7 +68,17 @@ type audioDevice struct {
 
 // Initialize initializes the audio profiles viewer.
 func (a *audioProfilesView) Initialize() error {
+	a.backend = newAudioBackend(a.Views, a.cfg.Values.AudioBackend)
+	if a.backend == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	a.active = make(map[bluetooth.MacAddress]string)
+	a.mu.Unlock()
+
 	a.isSupported.Store(true)
+	go a.watchDefaultSink()
 
 	return nil
 }
@@ -49,7 +100,7 @@ func (a *audioProfilesView) audioProfiles() {
 		return
 	}
 
-	profiles, err := a.app.Session().MediaPlayer(device.DeviceAddress).AudioProfiles()
+	profiles, err := a.backend.audioProfiles(device.DeviceAddress)
 	if err != nil {
 		a.status.ErrorMessage(err)
 		return
@@ -118,12 +169,75 @@ func (a *audioProfilesView) setProfile(profileMenu *tview.Table, row, _ int) {
 		return
 	}
 
-	if err := a.app.Session().MediaPlayer(device.address).SetAudioProfile(device.profile); err != nil {
+	if err := a.backend.setAudioProfile(device.address, device.profile); err != nil {
 		a.status.ErrorMessage(err)
 		return
 	}
 
 	a.markActiveProfile(profileMenu, row)
+	a.setActiveProfile(device.address, device.profile)
+
+	if err := a.savePreferredProfile(device.address.Address, device.profile.Name); err != nil {
+		a.status.ErrorMessage(err)
+	}
+}
+
+// switchMode switches the selected device to the first audio profile which matches the mode.
+// If the active profile already matches the mode, the profile is not changed.
+func (a *audioProfilesView) switchMode(mode audioMode) {
+	if !a.isSupported.Load() {
+		a.status.ErrorMessage(errors.New("this operation is not supported"))
+		return
+	}
+
+	device := a.device.getSelection(true)
+	if device.IsNil() {
+		return
+	}
+
+	name := btdevice.DisplayName(device.DeviceEventData)
+
+	profiles, err := a.backend.audioProfiles(device.DeviceAddress)
+	if err != nil {
+		a.status.ErrorMessage(err)
+		return
+	}
+
+	var (
+		selected bluetooth.AudioProfile
+		found    bool
+	)
+
+	for _, prefix := range mode.prefixes {
+		for _, profile := range profiles {
+			if !strings.HasPrefix(strings.ReplaceAll(profile.Name, "-", "_"), prefix) {
+				continue
+			}
+
+			if profile.Active {
+				a.setActiveProfile(device.DeviceAddress, profile)
+				a.status.InfoMessage(name+" is already in "+mode.name+" mode ("+profile.Description+")", false)
+				return
+			}
+
+			if !found {
+				selected, found = profile, true
+			}
+		}
+	}
+
+	if !found {
+		a.status.ErrorMessage(fmt.Errorf("%s does not have a %s audio profile", name, mode.name))
+		return
+	}
+
+	if err := a.backend.setAudioProfile(device.DeviceAddress, selected); err != nil {
+		a.status.ErrorMessage(err)
+		return
+	}
+
+	a.setActiveProfile(device.DeviceAddress, selected)
+	a.status.InfoMessage("Switched "+name+" to "+mode.name+" mode ("+selected.Description+")", false)
 }
 
 // markActiveProfile marks the active profile in the profiles list.
@@ -147,3 +261,260 @@ func (a *audioProfilesView) markActiveProfile(profileMenu *tview.Table, index ..
 		)
 	}
 }
+
+// activeProfileTag returns a short name for the active audio profile of the device,
+// if it is known.
+func (a *audioProfilesView) activeProfileTag(address bluetooth.MacAddress) string {
+	if !a.isSupported.Load() {
+		return ""
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.active[address]
+}
+
+// updateActiveProfile retrieves the active audio profile of the device, and redraws the device row.
+// If applyPreferred is set and a preferred audio profile is stored for the device, the preferred
+// profile is set instead. This should only be set when the device has just connected, so that a
+// profile which was changed afterwards is not overridden. Profiles are only retrieved for connected devices which advertise audio
+// services, since each retrieval queries the sound server. The sound server may take a while to set up
+// the profiles of a newly connected device, so the retrieval is retried a few times.
+func (a *audioProfilesView) updateActiveProfile(address bluetooth.DeviceAddress, applyPreferred bool) {
+	if !a.isSupported.Load() {
+		return
+	}
+
+	device, err := a.app.Session().Device(address).Properties()
+	if err != nil {
+		return
+	}
+
+	if connected, ok := device.Connected.Get(); !ok || !connected || !hasAudioServices(device.UUIDs) {
+		a.clearActiveProfile(address.Address)
+		return
+	}
+
+	preferred, hasPreferred := a.cfg.Values.PreferredAudioProfiles[address.Address.String()]
+	hasPreferred = hasPreferred && applyPreferred
+
+	for range audioProfileChecks {
+		profiles, err := a.backend.audioProfiles(address)
+		if err == nil {
+			if hasPreferred && a.applyPreferredProfile(device, profiles, preferred) {
+				return
+			}
+
+			for _, profile := range profiles {
+				if profile.Active && !hasPreferred {
+					a.setActiveProfile(address, profile)
+					return
+				}
+			}
+		}
+
+		time.Sleep(audioProfileCheckInterval)
+	}
+
+	if hasPreferred {
+		a.status.ErrorMessage(fmt.Errorf("cannot set the preferred audio profile %s for %s", preferred, btdevice.DisplayName(device.DeviceEventData)))
+		a.updateActiveProfile(address, false)
+	}
+}
+
+// applyPreferredProfile sets the preferred audio profile of the device, if it is
+// present within the provided profiles and is not already active.
+func (a *audioProfilesView) applyPreferredProfile(device bluetooth.DeviceData, profiles []bluetooth.AudioProfile, preferred string) bool {
+	index := slices.IndexFunc(profiles, func(profile bluetooth.AudioProfile) bool {
+		return profile.Name == preferred
+	})
+	if index < 0 {
+		return false
+	}
+
+	profile := profiles[index]
+	if !profile.Active {
+		if err := a.backend.setAudioProfile(device.DeviceAddress, profile); err != nil {
+			return false
+		}
+
+		a.status.InfoMessage("Set the preferred audio profile of "+btdevice.DisplayName(device.DeviceEventData)+" to "+profile.Description, false)
+	}
+
+	a.setActiveProfile(device.DeviceAddress, profile)
+
+	return true
+}
+
+// savePreferredProfile stores the audio profile as the preferred audio profile of the device
+// in the configuration. The preferred profile is set each time the device connects.
+func (a *audioProfilesView) savePreferredProfile(address bluetooth.MacAddress, profile string) error {
+	if a.cfg.Values.PreferredAudioProfiles[address.String()] == profile {
+		return nil
+	}
+
+	profiles := maps.Clone(a.cfg.Values.PreferredAudioProfiles)
+	if profiles == nil {
+		profiles = make(map[string]string)
+	}
+	profiles[address.String()] = profile
+
+	if err := a.cfg.Save("preferred-audio-profiles", profiles); err != nil {
+		return err
+	}
+
+	a.cfg.Values.PreferredAudioProfiles = profiles
+
+	return nil
+}
+
+// hasActiveProfile returns whether the audio profile of the device is known.
+func (a *audioProfilesView) hasActiveProfile(address bluetooth.MacAddress) bool {
+	if !a.isSupported.Load() {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.active[address]
+
+	return ok
+}
+
+// setActiveProfile stores the active audio profile of the device, and redraws the device row.
+func (a *audioProfilesView) setActiveProfile(address bluetooth.DeviceAddress, profile bluetooth.AudioProfile) {
+	a.mu.Lock()
+	a.active[address.Address] = audioProfileTag(profile.Name)
+	a.mu.Unlock()
+
+	a.device.redrawDevice(address)
+	go a.updateDefaultSink()
+}
+
+// isDefaultSink returns whether the device is the default audio output of the system.
+func (a *audioProfilesView) isDefaultSink(address bluetooth.MacAddress) bool {
+	if !a.isSupported.Load() {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return !a.defaultSink.IsNil() && a.defaultSink == address
+}
+
+// setDefaultSink sets the selected device as the default audio output of the system.
+func (a *audioProfilesView) setDefaultSink() {
+	if !a.isSupported.Load() {
+		a.status.ErrorMessage(errors.New("this operation is not supported"))
+		return
+	}
+
+	device := a.device.getSelection(true)
+	if device.IsNil() {
+		return
+	}
+
+	name := btdevice.DisplayName(device.DeviceEventData)
+
+	if connected, ok := device.Connected.Get(); !ok || !connected {
+		a.status.InfoMessage(name+" is not connected", false)
+		return
+	}
+
+	if a.isDefaultSink(device.Address) {
+		a.status.InfoMessage(name+" is already the default audio output", false)
+		return
+	}
+
+	if err := a.backend.setDefaultSink(device.DeviceAddress); err != nil {
+		a.status.ErrorMessage(err)
+		return
+	}
+
+	a.status.InfoMessage("Set "+name+" as the default audio output", false)
+	a.updateDefaultSink()
+}
+
+// updateDefaultSink retrieves the default audio output from the sound server,
+// and redraws the devices whose default output state has changed.
+func (a *audioProfilesView) updateDefaultSink() {
+	address, err := a.backend.defaultSink()
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	previous := a.defaultSink
+	a.defaultSink = address
+	a.mu.Unlock()
+
+	if previous != address {
+		a.device.redrawDevicesByAddress(previous, address)
+	}
+}
+
+// watchDefaultSink periodically checks for changes to the default audio output which are made
+// outside the application. The sound server is only queried while any audio devices are connected.
+func (a *audioProfilesView) watchDefaultSink() {
+	ticker := time.NewTicker(audioDefaultSinkInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.mu.Lock()
+		connected := len(a.active) > 0 || !a.defaultSink.IsNil()
+		a.mu.Unlock()
+
+		if connected {
+			a.updateDefaultSink()
+		}
+	}
+}
+
+// clearActiveProfile removes the stored audio profile of the device.
+func (a *audioProfilesView) clearActiveProfile(address bluetooth.MacAddress) {
+	if !a.isSupported.Load() {
+		return
+	}
+
+	a.mu.Lock()
+	delete(a.active, address)
+	a.mu.Unlock()
+}
+
+// audioProfileTag returns a short name for the audio profile with the provided name.
+func audioProfileTag(name string) string {
+	name = strings.ToLower(name)
+
+	switch {
+	case name == "off":
+		return "Audio Off"
+
+	case strings.Contains(name, "a2dp"):
+		return "A2DP"
+
+	case strings.Contains(name, "handsfree"), strings.Contains(name, "hfp"):
+		return "HFP"
+
+	case strings.Contains(name, "headset"), strings.Contains(name, "hsp"):
+		return "HSP"
+
+	case strings.Contains(name, "bap"):
+		return "LE Audio"
+	}
+
+	return ""
+}
+
+// hasAudioServices returns whether any of the provided services is an audio service.
+func hasAudioServices(serviceUUIDs uuid.UUIDs) bool {
+	for _, group := range groupServices(serviceUUIDs) {
+		if group.name == "Audio" {
+			return true
+		}
+	}
+
+	return false
+}
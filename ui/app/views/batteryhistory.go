@@ -0,0 +1,81 @@
+package views
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// batteryHistorySize is the maximum number of battery readings stored for each device.
+const batteryHistorySize = 32
+
+// sparklineBlocks holds the blocks used to draw a sparkline, in increasing order of height.
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// batteryHistory holds the recent battery readings of devices.
+type batteryHistory struct {
+	readings map[bluetooth.MacAddress][]uint32
+	mu       sync.Mutex
+}
+
+// newBatteryHistory returns a new battery history.
+func newBatteryHistory() *batteryHistory {
+	return &batteryHistory{
+		readings: make(map[bluetooth.MacAddress][]uint32),
+	}
+}
+
+// add appends the battery reading of the device to its history, if it has changed
+// from the last reading. The oldest reading is discarded if the history is full.
+func (b *batteryHistory) add(address bluetooth.MacAddress, percentage uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	readings := b.readings[address]
+	if len(readings) > 0 && readings[len(readings)-1] == percentage {
+		return
+	}
+
+	if len(readings) == batteryHistorySize {
+		readings = append(readings[:0], readings[1:]...)
+	}
+
+	b.readings[address] = append(readings, percentage)
+}
+
+// remove clears the battery history of the device.
+func (b *batteryHistory) remove(address bluetooth.MacAddress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.readings, address)
+}
+
+// sparkline returns a sparkline of the battery history of the device, along with
+// the first and last readings. If there are less than two readings, an empty string is returned.
+func (b *batteryHistory) sparkline(address bluetooth.MacAddress) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	readings := b.readings[address]
+	if len(readings) < 2 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	for _, percentage := range readings {
+		index := int(min(percentage, 100)) * (len(sparklineBlocks) - 1) / 100
+		sb.WriteRune(sparklineBlocks[index])
+	}
+
+	sb.WriteString(" (")
+	sb.WriteString(strconv.FormatUint(uint64(readings[0]), 10))
+	sb.WriteString("% → ")
+	sb.WriteString(strconv.FormatUint(uint64(readings[len(readings)-1]), 10))
+	sb.WriteString("%)")
+
+	return sb.String()
+}
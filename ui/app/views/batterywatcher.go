@@ -0,0 +1,102 @@
+package views
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// batteryHysteresis is the percentage above the low battery threshold
+// that a device has to charge to, before it can be alerted for again.
+const batteryHysteresis = 5
+
+// batteryWatcher holds an instance of a battery level watcher, which alerts
+// the user when the battery level of a connected device is low.
+type batteryWatcher struct {
+	v *Views
+
+	threshold uint32
+	alerted   map[bluetooth.MacAddress]struct{}
+	mu        sync.Mutex
+}
+
+// newBatteryWatcher returns a new battery level watcher.
+func newBatteryWatcher(v *Views) *batteryWatcher {
+	return &batteryWatcher{
+		v:         v,
+		threshold: uint32(v.cfg.Values.BatteryThreshold),
+		alerted:   make(map[bluetooth.MacAddress]struct{}),
+	}
+}
+
+// reload updates the battery threshold after the configuration is reloaded.
+func (b *batteryWatcher) reload() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.threshold = uint32(b.v.cfg.Values.BatteryThreshold)
+}
+
+// check checks the battery level of a device, and alerts the user once
+// if it is below the threshold.
+func (b *batteryWatcher) check(address bluetooth.DeviceAddress, percentage uint32) {
+	b.mu.Lock()
+	threshold := b.threshold
+	if percentage >= threshold+batteryHysteresis {
+		delete(b.alerted, address.Address)
+	}
+
+	_, alerted := b.alerted[address.Address]
+	if alerted || percentage >= threshold {
+		b.mu.Unlock()
+		return
+	}
+
+	b.alerted[address.Address] = struct{}{}
+	b.mu.Unlock()
+
+	props, err := b.v.app.Session().Device(address).Properties()
+	if err != nil {
+		return
+	}
+
+	if connected, ok := props.Connected.Get(); !ok || !connected {
+		return
+	}
+
+	name := btdevice.DisplayName(props.DeviceEventData)
+	level := strconv.FormatUint(uint64(percentage), 10) + "%"
+
+	b.v.status.InfoMessage(name+" has a low battery level ("+level+")", false)
+	b.v.notifier.notify("Low battery", name+" has a low battery level ("+level+")")
+}
+
+// event handles device events to watch for battery level changes.
+func (b *batteryWatcher) event() {
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		b.v.status.ErrorMessage(errors.New("cannot subscribe to device events"))
+		return
+	}
+
+	for {
+		select {
+		case <-deviceSub.Done:
+			return
+
+		case ev := <-deviceSub.UpdatedEvents:
+			if percentage, ok := ev.Percentage.Get(); ok && percentage > 0 {
+				b.check(ev.DeviceAddress, percentage)
+			}
+
+		case ev := <-deviceSub.RemovedEvents:
+			b.mu.Lock()
+			delete(b.alerted, ev.Address)
+			b.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package views
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/godbus/dbus/v5"
+)
+
+const bluezDest = "org.bluez"
+
+// errBluezUnsupported is returned by the functions which call BlueZ directly,
+// if BlueZ is not used by the session.
+var errBluezUnsupported = errors.New("this is only supported by BlueZ")
+
+// bluezDevicePath returns the object path of the device in BlueZ, for example
+// "/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF".
+func bluezDevicePath(adapterName string, address bluetooth.MacAddress) dbus.ObjectPath {
+	return dbus.ObjectPath("/org/bluez/" + adapterName + "/dev_" + strings.ReplaceAll(address.String(), ":", "_"))
+}
+
+// setBluezProperty sets a property of the provided interface of a BlueZ object.
+// This is used for properties which cannot be set using the session.
+func setBluezProperty(path dbus.ObjectPath, iface, name string, value any) error {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return err
+	}
+
+	return conn.Object(bluezDest, path).
+		Call("org.freedesktop.DBus.Properties.Set", 0, iface, name, dbus.MakeVariant(value)).
+		Store()
+}
+
+// setDeviceAlias sets the alias of the device, using the "org.bluez.Device1" interface.
+// If the alias is empty, BlueZ resets the alias to the name of the device.
+func setDeviceAlias(adapterName string, address bluetooth.MacAddress, alias string) error {
+	devicePath := bluezDevicePath(adapterName, address)
+
+	return setBluezProperty(devicePath, bluezDeviceIface, "Alias", alias)
+}
+
+// setAdapterAlias sets the alias of the adapter, using the "org.bluez.Adapter1" interface.
+func setAdapterAlias(adapterName, alias string) error {
+	return setBluezProperty(dbus.ObjectPath("/org/bluez/"+adapterName), bluezAdapterIface, "Alias", alias)
+}
+
+// setAdapterTimeout sets the discoverable or pairable timeout (in seconds) of the adapter,
+// using the "org.bluez.Adapter1" interface. The property is either "DiscoverableTimeout"
+// or "PairableTimeout".
+func setAdapterTimeout(adapterName, property string, timeout uint32) error {
+	return setBluezProperty(dbus.ObjectPath("/org/bluez/"+adapterName), bluezAdapterIface, property, timeout)
+}
+
+// getMediaPlayer returns the object path and the properties of the media player of the device,
+// using the "org.bluez.MediaPlayer1" interface. The path of the player can change while the
+// device is connected, so it is looked up each time.
+func getMediaPlayer(adapterName string, address bluetooth.MacAddress) (dbus.ObjectPath, map[string]dbus.Variant, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := conn.Object(bluezDest, "/").
+		Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).
+		Store(&objects); err != nil {
+		return "", nil, err
+	}
+
+	devicePath := bluezDevicePath(adapterName, address)
+
+	for path, ifaces := range objects {
+		props, ok := ifaces[bluezMediaPlayerIface]
+		if !ok {
+			continue
+		}
+
+		if device, ok := props["Device"].Value().(dbus.ObjectPath); ok && device == devicePath {
+			return path, props, nil
+		}
+	}
+
+	return "", nil, errors.New("the device does not have a media player")
+}
+
+// getPlaybackModes returns the repeat and shuffle modes of the media player of the device.
+// If the player does not support the modes, they are returned as empty strings.
+func getPlaybackModes(adapterName string, address bluetooth.MacAddress) (string, string, error) {
+	_, props, err := getMediaPlayer(adapterName, address)
+	if err != nil {
+		return "", "", err
+	}
+
+	repeat, _ := props["Repeat"].Value().(string)
+	shuffle, _ := props["Shuffle"].Value().(string)
+
+	return repeat, shuffle, nil
+}
+
+// setPlaybackMode sets the "Repeat" or "Shuffle" property of the media player of the device.
+func setPlaybackMode(adapterName string, address bluetooth.MacAddress, property, mode string) error {
+	path, props, err := getMediaPlayer(adapterName, address)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := props[property]; !ok {
+		return errors.New("the player does not support changing the " + strings.ToLower(property) + " mode")
+	}
+
+	return setBluezProperty(path, bluezMediaPlayerIface, property, mode)
+}
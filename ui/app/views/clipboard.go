@@ -0,0 +1,70 @@
+package views
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/darkhz/tview"
+)
+
+// clipboardCommands lists the commands which can write to the system clipboard,
+// in order of preference. If env is set, the command is only used if the
+// environment variable is set, for example when a display server is running.
+var clipboardCommands = []struct {
+	env, name string
+	args      []string
+}{
+	{env: "WAYLAND_DISPLAY", name: "wl-copy"},
+	{env: "DISPLAY", name: "xclip", args: []string{"-selection", "clipboard"}},
+	{env: "DISPLAY", name: "xsel", args: []string{"--clipboard", "--input"}},
+	{name: "pbcopy"},
+	{name: "clip"},
+}
+
+// copyToClipboard writes the provided text to the system clipboard.
+func copyToClipboard(text string) error {
+	for _, command := range clipboardCommands {
+		if command.env != "" && os.Getenv(command.env) == "" {
+			continue
+		}
+
+		path, err := exec.LookPath(command.name)
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, command.args...)
+		cmd.Stdin = strings.NewReader(text)
+
+		return cmd.Run()
+	}
+
+	return errors.New("no clipboard utility (wl-copy, xclip, xsel, pbcopy or clip) was found")
+}
+
+// copyAndNotify writes the provided text to the system clipboard, and displays the message if it succeeds.
+func copyAndNotify(status *statusBarView, text, message string) {
+	if err := copyToClipboard(text); err != nil {
+		status.ErrorMessage(err)
+		return
+	}
+
+	status.InfoMessage(message, false)
+}
+
+// selectedInfoValue returns the value of the selected row of an information table.
+// The value of each row is stored as the reference of the cell in its second column.
+func selectedInfoValue(table *tview.Table) (string, bool) {
+	row, _ := table.GetSelection()
+
+	cell := table.GetCell(row, 1)
+	if cell == nil {
+		return "", false
+	}
+
+	value, ok := cell.GetReference().(string)
+
+	return value, ok && value != ""
+}
@@ -0,0 +1,97 @@
+package views
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// connectionTimes holds the times at which devices were connected.
+// Only connections which were established while the application is running are tracked,
+// since the connection time of devices which were already connected is not known.
+type connectionTimes struct {
+	times map[bluetooth.MacAddress]time.Time
+	last  bluetooth.MacAddress
+	mu    sync.Mutex
+}
+
+// newConnectionTimes returns a new connection times tracker.
+func newConnectionTimes() *connectionTimes {
+	return &connectionTimes{
+		times: make(map[bluetooth.MacAddress]time.Time),
+	}
+}
+
+// update records the current time if the device has connected,
+// or clears the recorded time if the device has disconnected.
+func (c *connectionTimes) update(address bluetooth.MacAddress, connected bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !connected {
+		delete(c.times, address)
+		return
+	}
+
+	if _, ok := c.times[address]; !ok {
+		c.times[address] = time.Now()
+	}
+}
+
+// setLastConnected records the device as the device which has most recently connected.
+func (c *connectionTimes) setLastConnected(address bluetooth.MacAddress) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.last = address
+}
+
+// lastConnected returns the address of the device which has most recently connected.
+func (c *connectionTimes) lastConnected() (bluetooth.MacAddress, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.last, !c.last.IsNil()
+}
+
+// remove clears the recorded time of the device.
+func (c *connectionTimes) remove(address bluetooth.MacAddress) {
+	c.update(address, false)
+}
+
+// duration returns how long the device has been connected for.
+func (c *connectionTimes) duration(address bluetooth.MacAddress) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	connectedAt, ok := c.times[address]
+	if !ok {
+		return 0, false
+	}
+
+	return time.Since(connectedAt), true
+}
+
+// addresses returns the addresses of all the tracked devices.
+func (c *connectionTimes) addresses() []bluetooth.MacAddress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	addresses := make([]bluetooth.MacAddress, 0, len(c.times))
+	for address := range c.times {
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+// format returns the formatted connection duration of the device, if it is tracked.
+func (c *connectionTimes) format(address bluetooth.MacAddress) (string, bool) {
+	duration, ok := c.duration(address)
+	if !ok {
+		return "", false
+	}
+
+	return formatTimeDuration(duration), true
+}
@@ -0,0 +1,95 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// daemonWatcher holds a watcher, which monitors the BlueZ daemon on the system bus.
+// If the daemon is restarted, the session is restarted, so that the adapters and devices
+// are reloaded and the pairing agent is registered again.
+// If BlueZ is not used by the session, the watcher does not run.
+type daemonWatcher struct {
+	v *Views
+}
+
+// newDaemonWatcher returns a new daemon watcher.
+func newDaemonWatcher(v *Views) *daemonWatcher {
+	return &daemonWatcher{v: v}
+}
+
+// watch listens for changes of the owner of the BlueZ bus name.
+// A private connection is used, since the connection of the session is closed
+// when the session is restarted.
+func (d *daemonWatcher) watch() {
+	if !bluezSupported {
+		return
+	}
+
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface("org.freedesktop.DBus"),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, bluezDest),
+	); err != nil {
+		return
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+
+	for signal := range signals {
+		if signal.Name != "org.freedesktop.DBus.NameOwnerChanged" || len(signal.Body) < 3 {
+			continue
+		}
+
+		name, _ := signal.Body[0].(string)
+		newOwner, _ := signal.Body[2].(string)
+		if name != bluezDest {
+			continue
+		}
+
+		if newOwner == "" {
+			d.v.status.ErrorMessage(errors.New("the Bluetooth daemon has stopped, waiting for it to restart"))
+			continue
+		}
+
+		d.reconnect()
+	}
+}
+
+// reconnect restarts the session, and reloads the adapters and devices.
+func (d *daemonWatcher) reconnect() {
+	d.v.status.InfoMessage("Reconnecting to the Bluetooth daemon...", true)
+
+	d.v.op.cancelOperation(false)
+
+	if err := d.v.restartSession(); err != nil {
+		d.v.status.ErrorMessage(fmt.Errorf("cannot reconnect to the Bluetooth daemon: %w", err))
+		return
+	}
+
+	d.v.defaults.init()
+
+	if !d.v.adapter.selectAdapter() {
+		go d.v.app.QueueDraw(func() {
+			d.v.device.clear()
+		})
+
+		return
+	}
+
+	go d.v.app.QueueDraw(func() {
+		d.v.adapter.updateTopStatus()
+		d.v.device.list()
+	})
+
+	d.v.status.InfoMessage("Reconnected to the Bluetooth daemon", false)
+}
@@ -0,0 +1,144 @@
+package views
+
+import (
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// defaultDeviceConnector holds a connector, which connects to the default device
+// of an adapter once the adapter is powered on.
+type defaultDeviceConnector struct {
+	v *Views
+
+	powered map[bluetooth.MacAddress]bool
+	mu      sync.Mutex
+}
+
+// newDefaultDeviceConnector returns a new default device connector.
+func newDefaultDeviceConnector(v *Views) *defaultDeviceConnector {
+	return &defaultDeviceConnector{
+		v:       v,
+		powered: make(map[bluetooth.MacAddress]bool),
+	}
+}
+
+// adapterKey returns the key of the adapter within the "default-devices" configuration.
+func (d *defaultDeviceConnector) adapterKey(adapter bluetooth.AdapterData) string {
+	if adapter.UniqueName != "" {
+		return adapter.UniqueName
+	}
+
+	return adapter.Address.String()
+}
+
+// get returns the address of the default device of the adapter, if it is set.
+// The adapter may be specified by either its name or its address in the configuration.
+func (d *defaultDeviceConnector) get(adapter bluetooth.AdapterData) (string, bool) {
+	for _, key := range []string{adapter.UniqueName, adapter.Address.String()} {
+		if address, ok := d.v.cfg.Values.DefaultDevices[key]; ok && key != "" {
+			return address, true
+		}
+	}
+
+	return "", false
+}
+
+// toggle sets the provided device as the default device of the adapter, or unsets it
+// if it is already the default device. The mapping is saved to the configuration.
+func (d *defaultDeviceConnector) toggle(adapter bluetooth.AdapterData, device bluetooth.DeviceData) (bool, error) {
+	defaultDevices := make(map[string]string, len(d.v.cfg.Values.DefaultDevices))
+	for key, address := range d.v.cfg.Values.DefaultDevices {
+		if key == adapter.UniqueName || key == adapter.Address.String() {
+			continue
+		}
+
+		defaultDevices[key] = address
+	}
+
+	address, isDefault := d.get(adapter)
+	isDefault = isDefault && address == device.Address.String()
+	if !isDefault {
+		defaultDevices[d.adapterKey(adapter)] = device.Address.String()
+	}
+
+	if err := d.v.cfg.Save("default-devices", defaultDevices); err != nil {
+		return isDefault, err
+	}
+
+	d.v.cfg.Values.DefaultDevices = defaultDevices
+
+	return !isDefault, nil
+}
+
+// init stores the initial powered states of all the adapters.
+func (d *defaultDeviceConnector) init() {
+	adapters, err := d.v.app.Session().Adapters()
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, adapter := range adapters {
+		d.powered[adapter.Address] = adapter.Powered.Value()
+	}
+}
+
+// check checks whether the adapter has transitioned to a powered on state,
+// and connects to its default device. A connection is attempted only once for each transition.
+func (d *defaultDeviceConnector) check(ev bluetooth.AdapterEventData) {
+	powered, ok := ev.Powered.Get()
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	wasPowered := d.powered[ev.Address]
+	d.powered[ev.Address] = powered
+	d.mu.Unlock()
+
+	if !powered || wasPowered || len(d.v.cfg.Values.DefaultDevices) == 0 {
+		return
+	}
+
+	adapter, err := d.v.app.Session().Adapter(ev.AdapterAddress).Properties()
+	if err != nil {
+		return
+	}
+
+	address, ok := d.get(adapter)
+	if !ok {
+		return
+	}
+
+	devices, err := d.v.app.Session().Adapter(ev.AdapterAddress).Devices()
+	if err != nil {
+		d.v.status.ErrorMessage(err)
+		return
+	}
+
+	for _, device := range devices {
+		if device.Address.String() != address {
+			continue
+		}
+
+		if connected, ok := device.Connected.Get(); ok && connected {
+			return
+		}
+
+		name := btdevice.DisplayName(device.DeviceEventData)
+
+		d.v.status.InfoMessage("Connecting to the default device "+name, true)
+		if err := d.v.app.Session().Device(device.DeviceAddress).Connect(); err != nil {
+			d.v.status.ErrorMessage(err)
+			return
+		}
+		d.v.status.InfoMessage("Connected to "+name, false)
+
+		return
+	}
+}
@@ -0,0 +1,329 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/bluetuith/ui/btdevice"
+	"github.com/darkhz/bluetuith/ui/keybindings"
+	"github.com/darkhz/bluetuith/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// deviceSelection holds the devices which are selected in the devices view,
+// so that an action can be applied to all of them at once.
+type deviceSelection struct {
+	addresses map[bluetooth.DeviceAddress]struct{}
+	mu        sync.Mutex
+}
+
+// batchAction describes an action which can be applied to multiple devices.
+// The apply function returns a description of the result for the device.
+type batchAction struct {
+	title string
+	apply func(ctx context.Context, device bluetooth.DeviceData) (string, error)
+}
+
+// batchResult holds the result of a batch action for a single device.
+type batchResult struct {
+	name, result string
+	err          error
+}
+
+// newDeviceSelection returns a new device selection.
+func newDeviceSelection() *deviceSelection {
+	return &deviceSelection{
+		addresses: make(map[bluetooth.DeviceAddress]struct{}),
+	}
+}
+
+// toggle selects the device if it is not selected, and unselects it otherwise.
+// It returns whether the device is selected.
+func (s *deviceSelection) toggle(address bluetooth.DeviceAddress) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.addresses[address]; ok {
+		delete(s.addresses, address)
+		return false
+	}
+
+	s.addresses[address] = struct{}{}
+
+	return true
+}
+
+// has returns whether the device is selected.
+func (s *deviceSelection) has(address bluetooth.DeviceAddress) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.addresses[address]
+
+	return ok
+}
+
+// remove unselects the device.
+func (s *deviceSelection) remove(address bluetooth.DeviceAddress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.addresses, address)
+}
+
+// clear unselects all the devices.
+func (s *deviceSelection) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.addresses)
+}
+
+// toggleSelection selects or unselects the device under the cursor in the devices view,
+// and moves the cursor to the next device.
+func (d *deviceView) toggleSelection() {
+	row, _ := d.table.GetSelection()
+
+	device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData)
+	if !ok {
+		return
+	}
+
+	d.selection.toggle(device.DeviceAddress)
+	d.setPropertyInfo(row, device.DeviceEventData, true)
+
+	if row+1 < d.table.GetRowCount() {
+		d.table.Select(row+1, 0)
+	}
+}
+
+// selectedDevices returns the selected devices, in the order in which they are listed in the devices view.
+func (d *deviceView) selectedDevices() []bluetooth.DeviceData {
+	var devices []bluetooth.DeviceData
+
+	d.app.QueueDraw(func() {
+		for row := range d.table.GetRowCount() {
+			device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData)
+			if ok && d.selection.has(device.DeviceAddress) {
+				devices = append(devices, device)
+			}
+		}
+	})
+
+	return devices
+}
+
+// clearSelection unselects all the devices, and redraws them in the devices view.
+func (d *deviceView) clearSelection(devices []bluetooth.DeviceData) {
+	d.selection.clear()
+
+	for _, device := range devices {
+		d.redrawDevice(device.DeviceAddress)
+	}
+}
+
+// batchActions returns the actions which can be applied to the selected devices.
+func (d *deviceView) batchActions() []batchAction {
+	return []batchAction{
+		{
+			title: "Connect",
+			apply: func(ctx context.Context, device bluetooth.DeviceData) (string, error) {
+				if device.Connected.Value() {
+					return "Already connected", nil
+				}
+
+				d.safe.allow(device.Address)
+				if err := d.actions.connectDevice(ctx, device.DeviceAddress); err != nil {
+					return "", err
+				}
+
+				return "Connected", nil
+			},
+		},
+		{
+			title: "Disconnect",
+			apply: func(_ context.Context, device bluetooth.DeviceData) (string, error) {
+				if !device.Connected.Value() {
+					return "Not connected", nil
+				}
+
+				d.reconnect.ignoreNext(device.Address)
+				if err := d.app.Session().Device(device.DeviceAddress).Disconnect(); err != nil {
+					return "", err
+				}
+				d.player.closeForDevice(device.DeviceAddress)
+
+				return "Disconnected", nil
+			},
+		},
+		{
+			title: "Trust",
+			apply: func(_ context.Context, device bluetooth.DeviceData) (string, error) {
+				if device.Trusted.Value() {
+					return "Already trusted", nil
+				}
+
+				if err := d.app.Session().Device(device.DeviceAddress).SetTrusted(true); err != nil {
+					return "", err
+				}
+
+				return "Trusted", nil
+			},
+		},
+		{
+			title: "Remove",
+			apply: func(_ context.Context, device bluetooth.DeviceData) (string, error) {
+				if err := d.app.Session().Device(device.DeviceAddress).Remove(); err != nil {
+					return "", err
+				}
+
+				return "Removed", nil
+			},
+		},
+	}
+}
+
+// showBatchActions shows a popup with the actions which can be applied to the selected devices.
+func (d *deviceView) showBatchActions(devices []bluetooth.DeviceData) {
+	actions := d.batchActions()
+
+	batchModal := d.modals.newModalWithTable("batch", "Batch Actions ("+strconv.Itoa(len(devices))+" devices)", len(actions)+4, 40)
+
+	inputCapture := batchModal.table.GetInputCapture()
+	batchModal.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if d.kb.Key(event) != keybindings.KeySelect {
+			return inputCapture(event)
+		}
+
+		row, _ := batchModal.table.GetSelection()
+		if action, ok := batchModal.table.GetCell(row, 0).GetReference().(batchAction); ok {
+			batchModal.remove(false)
+			go d.runBatchAction(action, devices)
+		}
+
+		return nil
+	})
+
+	for row, action := range actions {
+		batchModal.table.SetCell(
+			row, 0, tview.NewTableCell(action.title).
+				SetExpansion(1).
+				SetReference(action).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(theme.ThemeText)).
+				SetSelectedStyle(
+					tcell.Style{}.
+						Bold(true).Reverse(true),
+				),
+		)
+	}
+
+	batchModal.show()
+}
+
+// runBatchAction applies the action to all the devices after a confirmation, one device at a time.
+// The action can be cancelled like any other operation, in which case the remaining devices are skipped.
+// Once the action has been applied, the selection is cleared and the result for each device is shown.
+func (d *deviceView) runBatchAction(action batchAction, devices []bluetooth.DeviceData) {
+	prompt := fmt.Sprintf("%s %d selected devices", action.title, len(devices))
+	if !d.actions.confirm(true, prompt) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if !d.op.startOperation(
+		func() {
+			defer cancel()
+
+			results := make([]batchResult, 0, len(devices))
+			var failed int
+
+			for i, device := range devices {
+				name := btdevice.DisplayName(device.DeviceEventData)
+
+				if ctx.Err() != nil {
+					results = append(results, batchResult{name: name, err: context.Canceled})
+					failed++
+
+					continue
+				}
+
+				d.status.InfoMessage(fmt.Sprintf("%s: %s (%d/%d)", action.title, name, i+1, len(devices)), true)
+
+				if current, err := d.app.Session().Device(device.DeviceAddress).Properties(); err == nil {
+					device = current
+				}
+
+				result, err := action.apply(ctx, device)
+				if err != nil {
+					failed++
+				}
+
+				results = append(results, batchResult{name: name, result: result, err: err})
+			}
+
+			d.clearSelection(devices)
+			d.app.QueueDraw(func() {
+				d.showBatchResults(action, results)
+			})
+
+			summary := fmt.Sprintf("%s: %d of %d devices succeeded", action.title, len(devices)-failed, len(devices))
+			if failed > 0 {
+				d.status.ErrorMessage(errors.New(summary))
+				return
+			}
+
+			d.status.InfoMessage(summary, false)
+		},
+		cancel,
+	) {
+		cancel()
+	}
+}
+
+// showBatchResults shows a popup with the result of the batch action for each device.
+func (d *deviceView) showBatchResults(action batchAction, results []batchResult) {
+	var width int
+
+	resultsModal := d.modals.newModalWithTable("batch-results", action.title+" Results", 0, 0)
+
+	for row, result := range results {
+		text, color := result.result, theme.ThemeText
+		if result.err != nil {
+			text, color = result.err.Error(), theme.ThemeStatusError
+		}
+
+		width = max(width, len(result.name)+len(text)+3)
+
+		resultsModal.table.SetCell(
+			row, 0, tview.NewTableCell(result.name).
+				SetExpansion(1).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(theme.ThemeText)).
+				SetSelectedStyle(
+					tcell.Style{}.
+						Bold(true).Reverse(true),
+				),
+		)
+
+		resultsModal.table.SetCell(
+			row, 1, tview.NewTableCell(text).
+				SetAlign(tview.AlignRight).
+				SetTextColor(theme.GetColor(color)).
+				SetSelectedStyle(
+					tcell.Style{}.Reverse(true),
+				),
+		)
+	}
+
+	resultsModal.height = min(len(results)+4, 30)
+	resultsModal.width = min(max(width+6, 40), 100)
+
+	resultsModal.show()
+}
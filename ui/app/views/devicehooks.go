@@ -0,0 +1,119 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// hookWaitDelay is the amount of time to wait for the output of a hook to be closed,
+// after the hook has exited or has been killed. Commands which are started in the
+// background by the hook may otherwise keep the output open indefinitely.
+const hookWaitDelay = 2 * time.Second
+
+// deviceHooks holds an instance of a hook runner, which runs the user-specified
+// commands when a device connects or disconnects.
+type deviceHooks struct {
+	v *Views
+
+	connected *deviceStates
+}
+
+// newDeviceHooks returns a new hook runner.
+func newDeviceHooks(v *Views) *deviceHooks {
+	return &deviceHooks{
+		v:         v,
+		connected: newDeviceStates(),
+	}
+}
+
+// event handles device events to run the hooks when the connection state of a device changes.
+func (d *deviceHooks) event() {
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		d.v.status.ErrorMessage(errors.New("cannot subscribe to device events"))
+		return
+	}
+
+	d.connected.seed(d.v.app.Session(), func(device bluetooth.DeviceData) bool {
+		return device.Connected.Value()
+	})
+
+	for {
+		select {
+		case <-deviceSub.Done:
+			return
+
+		case ev := <-deviceSub.UpdatedEvents:
+			connected, ok := ev.Connected.Get()
+			if !ok || !d.connected.update(ev.Address, connected) {
+				continue
+			}
+
+			hook := d.v.cfg.Values.OnDisconnect
+			if connected {
+				hook = d.v.cfg.Values.OnConnect
+			}
+			if hook == "" {
+				continue
+			}
+
+			go d.run(hook, ev.DeviceAddress, connected)
+
+		case ev := <-deviceSub.AddedEvents:
+			d.connected.update(ev.Address, ev.Connected.Value())
+
+		case ev := <-deviceSub.RemovedEvents:
+			d.connected.remove(ev.Address)
+		}
+	}
+}
+
+// run runs the hook command for the device, and records its output in the message log.
+// The command is run by the shell, with the address and name of the device passed as
+// the positional parameters '$1' and '$2', and set as the BLUETUITH_DEVICE_ADDRESS and
+// BLUETUITH_DEVICE_NAME environment variables. They are never substituted into the command
+// text, since the device name is set by the remote device.
+func (d *deviceHooks) run(hook string, address bluetooth.DeviceAddress, connected bool) {
+	name := address.Address.String()
+	if props, err := d.v.app.Session().Device(address).Properties(); err == nil {
+		name = btdevice.DisplayName(props.DeviceEventData)
+	}
+
+	event := "disconnect"
+	if connected {
+		event = "connect"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(d.v.cfg.Values.HookTimeout)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook, "sh", address.Address.String(), name)
+	cmd.Env = append(os.Environ(),
+		"BLUETUITH_DEVICE_ADDRESS="+address.Address.String(),
+		"BLUETUITH_DEVICE_NAME="+name,
+		"BLUETUITH_DEVICE_EVENT="+event,
+	)
+	cmd.WaitDelay = hookWaitDelay
+
+	output, err := cmd.CombinedOutput()
+	if text := strings.TrimSpace(string(output)); text != "" {
+		d.v.status.log.add("Hook output ("+event+", "+name+"): "+text, false)
+		d.v.logger.log("hook", text)
+	}
+
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %d seconds", d.v.cfg.Values.HookTimeout)
+	}
+	if err != nil {
+		d.v.status.ErrorMessage(fmt.Errorf("the %s hook for %s failed: %w", event, name, err))
+	}
+}
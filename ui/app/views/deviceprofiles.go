@@ -0,0 +1,122 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/bluetuith/ui/btdevice"
+	"github.com/darkhz/bluetuith/ui/keybindings"
+	"github.com/darkhz/bluetuith/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+)
+
+// showProfiles shows a popup with the profiles advertised by the selected device.
+// The selected profile can be connected or disconnected individually.
+func (d *deviceView) showProfiles() {
+	device := d.getSelection(false)
+	if device.IsNil() {
+		return
+	}
+
+	device, err := d.app.Session().Device(device.DeviceAddress).Properties()
+	if err != nil {
+		d.status.ErrorMessage(err)
+		return
+	}
+
+	if len(device.UUIDs) == 0 {
+		d.status.InfoMessage(btdevice.DisplayName(device.DeviceEventData)+" does not advertise any profiles", false)
+		return
+	}
+
+	var width int
+
+	profilesModal := d.modals.newModalWithTable("profiles", "Profiles", 0, 0)
+
+	inputCapture := profilesModal.table.GetInputCapture()
+	profilesModal.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		var connect bool
+
+		switch d.kb.Key(event) {
+		case keybindings.KeySelect:
+			connect = true
+
+		case keybindings.KeyDeviceProfileDisconnect:
+			connect = false
+
+		default:
+			return inputCapture(event)
+		}
+
+		row, _ := profilesModal.table.GetSelection()
+		if profileUUID, ok := profilesModal.table.GetCell(row, 0).GetReference().(uuid.UUID); ok {
+			go d.setProfileState(device, profileUUID, connect)
+		}
+
+		return nil
+	})
+
+	row := 0
+	for _, group := range groupServices(device.UUIDs) {
+		for _, profileUUID := range group.services {
+			name := serviceName(profileUUID)
+			width = max(width, len(name)+len(group.name)+3)
+
+			profilesModal.table.SetCell(
+				row, 0, tview.NewTableCell(name).
+					SetExpansion(1).
+					SetReference(profileUUID).
+					SetAlign(tview.AlignLeft).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(
+						tcell.Style{}.
+							Bold(true).Reverse(true),
+					),
+			)
+
+			profilesModal.table.SetCell(
+				row, 1, tview.NewTableCell("("+group.name+")").
+					SetAlign(tview.AlignRight).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(
+						tcell.Style{}.Reverse(true),
+					),
+			)
+
+			row++
+		}
+	}
+
+	profilesModal.height = min(row+4, 30)
+	profilesModal.width = min(max(width+6, 40), 100)
+
+	profilesModal.show()
+}
+
+// setProfileState connects or disconnects the profile of the device.
+func (d *deviceView) setProfileState(device bluetooth.DeviceData, profileUUID uuid.UUID, connect bool) {
+	name := btdevice.DisplayName(device.DeviceEventData)
+	profile := serviceName(profileUUID)
+
+	if connect {
+		d.status.InfoMessage(fmt.Sprintf("Connecting the %s profile of %s", profile, name), true)
+		if err := d.app.Session().Device(device.DeviceAddress).ConnectProfile(profileUUID); err != nil {
+			d.status.ErrorMessage(fmt.Errorf("cannot connect the %s profile: %w", profile, err))
+			return
+		}
+
+		d.status.InfoMessage(fmt.Sprintf("Connected the %s profile of %s", profile, name), false)
+
+		return
+	}
+
+	d.status.InfoMessage(fmt.Sprintf("Disconnecting the %s profile of %s", profile, name), true)
+	if err := d.app.Session().Device(device.DeviceAddress).DisconnectProfile(profileUUID); err != nil {
+		d.status.ErrorMessage(fmt.Errorf("cannot disconnect the %s profile: %w", profile, err))
+		return
+	}
+
+	d.status.InfoMessage(fmt.Sprintf("Disconnected the %s profile of %s", profile, name), false)
+}
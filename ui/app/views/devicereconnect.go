@@ -0,0 +1,219 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+const (
+	// reconnectInitialDelay is the delay before the first reconnection attempt.
+	reconnectInitialDelay = 2 * time.Second
+
+	// reconnectMaxDelay is the maximum delay between reconnection attempts.
+	reconnectMaxDelay = 2 * time.Minute
+
+	// reconnectMaxAttempts is the maximum number of reconnection attempts.
+	reconnectMaxAttempts = 8
+)
+
+// deviceReconnector holds an instance of a device reconnection manager,
+// which automatically reconnects to specific devices when they disconnect.
+type deviceReconnector struct {
+	v *Views
+
+	addresses map[bluetooth.MacAddress]struct{}
+	pending   map[bluetooth.MacAddress]context.CancelFunc
+	ignore    map[bluetooth.MacAddress]struct{}
+	mu        sync.Mutex
+}
+
+// newDeviceReconnector returns a new device reconnection manager.
+func newDeviceReconnector(v *Views) *deviceReconnector {
+	addresses := make(map[bluetooth.MacAddress]struct{}, len(v.cfg.Values.AutoReconnectAddrs))
+	for _, address := range v.cfg.Values.AutoReconnectAddrs {
+		addresses[address] = struct{}{}
+	}
+
+	return &deviceReconnector{
+		v:         v,
+		addresses: addresses,
+		pending:   make(map[bluetooth.MacAddress]context.CancelFunc),
+		ignore:    make(map[bluetooth.MacAddress]struct{}),
+	}
+}
+
+// reload updates the list of devices marked for automatic reconnection
+// after the configuration is reloaded, and starts watching for device events
+// if no devices were marked previously.
+func (r *deviceReconnector) reload() {
+	r.mu.Lock()
+	wasEnabled := len(r.addresses) > 0
+
+	r.addresses = make(map[bluetooth.MacAddress]struct{}, len(r.v.cfg.Values.AutoReconnectAddrs))
+	for _, address := range r.v.cfg.Values.AutoReconnectAddrs {
+		r.addresses[address] = struct{}{}
+	}
+
+	for address := range r.pending {
+		if _, ok := r.addresses[address]; !ok {
+			r.cancelLocked(address)
+		}
+	}
+	r.mu.Unlock()
+
+	if !wasEnabled && r.enabled() {
+		go r.event()
+	}
+}
+
+// enabled returns whether any devices are marked for automatic reconnection.
+func (r *deviceReconnector) enabled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.addresses) > 0
+}
+
+// ignoreNext cancels any pending reconnection to the device, and skips reconnecting
+// on its next disconnection. This is used when the user disconnects the device explicitly.
+func (r *deviceReconnector) ignoreNext(address bluetooth.MacAddress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.addresses[address]; !ok {
+		return
+	}
+
+	r.cancelLocked(address)
+	r.ignore[address] = struct{}{}
+}
+
+// cancel cancels any pending reconnection to the device.
+func (r *deviceReconnector) cancel(address bluetooth.MacAddress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancelLocked(address)
+}
+
+// cancelLocked cancels any pending reconnection to the device.
+// This must be called with the lock held.
+func (r *deviceReconnector) cancelLocked(address bluetooth.MacAddress) {
+	if cancel, ok := r.pending[address]; ok {
+		cancel()
+		delete(r.pending, address)
+	}
+}
+
+// schedule starts reconnecting to the device in the background, if it is marked
+// for automatic reconnection and no reconnection is already in progress.
+func (r *deviceReconnector) schedule(address bluetooth.DeviceAddress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.addresses[address.Address]; !ok {
+		return
+	}
+
+	if _, ok := r.ignore[address.Address]; ok {
+		delete(r.ignore, address.Address)
+		return
+	}
+
+	if _, ok := r.pending[address.Address]; ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.pending[address.Address] = cancel
+
+	go r.reconnect(ctx, address)
+}
+
+// reconnect attempts to connect to the device, with an exponentially increasing delay
+// between each attempt, until the device is connected or the context is cancelled.
+func (r *deviceReconnector) reconnect(ctx context.Context, address bluetooth.DeviceAddress) {
+	defer func() {
+		r.mu.Lock()
+		if ctx.Err() == nil {
+			delete(r.pending, address.Address)
+		}
+		r.mu.Unlock()
+	}()
+
+	name := address.Address.String()
+	if props, err := r.v.app.Session().Device(address).Properties(); err == nil {
+		name = btdevice.DisplayName(props.DeviceEventData)
+	}
+
+	delay := reconnectInitialDelay
+	for attempt := 1; attempt <= reconnectMaxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(delay):
+		}
+
+		r.v.status.InfoMessage("Reconnecting to "+name+" (attempt "+strconv.Itoa(attempt)+")", true)
+		if err := r.v.app.Session().Device(address).Connect(); err == nil {
+			r.v.status.InfoMessage("Reconnected to "+name, false)
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		delay = min(delay*2, reconnectMaxDelay)
+	}
+
+	r.v.status.ErrorMessage(errors.New("could not reconnect to " + name))
+}
+
+// event handles device events to reconnect to devices.
+func (r *deviceReconnector) event() {
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		r.v.status.ErrorMessage(errors.New("cannot subscribe to device events"))
+		return
+	}
+
+	for {
+		select {
+		case <-deviceSub.Done:
+			r.mu.Lock()
+			for address := range r.pending {
+				r.cancelLocked(address)
+			}
+			r.mu.Unlock()
+
+			return
+
+		case ev := <-deviceSub.UpdatedEvents:
+			connected, ok := ev.Connected.Get()
+			if !ok {
+				continue
+			}
+
+			if connected {
+				r.cancel(ev.Address)
+			} else {
+				r.schedule(ev.DeviceAddress)
+			}
+
+		case ev := <-deviceSub.RemovedEvents:
+			r.mu.Lock()
+			r.cancelLocked(ev.Address)
+			delete(r.ignore, ev.Address)
+			r.mu.Unlock()
+		}
+	}
+}
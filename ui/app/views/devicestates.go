@@ -0,0 +1,65 @@
+package views
+
+import (
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// deviceStates tracks a boolean state of devices, for example whether they are connected,
+// so that changes of the state can be detected. This is required since the device update
+// events contain all the properties of a device, and not only the properties which have changed.
+type deviceStates struct {
+	states map[bluetooth.MacAddress]bool
+	mu     sync.Mutex
+}
+
+// newDeviceStates returns a new device state tracker.
+func newDeviceStates() *deviceStates {
+	return &deviceStates{
+		states: make(map[bluetooth.MacAddress]bool),
+	}
+}
+
+// seed records the states of the devices of all the adapters in the session,
+// so that devices which are already in a state are not reported as having changed to it.
+func (s *deviceStates) seed(session bluetooth.Session, state func(device bluetooth.DeviceData) bool) {
+	adapters, err := session.Adapters()
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, adapter := range adapters {
+		devices, err := session.Adapter(adapter.AdapterAddress).Devices()
+		if err != nil {
+			continue
+		}
+
+		for _, device := range devices {
+			s.states[device.Address] = state(device)
+		}
+	}
+}
+
+// update records the state of the device, and returns whether the state has changed.
+// The state of devices which are not yet tracked is considered to be unset.
+func (s *deviceStates) update(address bluetooth.MacAddress, state bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.states[address]
+	s.states[address] = state
+
+	return previous != state
+}
+
+// remove stops tracking the state of the device.
+func (s *deviceStates) remove(address bluetooth.MacAddress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, address)
+}
@@ -1,58 +1,162 @@
 package views
 
 import (
+	"cmp"
 	"errors"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
 	"github.com/bluetuith-org/bluetooth-classic/api/optional"
+	"github.com/darkhz/bluetuith/ui/btdevice"
 	"github.com/darkhz/bluetuith/ui/keybindings"
 	"github.com/darkhz/bluetuith/ui/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+	"go.uber.org/atomic"
 )
 
 const devicePage viewName = "devices"
 
 // deviceView holds the devices view.
 type deviceView struct {
-	table *tview.Table
+	table        *tview.Table
+	sortMode     atomic.String
+	minRSSI      atomic.Int32
+	batteryCache *batteryHistory
+	conns        *connectionTimes
+	connected    *deviceStates
+	selection    *deviceSelection
+	pending      *pendingDevices
+	search       typeSearch
+
+	// rows maps the addresses of the listed devices to their rows, so that the row of a device
+	// can be found without iterating through the table. It must only be accessed within the UI goroutine.
+	rows map[bluetooth.DeviceAddress]int
 
 	*Views
 }
 
+// deviceSortMode describes the order in which devices are listed in the devices view.
+type deviceSortMode string
+
+// The different sort modes for the devices view.
+const (
+	deviceSortConnected deviceSortMode = "connected"
+	deviceSortRSSI      deviceSortMode = "rssi"
+	deviceSortName      deviceSortMode = "name"
+)
+
+// deviceSortModes holds the order in which the sort modes are cycled.
+var deviceSortModes = []deviceSortMode{
+	deviceSortConnected,
+	deviceSortRSSI,
+	deviceSortName,
+}
+
+// String returns the string representation of the sort mode.
+func (s deviceSortMode) String() string {
+	return string(s)
+}
+
+// description returns a description of the sort mode.
+func (s deviceSortMode) description() string {
+	switch s {
+	case deviceSortRSSI:
+		return "signal strength"
+
+	case deviceSortName:
+		return "name"
+	}
+
+	return "connection state"
+}
+
 // Initialize initializes the devices view.
 func (d *deviceView) Initialize() error {
+	d.sortMode.Store(d.cfg.Values.DeviceSort)
+	d.minRSSI.Store(int32(d.cfg.Values.MinRSSI))
+	d.batteryCache = newBatteryHistory()
+	d.conns = newConnectionTimes()
+	d.connected = newDeviceStates()
+	d.selection = newDeviceSelection()
+	d.pending = newPendingDevices()
+	d.rows = make(map[bluetooth.DeviceAddress]int)
+
 	d.table = tview.NewTable()
 	d.table.SetSelectorWrap(true)
 	d.table.SetSelectable(true, false)
 	d.table.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 	d.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if query, ok := d.search.input(event); ok {
+			d.find(query)
+			return nil
+		}
+
 		switch d.kb.Key(event) {
+		case keybindings.KeyFind:
+			d.search.start()
+			d.find("")
+
+			return nil
+
 		case keybindings.KeyMenu:
-			d.menu.highlight(menuAdapterName)
+			d.menu.open()
+			return event
+
+		case keybindings.KeyMenuDevice:
+			d.menu.highlight(menuDeviceName)
 			return event
 
 		case keybindings.KeyHelp:
 			d.help.showHelp()
 			return event
+
+		case keybindings.KeyHelpContext:
+			d.help.showContextHelp()
+			return event
+
+		case keybindings.KeyDeviceSelect:
+			if d.player.isOpen.Load() {
+				break
+			}
+
+			d.toggleSelection()
+
+			return nil
 		}
 
-		d.player.keyEvents(event)
+		d.player.keyEvents(d.kb.Key(event, keybindings.ContextPlayer))
 
 		d.menu.inputHandler(event)
 
 		return ignoreDefaultEvent(event)
 	})
 	d.table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
-		if action == tview.MouseRightClick && d.table.HasFocus() {
+		switch {
+		case d.scrollTable(d.table, action, event):
+			return action, nil
+
+		case action == tview.MouseRightClick && d.table.HasFocus():
 			device := d.getSelection(false)
 			if device.IsNil() {
 				return action, event
 			}
 
 			d.menu.setupSubMenu(0, 0, menuDeviceName, struct{}{})
+
+		case action == tview.MouseLeftDoubleClick && d.table.HasFocus():
+			if device := d.getSelection(false); device.IsNil() {
+				return action, event
+			}
+
+			d.actions.handler(keybindings.KeyDeviceConnect, actionInvoke)()
+
+			return action, nil
 		}
 
 		return action, event
@@ -61,6 +165,7 @@ func (d *deviceView) Initialize() error {
 	d.list()
 	d.connectByAddress()
 	go d.event()
+	go d.updateConnectionDurations()
 
 	return nil
 }
@@ -73,21 +178,213 @@ func (d *deviceView) SetRootView(v *Views) {
 // clear clears the devices list.
 func (d *deviceView) clear() {
 	d.table.Clear()
+	clear(d.rows)
+}
+
+// removeRow removes the row from the devices view, and updates the rows of the devices listed after it.
+func (d *deviceView) removeRow(row int) {
+	if device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData); ok {
+		delete(d.rows, device.DeviceAddress)
+	}
+
+	d.table.RemoveRow(row)
+
+	for address, r := range d.rows {
+		if r > row {
+			d.rows[address] = r - 1
+		}
+	}
 }
 
 // list lists the devices belonging to the selected adapter within the devices view.
 func (d *deviceView) list() {
+	if !d.adapter.hasAdapter() {
+		d.clear()
+		return
+	}
+
 	devices, err := d.adapter.currentSession().Devices()
 	if err != nil {
 		d.status.ErrorMessage(err)
 		return
 	}
 
-	d.table.Clear()
+	selected := d.getSelection(false)
+	d.sortDevices(devices)
+
+	devices = slices.DeleteFunc(devices, func(device bluetooth.DeviceData) bool {
+		return d.isHidden(device.DeviceEventData)
+	})
+
+	// The existing rows are overwritten instead of clearing the table, and any
+	// remaining rows are removed afterwards, so that the table is not emptied in between.
+	clear(d.rows)
+	for row := d.table.GetRowCount() - 1; row >= len(devices); row-- {
+		d.table.RemoveRow(row)
+	}
+
 	for i, device := range devices {
 		d.setInfo(i, device)
+
+		if device.Connected.Value() && !d.audioProfiles.hasActiveProfile(device.Address) {
+			go d.audioProfiles.updateActiveProfile(device.DeviceAddress, false)
+		}
 	}
-	d.table.Select(0, 0)
+
+	row, ok := d.getRowByAddress(selected.DeviceAddress)
+	if !ok {
+		row = 0
+	}
+	d.table.Select(row, 0)
+}
+
+// isHidden returns whether the device is hidden from the devices view, since its
+// signal strength is below the minimum signal strength. Only discovered devices are hidden.
+func (d *deviceView) isHidden(device bluetooth.DeviceEventData) bool {
+	minRSSI := d.minRSSI.Load()
+	if minRSSI == 0 {
+		return false
+	}
+
+	if device.Paired.Value() || device.Bonded.Value() || device.Connected.Value() {
+		return false
+	}
+
+	rssi, ok := device.RSSI.Get()
+
+	return ok && int32(rssi) < minRSSI
+}
+
+// setMinRSSI sets the minimum signal strength of the discovered devices which are listed,
+// and lists the devices again. A value of 0 lists all the devices.
+func (d *deviceView) setMinRSSI(rssi int) {
+	d.minRSSI.Store(int32(rssi))
+
+	d.app.QueueDraw(func() {
+		d.list()
+	})
+}
+
+// holdUnnamed delays listing the newly discovered device if it has no name, and the "scan-name-wait"
+// option is set, since the names of discovered devices are often resolved shortly after they appear.
+// The device is listed once its name is resolved, or with its address once the wait has elapsed.
+// It returns whether the device is held.
+func (d *deviceView) holdUnnamed(device bluetooth.DeviceData) bool {
+	wait := time.Duration(d.cfg.Values.ScanNameWait) * time.Millisecond
+	if wait <= 0 || !device.Name.IsZero() || device.Paired.Value() || device.Connected.Value() {
+		return false
+	}
+
+	d.pending.hold(device.DeviceAddress, wait, func() {
+		d.app.QueueDraw(func() {
+			if _, ok := d.getRowByAddress(device.DeviceAddress); !ok {
+				d.showDevice(device.DeviceAddress)
+			}
+		})
+	})
+
+	return true
+}
+
+// showDevice adds a previously hidden device to the devices view, if it belongs to the current adapter.
+func (d *deviceView) showDevice(address bluetooth.DeviceAddress) {
+	adapter := d.adapter.getAdapter()
+	if adapter == nil || adapter.AdapterAddress != address.AdapterAddress() {
+		return
+	}
+
+	device, err := d.app.Session().Device(address).Properties()
+	if err != nil || d.isHidden(device.DeviceEventData) {
+		return
+	}
+
+	d.setInfo(d.table.GetRowCount(), device)
+}
+
+// nextSortMode switches to the next sort mode of the devices view and returns it.
+// The devices list has to be refreshed using 'list' for the sort mode to take effect.
+func (d *deviceView) nextSortMode() deviceSortMode {
+	current := deviceSortMode(d.sortMode.Load())
+
+	next := deviceSortModes[0]
+	if index := slices.Index(deviceSortModes, current); index >= 0 {
+		next = deviceSortModes[(index+1)%len(deviceSortModes)]
+	}
+
+	d.sortMode.Store(next.String())
+
+	return next
+}
+
+// sortDevices sorts the devices according to the current sort mode.
+// Favorite devices are always listed first.
+// Devices which compare equally are ordered by their names and addresses, so that the
+// order of the devices is always the same for a given set of devices.
+func (d *deviceView) sortDevices(devices []bluetooth.DeviceData) {
+	mode := deviceSortMode(d.sortMode.Load())
+
+	slices.SortStableFunc(devices, func(i, j bluetooth.DeviceData) int {
+		ifavorite, jfavorite := d.isFavorite(i.Address), d.isFavorite(j.Address)
+		if ifavorite != jfavorite {
+			if ifavorite {
+				return -1
+			}
+
+			return 1
+		}
+
+		switch mode {
+		case deviceSortConnected:
+			iconnected, jconnected := i.Connected.Value(), j.Connected.Value()
+			if iconnected != jconnected {
+				if iconnected {
+					return -1
+				}
+
+				return 1
+			}
+
+		case deviceSortRSSI:
+			irssi, jrssi := int16(math.MinInt16), int16(math.MinInt16)
+			if rssi, ok := i.RSSI.Get(); ok {
+				irssi = rssi
+			}
+			if rssi, ok := j.RSSI.Get(); ok {
+				jrssi = rssi
+			}
+
+			if c := cmp.Compare(jrssi, irssi); c != 0 {
+				return c
+			}
+		}
+
+		iname := strings.ToLower(btdevice.DisplayName(i.DeviceEventData))
+		jname := strings.ToLower(btdevice.DisplayName(j.DeviceEventData))
+		if c := cmp.Compare(iname, jname); c != 0 {
+			return c
+		}
+
+		return slices.Compare(i.Address[:], j.Address[:])
+	})
+}
+
+// isFavorite checks whether the device with the provided address is marked as a favorite.
+func (d *deviceView) isFavorite(address bluetooth.MacAddress) bool {
+	return slices.Contains(d.cfg.Values.FavoriteDevices, address.String())
+}
+
+// displayName returns the name of the device as displayed in the devices view.
+// Favorite devices are marked with a star, and selected devices are marked with a check mark.
+func (d *deviceView) displayName(device bluetooth.DeviceEventData) string {
+	name := btdevice.DisplayName(device)
+	if d.isFavorite(device.Address) {
+		name = "★ " + name
+	}
+	if d.selection.has(device.DeviceAddress) {
+		name = "✓ " + name
+	}
+
+	return name
 }
 
 // connectByAddress connects to a device based on the provided address
@@ -102,8 +399,10 @@ func (d *deviceView) connectByAddress() {
 }
 
 // showDetailedInfo shows detailed information about a device.
+// The properties are retrieved before the modal is shown on the UI goroutine,
+// so that drawing is not blocked while they are retrieved.
 func (d *deviceView) showDetailedInfo() {
-	device := d.getSelection(false)
+	device := d.getSelection(true)
 	if device.IsNil() {
 		return
 	}
@@ -124,7 +423,7 @@ func (d *deviceView) showDetailedInfo() {
 		{"Name", optGetValueString(device.Name)},
 		{"Alias", optGetValueString(device.Alias)},
 		{"Address", device.Address.String()},
-		{"Class", strconv.FormatUint(uint64(device.Class), 10)},
+		{"Class", strconv.FormatUint(uint64(device.Class), 10) + " (" + device.Type + ")"},
 		{"Adapter", assocAdapter.UniqueName},
 		{"Connected", optYesNo(device.Connected)},
 		{"Paired", optYesNo(device.Paired)},
@@ -133,21 +432,102 @@ func (d *deviceView) showDetailedInfo() {
 		{"Blocked", optYesNo(device.Blocked)},
 		{"LegacyPairing", yesno(device.LegacyPairing)},
 	}
+
+	modalias, _ := getDeviceModalias(assocAdapter.UniqueName, device.Address)
+	if manufacturer, ok := d.deviceManufacturer(device.Address, modalias); ok {
+		props = append(props, []string{"Manufacturer", manufacturer})
+	}
+	if modalias != "" {
+		props = append(props, []string{"Modalias", modalias})
+	}
+
+	if duration, ok := d.conns.format(device.Address); ok {
+		props = append(props, []string{"Connected For", duration})
+	}
+
+	if history := d.batteryCache.sparkline(device.Address); history != "" {
+		props = append(props, []string{"Battery History", history})
+	}
+
+	if transports, err := getMediaTransports(assocAdapter.UniqueName, device.Address); err == nil && len(transports) > 0 {
+		codecs := make([]string, 0, len(transports))
+		sampleRates := make([]string, 0, len(transports))
+		delays := make([]string, 0, len(transports))
+
+		for _, transport := range transports {
+			codec := transport.codec
+			if transport.state != "" {
+				codec += " (" + transport.state + ")"
+			}
+
+			codecs = append(codecs, codec)
+			sampleRates = append(sampleRates, formatSampleRate(transport.sampleRate))
+
+			// The delay is reported by the device in units of 1/10 milliseconds.
+			if transport.delay > 0 {
+				delays = append(delays, strconv.FormatFloat(float64(transport.delay)/10, 'f', 1, 64)+" ms")
+			}
+		}
+
+		props = append(props,
+			[]string{"Codec", strings.Join(codecs, ", ")},
+			[]string{"Sample Rate", strings.Join(sampleRates, ", ")},
+		)
+		if len(delays) > 0 {
+			props = append(props, []string{"Audio Delay", strings.Join(delays, ", ")})
+		}
+	}
+
+	qualityRow := len(props)
+	props = append(props, []string{"Link Quality", getLinkQuality(assocAdapter.UniqueName, device).String()})
+
+	d.app.QueueDraw(func() {
+		infoModal := d.showInfoModal("info", "Device Information", "device", props, device.UUIDs)
+
+		if connected, _ := device.Connected.Get(); connected {
+			go d.refreshLinkQuality(infoModal, qualityRow, assocAdapter.UniqueName, device.DeviceAddress)
+		}
+	})
+}
+
+// showInfoModal shows a modal with the provided properties, followed by the services of the
+// device or adapter, which are grouped by their category. The value of the selected row, or
+// the entire information, can be copied to the clipboard.
+func (v *Views) showInfoModal(name, title, subject string, props [][]string, serviceUUIDs uuid.UUIDs) *tableModalView {
+	var info strings.Builder
+
 	props = append(props, []string{"UUIDs", ""})
 
-	infoModal := d.modals.newModalWithTable("info", "Device Information", 40, 100)
+	infoModal := v.modals.newModalWithTable(name, title, 40, 100)
 	infoModal.table.SetSelectionChangedFunc(func(row, _ int) {
 		_, _, _, height := infoModal.table.GetRect()
 		infoModal.table.SetOffset(row-((height-1)/2), 0)
 	})
 
+	inputCapture := infoModal.table.GetInputCapture()
+	infoModal.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch v.kb.Key(event) {
+		case keybindings.KeyDeviceCopyAddress:
+			if value, ok := selectedInfoValue(infoModal.table); ok {
+				go copyAndNotify(v.status, value, "Copied '"+value+"' to the clipboard")
+			}
+
+			return nil
+
+		case keybindings.KeyDeviceCopyInfo:
+			go copyAndNotify(v.status, info.String(), "Copied the "+subject+" information to the clipboard")
+
+			return nil
+		}
+
+		return inputCapture(event)
+	})
+
 	for i, prop := range props {
 		propName := prop[0]
 		propValue := prop[1]
 
-		if propName == "Class" {
-			propValue += " (" + device.Type + ")"
-		}
+		info.WriteString(propName + ": " + propValue + "\n")
 
 		infoModal.table.SetCell(
 			i, 0, tview.NewTableCell("[::b]"+propName+":").
@@ -163,6 +543,7 @@ func (d *deviceView) showDetailedInfo() {
 
 		infoModal.table.SetCell(
 			i, 1, tview.NewTableCell(propValue).
+				SetReference(propValue).
 				SetExpansion(1).
 				SetAlign(tview.AlignLeft).
 				SetTextColor(theme.GetColor(theme.ThemeText)).
@@ -170,28 +551,47 @@ func (d *deviceView) showDetailedInfo() {
 		)
 	}
 
-	rows := infoModal.table.GetRowCount() - 1
-	for i, serviceUUID := range device.UUIDs {
-		serviceType := bluetooth.ServiceType(serviceUUID)
-		serviceString := "(" + serviceUUID.String() + ")"
+	row := infoModal.table.GetRowCount() - 1
+	for _, group := range groupServices(serviceUUIDs) {
+		info.WriteString("  " + group.name + ":\n")
 
 		infoModal.table.SetCell(
-			rows+i, 1, tview.NewTableCell(serviceType).
+			row, 1, tview.NewTableCell("[::u]"+group.name).
+				SetReference(group.name).
 				SetExpansion(1).
 				SetAlign(tview.AlignLeft).
 				SetTextColor(theme.GetColor(theme.ThemeText)),
 		)
-
-		infoModal.table.SetCell(
-			rows+i, 2, tview.NewTableCell(serviceString).
-				SetExpansion(0).
-				SetTextColor(theme.GetColor(theme.ThemeText)),
-		)
+		row++
+
+		for _, serviceUUID := range group.services {
+			serviceType := serviceName(serviceUUID)
+			serviceString := "(" + serviceUUID.String() + ")"
+
+			info.WriteString("    " + serviceType + " " + serviceString + "\n")
+
+			infoModal.table.SetCell(
+				row, 1, tview.NewTableCell("  "+serviceType).
+					SetReference(serviceUUID.String()).
+					SetExpansion(1).
+					SetAlign(tview.AlignLeft).
+					SetTextColor(theme.GetColor(theme.ThemeText)),
+			)
+
+			infoModal.table.SetCell(
+				row, 2, tview.NewTableCell(serviceString).
+					SetExpansion(0).
+					SetTextColor(theme.GetColor(theme.ThemeText)),
+			)
+			row++
+		}
 	}
 
 	infoModal.height = min(infoModal.table.GetRowCount()+4, 60)
 
 	infoModal.show()
+
+	return infoModal
 }
 
 // getSelection retrieves device information from the current selection in the devices view.
@@ -227,35 +627,101 @@ func (d *deviceView) getSelection(lock bool) bluetooth.DeviceData {
 	return device
 }
 
-// getRowByAddress iterates through the devices view and checks
-// if a device whose path matches the path parameter exists.
+// saveLastConnected stores the address of the connected device in the configuration if it is enabled,
+// so that the device can be connected to with the "Connect Last" action in later sessions.
+func (d *deviceView) saveLastConnected(address bluetooth.MacAddress) {
+	if !d.cfg.Values.RememberLastDevice || address.String() == d.cfg.Values.LastConnectedDevice {
+		return
+	}
+
+	if err := d.cfg.Save("last-connected-device", address.String()); err != nil {
+		d.status.ErrorMessage(err)
+		return
+	}
+
+	d.cfg.Values.LastConnectedDevice = address.String()
+}
+
+// find selects the first device whose name starts with the search query.
+func (d *deviceView) find(query string) {
+	found := d.search.jump(d.table, query, func(row int) (string, bool) {
+		device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData)
+		if !ok {
+			return "", false
+		}
+
+		return btdevice.DisplayName(device.DeviceEventData), true
+	})
+
+	showFindQuery(d.status, query, found)
+}
+
+// getRowByAddress returns the row of the device with the provided address in the devices view,
+// if it is listed. If the stored row of the device does not match the table, the rows are indexed again.
 func (d *deviceView) getRowByAddress(address bluetooth.DeviceAddress) (int, bool) {
+	row, ok := d.rows[address]
+	if !ok {
+		return -1, false
+	}
+
+	if ref, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData); ok && ref.DeviceAddress == address {
+		return row, true
+	}
+
+	d.indexRows()
+
+	row, ok = d.rows[address]
+	if !ok {
+		return -1, false
+	}
+
+	return row, true
+}
+
+// indexRows stores the rows of all the devices listed in the devices view.
+func (d *deviceView) indexRows() {
+	clear(d.rows)
+
 	for row := range d.table.GetRowCount() {
-		cell := d.table.GetCell(row, 0)
-		if cell == nil {
-			continue
+		if ref, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData); ok {
+			d.rows[ref.DeviceAddress] = row
 		}
+	}
+}
 
-		ref, ok := cell.GetReference().(bluetooth.DeviceData)
+// redrawDevice redraws the properties of the device in the devices view.
+func (d *deviceView) redrawDevice(address bluetooth.DeviceAddress) {
+	go d.app.QueueDraw(func() {
+		row, ok := d.getRowByAddress(address)
 		if !ok {
-			continue
+			return
 		}
 
-		if ref.DeviceAddress == address {
-			return row, true
+		if device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData); ok {
+			d.setPropertyInfo(row, device.DeviceEventData, true)
 		}
-	}
+	})
+}
 
-	return -1, false
+// redrawDevicesByAddress redraws the properties of the devices with the provided addresses in the devices view.
+func (d *deviceView) redrawDevicesByAddress(addresses ...bluetooth.MacAddress) {
+	go d.app.QueueDraw(func() {
+		for row := range d.table.GetRowCount() {
+			device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData)
+			if ok && slices.Contains(addresses, device.Address) {
+				d.setPropertyInfo(row, device.DeviceEventData, true)
+			}
+		}
+	})
 }
 
 // setInfo writes device information into the specified row of the devices view.
 func (d *deviceView) setInfo(row int, device bluetooth.DeviceData) {
 	var sb strings.Builder
 
-	name := getDeviceDisplayName(device.DeviceEventData)
+	name := btdevice.DisplayName(device.DeviceEventData)
 
-	sb.WriteString(name)
+	sb.WriteString(d.displayName(device.DeviceEventData))
 	sb.WriteString(" (")
 	if !device.Alias.IsZero() && device.Alias.Value() != name {
 		sb.WriteString(theme.ColorWrap(theme.ThemeDeviceAlias, device.Alias.Value()))
@@ -267,6 +733,7 @@ func (d *deviceView) setInfo(row int, device bluetooth.DeviceData) {
 	nameDisplay := sb.String()
 	nameColor := theme.ThemeDevice
 
+	d.rows[device.DeviceAddress] = row
 	d.table.SetCell(
 		row, 0, tview.NewTableCell(nameDisplay).
 			SetExpansion(1).
@@ -303,6 +770,10 @@ func (d *deviceView) setPropertyInfo(row int, deviceEvent bluetooth.DeviceEventD
 
 	if connected, ok := deviceEvent.Connected.Get(); ok && connected {
 		appendProperty("Connected")
+		if duration, ok := d.conns.format(deviceEvent.Address); ok {
+			sb.WriteString(" ")
+			sb.WriteString(duration)
+		}
 
 		nameColor = theme.ThemeDeviceConnected
 		propColor = theme.ThemeDevicePropertyConnected
@@ -314,6 +785,16 @@ func (d *deviceView) setPropertyInfo(row int, deviceEvent bluetooth.DeviceEventD
 			sb.WriteString("[]")
 		}
 
+		if profile := d.audioProfiles.activeProfileTag(deviceEvent.Address); profile != "" {
+			appendProperty(profile)
+		}
+		if d.audioProfiles.isDefaultSink(deviceEvent.Address) {
+			appendProperty("Default Output")
+		}
+		if network := d.network.activeTag(deviceEvent.Address); network != "" {
+			appendProperty(network)
+		}
+
 		if percentage, ok := deviceEvent.Percentage.Get(); ok && percentage > 0 {
 			appendProperty("Battery ")
 			sb.WriteString(strconv.FormatUint(uint64(percentage), 10))
@@ -342,6 +823,15 @@ func (d *deviceView) setPropertyInfo(row int, deviceEvent bluetooth.DeviceEventD
 		sb.WriteString(")")
 	}
 
+	if rssi, ok := deviceEvent.RSSI.Get(); ok && rssi < 0 {
+		properties := sb.String()
+
+		sb.Reset()
+		sb.WriteString(signalBar(rssi))
+		sb.WriteString(" ")
+		sb.WriteString(properties)
+	}
+
 	deviceNameCell := d.table.GetCell(row, 0)
 	if deviceNameCell != nil {
 		if isPartialUpdate {
@@ -351,8 +841,7 @@ func (d *deviceView) setPropertyInfo(row int, deviceEvent bluetooth.DeviceEventD
 			if found {
 				var sb strings.Builder
 
-				name := getDeviceDisplayName(deviceEvent)
-				sb.WriteString(name)
+				sb.WriteString(d.displayName(deviceEvent))
 				sb.WriteString(" (")
 				sb.WriteString(after)
 
@@ -399,18 +888,41 @@ func (d *deviceView) event() {
 		return
 	}
 
+	d.connected.seed(d.app.Session(), func(device bluetooth.DeviceData) bool {
+		return device.Connected.Value()
+	})
+
 	for {
 		select {
 		case <-deviceSub.Done:
 			return
 
 		case ev := <-deviceSub.AddedEvents:
+			d.connected.update(ev.Address, ev.Connected.Value())
+
 			go d.app.QueueDraw(func() {
 				row, ok := d.getRowByAddress(ev.DeviceAddress)
+				if d.isHidden(ev.DeviceEventData) {
+					if ok {
+						d.removeRow(row)
+					}
+
+					return
+				}
+
 				if ok {
 					d.setInfo(row, ev)
 				} else {
 					if v := d.adapter.getAdapter(); v != nil && v.AdapterAddress == ev.AdapterAddress() {
+						if d.isFavorite(ev.Address) {
+							d.list()
+							return
+						}
+
+						if d.holdUnnamed(ev) {
+							return
+						}
+
 						deviceRow := d.table.GetRowCount()
 						d.setInfo(deviceRow, ev)
 					}
@@ -418,18 +930,63 @@ func (d *deviceView) event() {
 			})
 
 		case ev := <-deviceSub.UpdatedEvents:
+			if connected, ok := ev.Connected.Get(); ok {
+				changed := d.connected.update(ev.Address, connected)
+				if changed {
+					d.conns.update(ev.Address, connected)
+				}
+
+				if connected {
+					if changed {
+						d.conns.setLastConnected(ev.Address)
+						d.saveLastConnected(ev.Address)
+
+						go d.audioProfiles.updateActiveProfile(ev.DeviceAddress, true)
+					}
+				} else {
+					d.audioProfiles.clearActiveProfile(ev.Address)
+					d.network.clearActive(ev.Address)
+				}
+			}
+			if percentage, ok := ev.Percentage.Get(); ok && percentage > 0 {
+				d.batteryCache.add(ev.Address, percentage)
+			}
+
 			go d.app.QueueDraw(func() {
 				row, ok := d.getRowByAddress(ev.DeviceAddress)
-				if ok {
+				if !ok && d.pending.has(ev.DeviceAddress) {
+					if !ev.Name.IsZero() && d.pending.release(ev.DeviceAddress) {
+						d.showDevice(ev.DeviceAddress)
+					}
+
+					return
+				}
+
+				switch hidden := d.isHidden(ev); {
+				case ok && hidden:
+					d.removeRow(row)
+
+				case ok:
 					d.setPropertyInfo(row, ev, true)
+
+				case !hidden && d.minRSSI.Load() < 0:
+					d.showDevice(ev.DeviceAddress)
 				}
 			})
 
 		case ev := <-deviceSub.RemovedEvents:
+			d.batteryCache.remove(ev.Address)
+			d.conns.remove(ev.Address)
+			d.connected.remove(ev.Address)
+			d.audioProfiles.clearActiveProfile(ev.Address)
+			d.network.clearActive(ev.Address)
+			d.selection.remove(ev.DeviceAddress)
+			d.pending.release(ev.DeviceAddress)
+
 			go d.app.QueueDraw(func() {
 				row, ok := d.getRowByAddress(ev.DeviceAddress)
 				if ok {
-					d.table.RemoveRow(row)
+					d.removeRow(row)
 					d.player.closeForDevice(ev.DeviceAddress)
 				}
 			})
@@ -437,6 +994,70 @@ func (d *deviceView) event() {
 	}
 }
 
+// updateConnectionDurations redraws the connection durations of the connected devices every second.
+func (d *deviceView) updateConnectionDurations() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		addresses := d.conns.addresses()
+		if len(addresses) == 0 {
+			continue
+		}
+
+		go d.app.QueueDraw(func() {
+			adapter := d.adapter.getAdapter()
+			if adapter == nil {
+				return
+			}
+
+			for _, address := range addresses {
+				deviceAddress := bluetooth.DeviceAddress{Address: address, AssociatedAdapter: adapter.Address}
+
+				row, ok := d.getRowByAddress(deviceAddress)
+				if !ok {
+					continue
+				}
+
+				if device, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData); ok {
+					d.setPropertyInfo(row, device.DeviceEventData, true)
+				}
+			}
+		})
+	}
+}
+
+// signalBar returns a colored signal strength indicator for the provided RSSI value.
+func signalBar(rssi int16) string {
+	const bars = "▂▄▆█"
+
+	var level int
+	var color theme.Context
+
+	switch {
+	case rssi >= -55:
+		level, color = 4, theme.ThemeDeviceSignalStrong
+
+	case rssi >= -67:
+		level, color = 3, theme.ThemeDeviceSignalMedium
+
+	case rssi >= -80:
+		level, color = 2, theme.ThemeDeviceSignalMedium
+
+	default:
+		level, color = 1, theme.ThemeDeviceSignalWeak
+	}
+
+	barRunes := []rune(bars)
+
+	indicator := theme.ColorWrap(color, string(barRunes[:level]), "::-")
+	if level < len(barRunes) {
+		indicator += theme.ColorWrap(theme.ThemeDeviceProperty, string(barRunes[level:]), "::-")
+	}
+
+	return indicator
+}
+
 func yesno(val bool) string {
 	if !val {
 		return "no"
@@ -460,16 +1081,3 @@ func optYesNo(val optional.Optional[bool]) string {
 
 	return yesno(val.Value())
 }
-
-// getDeviceDisplayName returns the display name for the device.
-func getDeviceDisplayName(deviceData bluetooth.DeviceEventData) string {
-	if name, ok := deviceData.Name.Get(); ok {
-		return name
-	}
-
-	if alias, ok := deviceData.Alias.Get(); ok {
-		return alias
-	}
-
-	return deviceData.Address.String()
-}
@@ -0,0 +1,69 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// simulatedDevices is the number of devices which are listed in the benchmarks.
+const simulatedDevices = 500
+
+func TestDeviceViewRowLookup(t *testing.T) {
+	session := newFakeSession(simulatedDevices)
+	d := newFakeViews(session).device
+	d.list()
+
+	if rows := d.table.GetRowCount(); rows != simulatedDevices {
+		t.Fatalf("expected %d rows, got %d", simulatedDevices, rows)
+	}
+
+	for _, device := range session.devices {
+		row, ok := d.getRowByAddress(device.DeviceAddress)
+		if !ok {
+			t.Fatalf("device %s is not listed", device.Address)
+		}
+
+		listed, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData)
+		if !ok || listed.Address != device.Address {
+			t.Fatalf("row %d does not hold device %s", row, device.Address)
+		}
+	}
+
+	row, _ := d.getRowByAddress(session.devices[0].DeviceAddress)
+	d.removeRow(row)
+
+	for _, device := range session.devices[1:] {
+		row, ok := d.getRowByAddress(device.DeviceAddress)
+		if !ok {
+			t.Fatalf("device %s is not listed after removing a row", device.Address)
+		}
+
+		listed, ok := d.table.GetCell(row, 0).GetReference().(bluetooth.DeviceData)
+		if !ok || listed.Address != device.Address {
+			t.Fatalf("row %d does not hold device %s after removing a row", row, device.Address)
+		}
+	}
+}
+
+func BenchmarkDeviceViewRowLookup(b *testing.B) {
+	session := newFakeSession(simulatedDevices)
+	d := newFakeViews(session).device
+	d.list()
+
+	b.ResetTimer()
+	for i := range b.N {
+		if _, ok := d.getRowByAddress(session.devices[i%len(session.devices)].DeviceAddress); !ok {
+			b.Fatal("device is not listed")
+		}
+	}
+}
+
+func BenchmarkDeviceViewList(b *testing.B) {
+	d := newFakeViews(newFakeSession(simulatedDevices)).device
+
+	b.ResetTimer()
+	for range b.N {
+		d.list()
+	}
+}
@@ -0,0 +1,231 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// addressPattern matches Bluetooth MAC addresses, separated by colons, dashes or underscores.
+var addressPattern = regexp.MustCompile(`(?i)[0-9a-f]{2}[:_-][0-9a-f]{2}(?:[:_-][0-9a-f]{2}){4}`)
+
+// eventLogger holds a file logger, which records all status messages, errors,
+// device and file transfer events, and invoked actions to a log file.
+// When the log file exceeds the maximum size, it is rotated to a single backup file.
+type eventLogger struct {
+	path          string
+	maxSize       int64
+	maskAddresses bool
+
+	file *os.File
+	size int64
+
+	connected, paired *deviceStates
+
+	v *Views
+
+	mu sync.Mutex
+}
+
+// newEventLogger returns a new file logger.
+func newEventLogger(v *Views) *eventLogger {
+	return &eventLogger{
+		path:          v.cfg.Values.LogFile,
+		maxSize:       int64(v.cfg.Values.LogMaxSize) * 1024,
+		maskAddresses: v.cfg.Values.LogNoAddresses,
+		connected:     newDeviceStates(),
+		paired:        newDeviceStates(),
+		v:             v,
+	}
+}
+
+// start opens the log file and starts recording events.
+func (e *eventLogger) start() error {
+	if e.path == "" {
+		return nil
+	}
+
+	e.mu.Lock()
+	err := e.open(false)
+	e.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	e.log("app", "Started logging")
+
+	go e.event()
+
+	return nil
+}
+
+// stop closes the log file.
+func (e *eventLogger) stop() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file == nil {
+		return
+	}
+
+	e.file.Close()
+	e.file = nil
+}
+
+// log writes a timestamped message with the provided category to the log file.
+func (e *eventLogger) log(category, message string) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file == nil {
+		return
+	}
+
+	if e.maskAddresses {
+		message = addressPattern.ReplaceAllStringFunc(message, func(address string) string {
+			separator := address[2:3]
+
+			return "XX" + separator + "XX" + separator + "XX" + separator + "XX" + separator + "XX" + separator + "XX"
+		})
+	}
+
+	line := fmt.Sprintf("%s [%s] %s\n", time.Now().Format(time.RFC3339), category, message)
+	if e.maxSize > 0 && e.size+int64(len(line)) > e.maxSize {
+		if err := e.open(true); err != nil {
+			return
+		}
+	}
+
+	n, _ := e.file.WriteString(line)
+	e.size += int64(n)
+}
+
+// open opens the log file for appending. If rotate is set, the current log file
+// is moved to a backup file, and a new log file is created.
+// This must be called with the lock held.
+func (e *eventLogger) open(rotate bool) error {
+	if e.file != nil {
+		e.file.Close()
+		e.file = nil
+	}
+
+	if rotate {
+		if err := os.Rename(e.path, e.path+".1"); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(e.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	e.file, e.size = file, info.Size()
+
+	return nil
+}
+
+// event handles error, device and file transfer events to record them in the log file.
+func (e *eventLogger) event() {
+	errorSub, ok := bluetooth.ErrorEvents().Subscribe()
+	if !ok {
+		e.log("error", "Cannot subscribe to error events")
+		return
+	}
+
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		e.log("error", "Cannot subscribe to device events")
+		return
+	}
+
+	oppSub, ok := bluetooth.ObjectPushEvents().Subscribe()
+	if !ok {
+		e.log("error", "Cannot subscribe to file transfer events")
+		return
+	}
+
+	e.connected.seed(e.v.app.Session(), func(device bluetooth.DeviceData) bool {
+		return device.Connected.Value()
+	})
+	e.paired.seed(e.v.app.Session(), func(device bluetooth.DeviceData) bool {
+		return device.Paired.Value()
+	})
+
+	transfers := make(map[bluetooth.ObjectPushTransferID]bluetooth.ObjectPushStatus)
+
+	for {
+		select {
+		case <-errorSub.Done:
+			return
+
+		case err, ok := <-errorSub.AddedEvents:
+			if !ok {
+				return
+			}
+
+			e.log("error", err.Error())
+
+		case ev := <-deviceSub.AddedEvents:
+			e.connected.update(ev.Address, ev.Connected.Value())
+			e.paired.update(ev.Address, ev.Paired.Value())
+
+			e.log("device", "Added "+btdevice.DisplayName(ev.DeviceEventData)+" ("+ev.Address.String()+")")
+
+		case ev := <-deviceSub.RemovedEvents:
+			e.connected.remove(ev.Address)
+			e.paired.remove(ev.Address)
+
+			e.log("device", "Removed "+ev.Address.String())
+
+		case ev := <-deviceSub.UpdatedEvents:
+			// The update events contain all the properties of the device,
+			// so only the changes of the connection and pairing states are logged.
+			if connected, ok := ev.Connected.Get(); ok && e.connected.update(ev.Address, connected) {
+				e.log("device", ev.Address.String()+" connected: "+fmt.Sprint(connected))
+			}
+			if paired, ok := ev.Paired.Get(); ok && e.paired.update(ev.Address, paired) {
+				e.log("device", ev.Address.String()+" paired: "+fmt.Sprint(paired))
+			}
+
+		case ev := <-oppSub.AddedEvents:
+			direction := "Sending"
+			if ev.Receiving {
+				direction = "Receiving"
+			}
+
+			e.log("transfer", fmt.Sprintf("%s '%s' (%s), device %s", direction, ev.Name, formatSize(int64(ev.Size)), ev.Address.String()))
+
+		case ev := <-oppSub.UpdatedEvents:
+			if ev.Status == "" || transfers[ev.TransferID] == ev.Status {
+				continue
+			}
+
+			transfers[ev.TransferID] = ev.Status
+			e.log("transfer", fmt.Sprintf("Transfer %s (device %s) is %s", ev.TransferID, ev.Address.String(), ev.Status))
+
+		case ev := <-oppSub.RemovedEvents:
+			delete(transfers, ev.TransferID)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package views
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/bluetuith-org/bluetooth-classic/api/optional"
+	"github.com/darkhz/tview"
+
+	"github.com/darkhz/bluetuith/ui/config"
+)
+
+// fakeSession is a session with a single adapter and a fixed list of devices.
+// Only the functions which are used by the tests are implemented.
+type fakeSession struct {
+	bluetooth.Session
+
+	adapter bluetooth.AdapterData
+	devices []bluetooth.DeviceData
+	player  bluetooth.MediaPlayer
+}
+
+func (s *fakeSession) Adapters() ([]bluetooth.AdapterData, error) {
+	return []bluetooth.AdapterData{s.adapter}, nil
+}
+
+func (s *fakeSession) Adapter(bluetooth.AdapterAddress) bluetooth.Adapter {
+	return &fakeAdapter{session: s}
+}
+
+func (s *fakeSession) MediaPlayer(bluetooth.DeviceAddress) bluetooth.MediaPlayer {
+	return s.player
+}
+
+// fakeAdapter is the adapter of a fake session.
+type fakeAdapter struct {
+	bluetooth.Adapter
+
+	session *fakeSession
+}
+
+func (a *fakeAdapter) Properties() (bluetooth.AdapterData, error) {
+	return a.session.adapter, nil
+}
+
+func (a *fakeAdapter) Devices() ([]bluetooth.DeviceData, error) {
+	return slices.Clone(a.session.devices), nil
+}
+
+// fakeMediaPlayer is a media player which returns fixed properties,
+// and counts the number of times the properties are retrieved.
+type fakeMediaPlayer struct {
+	bluetooth.MediaPlayer
+
+	properties bluetooth.MediaData
+	calls      atomic.Int32
+}
+
+func (p *fakeMediaPlayer) Properties() (bluetooth.MediaData, error) {
+	p.calls.Add(1)
+
+	return p.properties, nil
+}
+
+// fakeAppBinder binds a fake session to the views. Drawing functions are run immediately,
+// one at a time, instead of being queued to the UI goroutine.
+type fakeAppBinder struct {
+	AppBinder
+
+	session *fakeSession
+	mu      sync.Mutex
+}
+
+func (a *fakeAppBinder) Session() bluetooth.Session {
+	return a.session
+}
+
+func (a *fakeAppBinder) QueueDraw(drawFunc func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	drawFunc()
+}
+
+// newFakeSession returns a fake session with the provided number of simulated devices.
+// Every tenth device is connected, and every other device is paired.
+func newFakeSession(count int) *fakeSession {
+	var adapter bluetooth.AdapterData
+	adapter.Address = bluetooth.MacAddress{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	adapter.UniqueName = "hci0"
+
+	session := &fakeSession{adapter: adapter}
+	for i := range count {
+		var device bluetooth.DeviceData
+
+		device.Address = bluetooth.MacAddress{0, 0, 0, 0, byte(i >> 8), byte(i)}
+		device.AssociatedAdapter = adapter.Address
+		device.Type = "Phone"
+		device.Name = optional.New("Device " + strconv.Itoa(i))
+		device.Connected = optional.New(i%10 == 0)
+		device.Paired = optional.New(i%2 == 0)
+
+		session.devices = append(session.devices, device)
+	}
+
+	return session
+}
+
+// newFakeViews returns the views which are required to list the devices of the session.
+func newFakeViews(session *fakeSession) *Views {
+	v := &Views{
+		cfg: config.NewConfig(),
+		app: &fakeAppBinder{session: session},
+	}
+
+	v.adapter = &adapterView{Views: v}
+	v.adapter.currentAdapter.Store(&session.adapter)
+
+	v.audioProfiles = &audioProfilesView{Views: v}
+	v.network = &networkView{Views: v}
+
+	v.device = &deviceView{
+		table:     tview.NewTable(),
+		conns:     newConnectionTimes(),
+		connected: newDeviceStates(),
+		selection: newDeviceSelection(),
+		pending:   newPendingDevices(),
+		rows:      make(map[bluetooth.DeviceAddress]int),
+		Views:     v,
+	}
+	v.device.sortMode.Store(deviceSortConnected.String())
+
+	return v
+}
@@ -25,16 +25,21 @@ const filePickerPage viewName = "filepicker"
 type filePickerView struct {
 	isSupported bool
 
-	table          *tview.Table
-	title, buttons *tview.TextView
-	pickerFlex     *tview.Flex
+	table                     *tview.Table
+	infoTitle, title, buttons *tview.TextView
+	pickerFlex                *tview.Flex
 
 	prevDir, currentPath string
 	isHidden             atomic.Bool
+	filter               atomic.String
+	sortMode             atomic.String
+	sortReverse          atomic.Bool
 
 	listChan     chan []string
 	prevFileInfo fs.DirEntry
 
+	search typeSearch
+
 	selectedFiles map[string]fs.DirEntry
 	selectMu      sync.Mutex
 
@@ -43,21 +48,50 @@ type filePickerView struct {
 	*Views
 }
 
+// fileSortMode describes the order in which files are listed in the file picker.
+type fileSortMode string
+
+// The different sort modes for the file picker.
+const (
+	fileSortName     fileSortMode = "name"
+	fileSortSize     fileSortMode = "size"
+	fileSortModified fileSortMode = "modified"
+)
+
+// fileSortModes holds the order in which the sort modes are cycled.
+var fileSortModes = []fileSortMode{
+	fileSortName,
+	fileSortSize,
+	fileSortModified,
+}
+
+// description returns a description of the sort mode.
+func (s fileSortMode) description() string {
+	switch s {
+	case fileSortSize:
+		return "size"
+
+	case fileSortModified:
+		return "modified time"
+	}
+
+	return "name"
+}
+
 const filePickButtonRegion = `["ok"][::b][OK[][""] ["cancel"][::b][Cancel[][""] ["hidden"][::b][Toggle hidden[][""] ["invert"][Invert selection[][""] ["all"][Select All[][""]`
 
 // Initialize initializes the file picker.
 func (f *filePickerView) Initialize() error {
 	f.reset()
 
-	infoTitle := tview.NewTextView()
-	infoTitle.SetDynamicColors(true)
-	infoTitle.SetTextAlign(tview.AlignCenter)
-	infoTitle.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
-	infoTitle.SetText(theme.ColorWrap(theme.ThemeText, "Select files to send", "::bu"))
+	f.infoTitle = tview.NewTextView()
+	f.infoTitle.SetDynamicColors(true)
+	f.infoTitle.SetTextAlign(tview.AlignCenter)
+	f.infoTitle.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
 	f.pickerFlex = tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(infoTitle, 1, 0, false).
+		AddItem(f.infoTitle, 1, 0, false).
 		AddItem(nil, 1, 0, false).
 		AddItem(f.filePickerTitle(), 1, 0, false).
 		AddItem(nil, 1, 0, false).
@@ -80,15 +114,21 @@ func (f *filePickerView) SetRootView(v *Views) {
 	f.Views = v
 }
 
-// Show shows a file picker, and returns
-// a list of all the selected files.
-func (f *filePickerView) Show() ([]string, error) {
+// Show shows a file picker, and returns a list of all the selected files.
+// The name of the device, which the files will be sent to, is displayed in the title.
+func (f *filePickerView) Show(deviceName string) ([]string, error) {
 	if !f.isSupported {
 		return nil, errors.New("the filepicker cannot be opened since sending files is not supported")
 	}
 
+	title := "Select files to send"
+	if deviceName != "" {
+		title += " to " + deviceName
+	}
+
 	f.reset()
 	f.app.QueueDraw(func() {
+		f.infoTitle.SetText(theme.ColorWrap(theme.ThemeText, tview.Escape(title), "::bu"))
 		f.pages.AddAndSwitchToPage(filePickerPage.String(), f.pickerFlex, true)
 		go f.changeDir(false, false)
 	})
@@ -109,7 +149,18 @@ func (f *filePickerView) filePickerTable() *tview.Table {
 	f.table.SetSelectable(true, false)
 	f.table.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 	f.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if query, ok := f.search.input(event); ok {
+			f.find(query)
+			return nil
+		}
+
 		switch f.kb.Key(event, keybindings.ContextFiles) {
+		case keybindings.KeyFind:
+			f.search.start()
+			f.find("")
+
+			return nil
+
 		case keybindings.KeyFilebrowserDirForward:
 			go f.changeDir(true, false)
 
@@ -123,6 +174,26 @@ func (f *filePickerView) filePickerTable() *tview.Table {
 		case keybindings.KeyFilebrowserRefresh:
 			go f.changeDir(false, false)
 
+		case keybindings.KeyFilebrowserFilter:
+			go f.setFilter()
+
+		case keybindings.KeyFilebrowserSort:
+			f.nextSortMode()
+			go f.changeDir(false, false)
+
+		case keybindings.KeyFilebrowserSortReverse:
+			f.sortReverse.Toggle()
+			go f.changeDir(false, false)
+
+		case keybindings.KeyFilebrowserGoTo:
+			go f.goToPath()
+
+		case keybindings.KeyFilebrowserBookmarks:
+			f.showBookmarks()
+
+		case keybindings.KeyFilebrowserToggleBookmark:
+			go f.toggleBookmark()
+
 		case keybindings.KeyFilebrowserConfirmSelection:
 			f.sendFileList()
 			fallthrough
@@ -136,12 +207,22 @@ func (f *filePickerView) filePickerTable() *tview.Table {
 		case keybindings.KeyHelp:
 			f.help.showHelp()
 
+		case keybindings.KeyHelpContext:
+			f.help.showContextHelp()
+
 		case keybindings.KeyFilebrowserSelectAll, keybindings.KeyFilebrowserInvertSelection, keybindings.KeyFilebrowserSelect:
 			f.selectFile(event.Rune())
 		}
 
 		return ignoreDefaultEvent(event)
 	})
+	f.table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if f.scrollTable(f.table, action, event) {
+			return action, nil
+		}
+
+		return action, event
+	})
 
 	return f.table
 }
@@ -222,10 +303,10 @@ func (f *filePickerView) changeDir(cdFwd bool, cdBack bool) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	if f.currentPath == "" {
+	if f.currentPath == "" || !isDirectory(f.currentPath) {
 		var err error
 
-		f.currentPath, err = os.UserHomeDir()
+		f.currentPath, err = f.startDir()
 		if err != nil {
 			f.status.ErrorMessage(err)
 			return
@@ -259,28 +340,110 @@ func (f *filePickerView) changeDir(cdFwd bool, cdBack bool) {
 		testPath = f.trimPath(testPath, cdBack)
 	}
 
+	f.openDir(testPath, cdBack)
+}
+
+// changeDirTo changes to the provided directory and lists its contents.
+func (f *filePickerView) changeDirTo(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.openDir(path, false) {
+		f.status.ErrorMessage(fmt.Errorf("the directory '%s' could not be listed", path))
+	}
+}
+
+// openDir lists and displays the contents of the provided directory,
+// and returns whether the directory could be listed. This must be called with the lock held.
+func (f *filePickerView) openDir(testPath string, cdBack bool) bool {
 	dlist, listed := f.dirList(filepath.FromSlash(testPath))
 	if !listed {
-		return
+		return false
 	}
 
-	slices.SortFunc(dlist, func(i, j fs.DirEntry) int {
+	f.sortDirList(dlist)
+
+	f.currentPath = testPath
+
+	f.createDirList(dlist, cdBack)
+
+	return true
+}
+
+// sortDirList sorts the directory entries according to the current sort mode.
+// Directories are always listed first and ordered by their names, and only
+// the files are ordered by the sort mode.
+func (f *filePickerView) sortDirList(dlist []fs.DirEntry) {
+	mode := f.getSortMode()
+	reverse := f.sortReverse.Load()
+
+	infos := make(map[string]fs.FileInfo, len(dlist))
+	if mode != fileSortName {
+		for _, entry := range dlist {
+			if info, err := entry.Info(); err == nil {
+				infos[entry.Name()] = info
+			}
+		}
+	}
+
+	slices.SortStableFunc(dlist, func(i, j fs.DirEntry) int {
 		if i.IsDir() != j.IsDir() {
-			if i.IsDir() && !j.IsDir() {
+			if i.IsDir() {
 				return -1
 			}
 
-			if !i.IsDir() && j.IsDir() {
-				return 1
+			return 1
+		}
+
+		if i.IsDir() {
+			return cmp.Compare(i.Name(), j.Name())
+		}
+
+		var result int
+
+		iinfo, iok := infos[i.Name()]
+		jinfo, jok := infos[j.Name()]
+
+		if iok && jok {
+			switch mode {
+			case fileSortSize:
+				result = cmp.Compare(iinfo.Size(), jinfo.Size())
+
+			case fileSortModified:
+				result = iinfo.ModTime().Compare(jinfo.ModTime())
 			}
 		}
 
-		return cmp.Compare(i.Name(), j.Name())
+		if result == 0 {
+			result = cmp.Compare(i.Name(), j.Name())
+		}
+
+		if reverse {
+			result = -result
+		}
+
+		return result
 	})
+}
 
-	f.currentPath = testPath
+// getSortMode returns the current sort mode of the file picker.
+func (f *filePickerView) getSortMode() fileSortMode {
+	mode := fileSortMode(f.sortMode.Load())
+	if !slices.Contains(fileSortModes, mode) {
+		return fileSortName
+	}
 
-	f.createDirList(dlist, cdBack)
+	return mode
+}
+
+// nextSortMode switches to the next sort mode of the file picker.
+func (f *filePickerView) nextSortMode() {
+	next := fileSortModes[0]
+	if index := slices.Index(fileSortModes, f.getSortMode()); index >= 0 {
+		next = fileSortModes[(index+1)%len(fileSortModes)]
+	}
+
+	f.sortMode.Store(string(next))
 }
 
 // createDirList displays the contents of the directory in the f.
@@ -385,7 +548,18 @@ func (f *filePickerView) createDirList(dlist []fs.DirEntry, cdBack bool) {
 			f.markFileSelection(row, entry, f.checkFileSelected(filepath.Join(f.currentPath, name)))
 		}
 
-		f.title.SetText(theme.ColorWrap(theme.ThemeText, "Directory: "+f.currentPath))
+		title := "Directory: " + f.currentPath
+		if filter := f.getFilter(); filter != "" {
+			title += " (Filter: " + filter + ")"
+		}
+
+		sortDescription := "Sort: " + f.getSortMode().description()
+		if f.sortReverse.Load() {
+			sortDescription += ", reversed"
+		}
+		title += " (" + sortDescription + ")"
+
+		f.title.SetText(theme.ColorWrap(theme.ThemeText, tview.Escape(title)))
 
 		f.table.ScrollToBeginning()
 		f.table.SetSelectable(true, false)
@@ -422,6 +596,10 @@ func (f *filePickerView) dirList(testPath string) ([]fs.DirEntry, bool) {
 			continue
 		}
 
+		if !entry.IsDir() && !f.matchFilter(entry.Name()) {
+			continue
+		}
+
 		dlist = append(dlist, entry)
 	}
 
@@ -443,6 +621,10 @@ func (f *filePickerView) buttonHandler(button string) {
 	case "cancel":
 		close(f.listChan)
 
+		if f.cfg.Values.FilePickerRememberDir {
+			go f.saveLastDir()
+		}
+
 		f.pages.RemovePage(filePickerPage.String())
 		f.pages.SwitchToPage(devicePage.String())
 
@@ -540,6 +722,25 @@ func (f *filePickerView) removeFileSelection(path string) {
 	delete(f.selectedFiles, path)
 }
 
+// selectedSize returns the total size of all the selected files.
+func (f *filePickerView) selectedSize() int64 {
+	f.selectMu.Lock()
+	defer f.selectMu.Unlock()
+
+	var size int64
+
+	for _, entry := range f.selectedFiles {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		size += info.Size()
+	}
+
+	return size
+}
+
 // checkFileSelected checks if a file is selected.
 func (f *filePickerView) checkFileSelected(path string) bool {
 	f.selectMu.Lock()
@@ -571,6 +772,35 @@ func (f *filePickerView) markFileSelection(row int, info fs.DirEntry, selected b
 	cell.Text = theme.ColorWrap(theme.ThemeText, cell.Text)
 }
 
+// startDir returns the directory to start the file picker in.
+// This is the last used directory if it is remembered and still exists, or the home directory.
+func (f *filePickerView) startDir() (string, error) {
+	if lastDir := f.cfg.Values.FilePickerLastDir; f.cfg.Values.FilePickerRememberDir && lastDir != "" && isDirectory(lastDir) {
+		return lastDir, nil
+	}
+
+	return os.UserHomeDir()
+}
+
+// saveLastDir stores the current directory of the file picker in the configuration,
+// so that the file picker can be reopened there in later sessions.
+func (f *filePickerView) saveLastDir() {
+	f.mu.Lock()
+	path := f.currentPath
+	f.mu.Unlock()
+
+	if path == "" || path == f.cfg.Values.FilePickerLastDir {
+		return
+	}
+
+	if err := f.cfg.Save("file-picker-last-dir", path); err != nil {
+		f.status.ErrorMessage(err)
+		return
+	}
+
+	f.cfg.Values.FilePickerLastDir = path
+}
+
 // trimPath trims a given path and appends a path separator where appropriate.
 func (f *filePickerView) trimPath(testPath string, cdBack bool) string {
 	testPath = filepath.Clean(testPath)
@@ -592,6 +822,178 @@ func (f *filePickerView) toggleHidden() {
 	f.isHidden.Store(!f.isHidden.Load())
 }
 
+// goToPath prompts for the absolute path of a directory, and changes to it.
+func (f *filePickerView) goToPath() {
+	path := strings.TrimSpace(f.status.SetInput("Go to directory:", struct{}{}))
+	if path == "" {
+		return
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~"+string(os.PathSeparator)) {
+		homedir, err := os.UserHomeDir()
+		if err != nil {
+			f.status.ErrorMessage(err)
+			return
+		}
+
+		path = filepath.Join(homedir, path[1:])
+	}
+
+	if !filepath.IsAbs(path) {
+		f.status.ErrorMessage(fmt.Errorf("the path '%s' is not an absolute path", path))
+		return
+	}
+
+	path = filepath.Clean(path)
+
+	statpath, err := os.Lstat(path)
+	if err == nil && statpath.Mode()&fs.ModeSymlink != 0 {
+		statpath, err = os.Stat(path)
+	}
+	if err != nil {
+		f.status.ErrorMessage(fmt.Errorf("the directory '%s' does not exist", path))
+		return
+	}
+	if !statpath.IsDir() {
+		f.status.ErrorMessage(fmt.Errorf("the path '%s' is not a directory", path))
+		return
+	}
+
+	f.changeDirTo(path)
+}
+
+// showBookmarks shows a popup with the list of bookmarked directories.
+func (f *filePickerView) showBookmarks() {
+	bookmarks := slices.Clone(f.cfg.Values.FileBookmarks)
+	if len(bookmarks) == 0 {
+		f.status.InfoMessage("No directories have been bookmarked", false)
+		return
+	}
+
+	var width int
+
+	bookmarksModal := f.modals.newModalWithTable("bookmarks", "Bookmarks", 0, 0)
+	bookmarksModal.table.SetSelectedFunc(func(row, _ int) {
+		path, ok := bookmarksModal.table.GetCell(row, 0).GetReference().(string)
+		if !ok {
+			return
+		}
+
+		bookmarksModal.remove(false)
+
+		if !isDirectory(path) {
+			f.status.ErrorMessage(fmt.Errorf("the bookmarked directory '%s' is not accessible", path))
+			return
+		}
+
+		go f.changeDirTo(path)
+	})
+
+	for row, path := range bookmarks {
+		width = max(width, len(path))
+
+		bookmarksModal.table.SetCell(
+			row, 0, tview.NewTableCell(tview.Escape(path)).
+				SetExpansion(1).
+				SetReference(path).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(theme.ThemeText)).
+				SetSelectedStyle(
+					tcell.Style{}.
+						Bold(true).Reverse(true),
+				),
+		)
+	}
+
+	bookmarksModal.height = min(len(bookmarks)+4, 30)
+	bookmarksModal.width = min(max(width+6, 40), 100)
+
+	bookmarksModal.show()
+}
+
+// toggleBookmark adds the current directory to the list of bookmarks,
+// or removes it if it is already bookmarked. The bookmarks are saved to the configuration.
+func (f *filePickerView) toggleBookmark() {
+	f.mu.Lock()
+	path := f.currentPath
+	f.mu.Unlock()
+
+	if path == "" {
+		return
+	}
+
+	message := "Bookmarked "
+	bookmarks := slices.Clone(f.cfg.Values.FileBookmarks)
+
+	if index := slices.Index(bookmarks, path); index >= 0 {
+		bookmarks = slices.Delete(bookmarks, index, index+1)
+		message = "Removed the bookmark for "
+	} else {
+		bookmarks = append(bookmarks, path)
+	}
+
+	if err := f.cfg.Save("file-bookmarks", bookmarks); err != nil {
+		f.status.ErrorMessage(err)
+		return
+	}
+
+	f.cfg.Values.FileBookmarks = bookmarks
+	f.status.InfoMessage(message+path, false)
+}
+
+// find selects the first directory entry whose name starts with the search query.
+func (f *filePickerView) find(query string) {
+	found := f.search.jump(f.table, query, func(row int) (string, bool) {
+		entry, ok := f.table.GetCell(row, 1).GetReference().(fs.DirEntry)
+		if !ok || entry == f.prevFileInfo {
+			return "", false
+		}
+
+		return entry.Name(), true
+	})
+
+	showFindQuery(f.status, query, found)
+}
+
+// setFilter prompts for a pattern to filter the listed files with.
+// Directories are always listed, and an empty pattern clears the filter.
+func (f *filePickerView) setFilter() {
+	filter := strings.TrimSpace(f.status.SetInput("Filter files (e.g. *.jpg):", struct{}{}))
+	if filter != "" {
+		if _, err := filepath.Match(filter, ""); err != nil {
+			f.status.ErrorMessage(fmt.Errorf("the filter pattern '%s' is invalid", filter))
+			return
+		}
+	}
+
+	f.filter.Store(filter)
+	f.changeDir(false, false)
+}
+
+// getFilter returns the pattern that the listed files are filtered with.
+func (f *filePickerView) getFilter() string {
+	return f.filter.Load()
+}
+
+// matchFilter checks if the provided file name matches the filter pattern.
+func (f *filePickerView) matchFilter(name string) bool {
+	filter := f.getFilter()
+	if filter == "" {
+		return true
+	}
+
+	matched, _ := filepath.Match(strings.ToLower(filter), strings.ToLower(name))
+
+	return matched
+}
+
+// isDirectory checks if the provided path exists and is a directory.
+func isDirectory(path string) bool {
+	statpath, err := os.Stat(path)
+
+	return err == nil && statpath.IsDir()
+}
+
 // formatSize returns the human readable form of a size value in bytes.
 // Adapted from: https://yourbasic.org/golang/formatting-byte-size-to-human-readable-format/
 func formatSize(size int64) string {
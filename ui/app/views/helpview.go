@@ -1,7 +1,10 @@
 package views
 
 import (
+	"maps"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/darkhz/bluetuith/ui/keybindings"
 	"github.com/darkhz/bluetuith/ui/theme"
@@ -20,6 +23,14 @@ type helpView struct {
 	*Views
 }
 
+// helpTopicPages maps the pages to the titles of their help topics.
+var helpTopicPages = map[string]string{
+	devicePage.String():      "Device Screen",
+	filePickerPage.String():  "File Picker",
+	remoteFilesPage.String(): "Remote Files",
+	progressPage.String():    "Progress View",
+}
+
 // Initialize initializes the help view.
 func (h *helpView) Initialize() error {
 	if !h.cfg.Values.NoHelpDisplay {
@@ -40,6 +51,31 @@ func (h *helpView) SetRootView(v *Views) {
 	h.Views = v
 }
 
+// reload applies the help display setting after the configuration is reloaded.
+func (h *helpView) reload(noHelpDisplay bool) {
+	if noHelpDisplay != h.cfg.Values.NoHelpDisplay {
+		if h.cfg.Values.NoHelpDisplay {
+			if h.area != nil {
+				h.layout.RemoveItem(h.area)
+			}
+		} else {
+			if h.area == nil {
+				h.area = tview.NewFlex().
+					SetDirection(tview.FlexRow).
+					AddItem(horizontalLine(), 1, 0, false).
+					AddItem(h.status.Help, 1, 0, false)
+			}
+
+			h.statusHelpArea(true)
+		}
+	}
+
+	page := h.page
+	h.page = ""
+	h.status.Help.Clear()
+	h.showStatusHelp(page)
+}
+
 // statusHelpArea shows or hides the status help text.
 func (h *helpView) statusHelpArea(add bool) {
 	if h.cfg.Values.NoHelpDisplay {
@@ -74,13 +110,8 @@ func (h *helpView) showStatusHelp(page string) {
 	}
 
 	h.page = page
-	pages := map[string]string{
-		devicePage.String():     "Device Screen",
-		filePickerPage.String(): "File Picker",
-		progressPage.String():   "Progress View",
-	}
 
-	items, ok := h.topics[pages[page]]
+	items, ok := h.topics[helpTopicPages[page]]
 	if !ok {
 		h.status.Help.Clear()
 		return
@@ -89,7 +120,7 @@ func (h *helpView) showStatusHelp(page string) {
 	groups := map[string][]HelpData{}
 
 	for _, item := range items {
-		if !item.ShowInStatus {
+		if !item.ShowInStatus || !slices.ContainsFunc(item.Keys, h.kb.IsBound) {
 			continue
 		}
 
@@ -103,9 +134,12 @@ func (h *helpView) showStatusHelp(page string) {
 			case keybindings.KeyFilebrowserSelect, keybindings.KeyFilebrowserInvertSelection, keybindings.KeyFilebrowserSelectAll:
 				group = "Select"
 
-			case keybindings.KeyProgressTransferSuspend, keybindings.KeyProgressTransferResume, keybindings.KeyProgressTransferCancel:
+			case keybindings.KeyProgressTransferSuspend, keybindings.KeyProgressTransferResume, keybindings.KeyProgressTransferCancel, keybindings.KeyProgressTransferToggleAll:
 				group = "Transfer"
 
+			case keybindings.KeyProgressQueueFront, keybindings.KeyProgressQueueCancel:
+				group = "Queue"
+
 			case keybindings.KeyDeviceConnect, keybindings.KeyDevicePair, keybindings.KeyAdapterToggleScan, keybindings.KeyAdapterTogglePower:
 				group = "Toggle"
 			}
@@ -133,7 +167,9 @@ func (h *helpView) showStatusHelp(page string) {
 				names = append(names, item.Title)
 			}
 			for _, k := range item.Keys {
-				keys = append(keys, h.kb.Name(h.kb.Data(k).Kb))
+				if h.kb.IsBound(k) {
+					keys = append(keys, h.kb.KeyName(k))
+				}
 			}
 		}
 		if names != nil {
@@ -158,51 +194,130 @@ func (h *helpView) showStatusHelp(page string) {
 
 // showHelp displays a modal with the help items for all the screens.
 func (h *helpView) showHelp() {
-	var row int
+	h.showHelpModal("help", "Help", h.topics)
+}
+
+// showContextHelp displays a modal with the help items for the currently focused screen.
+// Only the items whose actions are currently available are displayed, for example, the
+// item to send files is hidden if the selected device does not support file transfers.
+func (h *helpView) showContextHelp() {
+	page := h.pages.currentPage()
+
+	names := []string{helpTopicPages[page]}
+	if page == devicePage.String() && h.player.isOpen.Load() {
+		names = append(names, "Media Player")
+	}
 
-	helpModal := h.modals.newModalWithTable("help", "Help", 40, 60)
+	topics := map[string][]HelpData{}
+	for _, name := range names {
+		for _, item := range h.topics[name] {
+			if !slices.ContainsFunc(item.Keys, h.actions.available) {
+				continue
+			}
+
+			topics[name] = append(topics[name], item)
+		}
+	}
+
+	if len(topics) == 0 {
+		h.status.InfoMessage("No actions are available on this screen", false)
+		return
+	}
+
+	h.showHelpModal("contexthelp", "Help ("+names[0]+")", topics)
+}
+
+// showHelpModal displays a modal with the provided help topics.
+// Pressing '/' starts a search, after which typing filters the help items by their
+// descriptions and key names. Other keys are handled by the modal until the search is started.
+func (h *helpView) showHelpModal(name, title string, topics map[string][]HelpData) {
+	var query string
+	var searching bool
+
+	helpModal := h.modals.newModalWithTable(name, title, 40, 60)
 	helpModal.table.SetSelectionChangedFunc(func(row, _ int) {
 		if row == 1 {
 			helpModal.table.ScrollToBeginning()
 		}
 	})
-	helpModal.table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
-		if action == tview.MouseScrollUp {
-			helpModal.table.InputHandler()(tcell.NewEventKey(tcell.KeyUp, ' ', tcell.ModNone), nil)
+
+	inputCapture := helpModal.table.GetInputCapture()
+	helpModal.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case !searching:
+			if event.Key() != tcell.KeyRune || event.Rune() != '/' {
+				return inputCapture(event)
+			}
+
+			searching = true
+
+		case h.kb.Key(event) == keybindings.KeyClose:
+			query, searching = "", false
+
+		case event.Key() == tcell.KeyRune && event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0:
+			query += string(event.Rune())
+
+		case (event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2) && query != "":
+			_, size := utf8.DecodeLastRuneInString(query)
+			query = query[:len(query)-size]
+
+		default:
+			return inputCapture(event)
 		}
 
-		return action, event
+		found := h.drawHelpItems(helpModal.table, topics, query)
+		showFindQuery(h.status, query, found)
+
+		return nil
 	})
 
-	for title, helpItems := range h.topics {
-		helpModal.table.SetCell(
-			row, 0, tview.NewTableCell("[::bu]"+title).
-				SetSelectable(false).
-				SetAlign(tview.AlignCenter).
-				SetTextColor(theme.GetColor(theme.ThemeText)),
-		)
+	h.drawHelpItems(helpModal.table, topics, "")
+
+	helpModal.show()
+}
+
+// drawHelpItems draws the help items of the provided topics which match the query onto the table,
+// and highlights the matching text. It returns whether any help item matched the query.
+func (h *helpView) drawHelpItems(table *tview.Table, topics map[string][]HelpData, query string) bool {
+	var row int
 
+	table.Clear()
+	query = strings.ToLower(query)
+
+	for _, title := range slices.Sorted(maps.Keys(topics)) {
+		titleRow := row
 		row++
 
-		for _, item := range helpItems {
+		for _, item := range topics[title] {
 			var names []string
 
 			for _, k := range item.Keys {
-				names = append(names, h.kb.Name(h.kb.Data(k).Kb))
+				if h.kb.IsBound(k) {
+					names = append(names, h.kb.KeyName(k))
+				}
+			}
+			if names == nil {
+				continue
 			}
 
 			keybinding := strings.Join(names, "/")
 
-			helpModal.table.SetCell(
-				row, 0, tview.NewTableCell(theme.ColorWrap(theme.ThemeText, item.Description)).
+			if query != "" &&
+				!strings.Contains(strings.ToLower(item.Description), query) &&
+				!strings.Contains(strings.ToLower(keybinding), query) {
+				continue
+			}
+
+			table.SetCell(
+				row, 0, tview.NewTableCell(theme.ColorWrap(theme.ThemeText, highlightMatch(item.Description, query))).
 					SetExpansion(1).
 					SetAlign(tview.AlignLeft).
 					SetTextColor(theme.GetColor(theme.ThemeText)).
 					SetSelectedStyle(tcell.Style{}.Reverse(true)),
 			)
 
-			helpModal.table.SetCell(
-				row, 1, tview.NewTableCell(theme.ColorWrap(theme.ThemeText, keybinding)).
+			table.SetCell(
+				row, 1, tview.NewTableCell(theme.ColorWrap(theme.ThemeText, highlightMatch(keybinding, query))).
 					SetExpansion(0).
 					SetAlign(tview.AlignLeft).
 					SetTextColor(theme.GetColor(theme.ThemeText)).
@@ -212,11 +327,38 @@ func (h *helpView) showHelp() {
 			row++
 		}
 
+		if row == titleRow+1 {
+			row = titleRow
+			continue
+		}
+
+		table.SetCell(
+			titleRow, 0, tview.NewTableCell("[::bu]"+title).
+				SetSelectable(false).
+				SetAlign(tview.AlignCenter).
+				SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+
 		row++
+	}
+
+	table.Select(1, 0)
+	table.ScrollToBeginning()
+
+	return row > 0
+}
 
+// highlightMatch escapes the provided text, and underlines the first case-insensitive
+// occurrence of the lowercase query within it.
+func highlightMatch(text, query string) string {
+	index := strings.Index(strings.ToLower(text), query)
+	if query == "" || index < 0 || len(strings.ToLower(text)) != len(text) {
+		return tview.Escape(text)
 	}
 
-	helpModal.show()
+	end := index + len(query)
+
+	return tview.Escape(text[:index]) + "[::u]" + tview.Escape(text[index:end]) + "[::U]" + tview.Escape(text[end:])
 }
 
 // HelpData describes the help item.
@@ -231,6 +373,7 @@ func (h *helpView) initHelpData() {
 	h.topics = map[string][]HelpData{
 		"Device Screen": {
 			{"Menu", "Open the menu", []keybindings.Key{keybindings.KeyMenu}, true},
+			{"Device Menu", "Open the device menu", []keybindings.Key{keybindings.KeyMenuDevice}, false},
 			{"Switch", "Navigate between menus", []keybindings.Key{keybindings.KeySwitch}, true},
 			{"Navigation", "Navigate between devices/options", []keybindings.Key{keybindings.KeyNavigateUp, keybindings.KeyNavigateDown}, true},
 			{"Power", "Toggle adapter power state", []keybindings.Key{keybindings.KeyAdapterTogglePower}, true},
@@ -238,17 +381,43 @@ func (h *helpView) initHelpData() {
 			{"Pairable", "Toggle pairable state", []keybindings.Key{keybindings.KeyAdapterTogglePairable}, false},
 			{"Scan", "Toggle scan (discovery state)", []keybindings.Key{keybindings.KeyAdapterToggleScan}, true},
 			{"Adapter", "Change adapter", []keybindings.Key{keybindings.KeyAdapterChange}, true},
+			{"Rename Adapter", "Set an alias for the current adapter", []keybindings.Key{keybindings.KeyAdapterRename}, false},
+			{"Reset Adapter", "Power cycle the current adapter", []keybindings.Key{keybindings.KeyAdapterReset}, false},
+			{"Adapter Info", "Show adapter information", []keybindings.Key{keybindings.KeyAdapterInfo}, false},
 			{"Send", "Send files", []keybindings.Key{keybindings.KeyDeviceSendFiles}, true},
-			{"Network", "Connect to network", []keybindings.Key{keybindings.KeyDeviceNetwork}, false},
+			{"Browse", "Browse files on the device", []keybindings.Key{keybindings.KeyDeviceBrowseFiles}, false},
+			{"Network", "Connect to network, or disconnect the active network connection", []keybindings.Key{keybindings.KeyDeviceNetwork, keybindings.KeyDeviceNetworkDisconnect}, false},
 			{"Progress", "Progress view", []keybindings.Key{keybindings.KeyProgressView}, false},
+			{"Audio Mode", "Switch the device to high quality (A2DP) or headset (HFP/HSP) audio", []keybindings.Key{keybindings.KeyDeviceAudioHighQuality, keybindings.KeyDeviceAudioHeadset}, false},
+			{"Default Output", "Set the device as the default audio output of the system", []keybindings.Key{keybindings.KeyDeviceAudioDefault}, false},
 			{"Player", "Show/Hide player", []keybindings.Key{keybindings.KeyPlayerShow, keybindings.KeyPlayerHide}, false},
 			{"Device Info", "Show device information", []keybindings.Key{keybindings.KeyDeviceInfo}, false},
+			{"Profiles", "Connect (Enter) or disconnect individual profiles of the device", []keybindings.Key{keybindings.KeyDeviceProfiles, keybindings.KeyDeviceProfileDisconnect}, false},
+			{"Favorite", "Pin/Unpin the device to the top of the list", []keybindings.Key{keybindings.KeyDeviceFavorite}, false},
+			{"Set Default", "Connect to the device once the adapter is powered on", []keybindings.Key{keybindings.KeyDeviceSetDefault}, false},
+			{"Copy", "Copy the device address (or the selected value, in the information window)", []keybindings.Key{keybindings.KeyDeviceCopyAddress}, false},
+			{"Copy Information", "Copy all the information, in the information window", []keybindings.Key{keybindings.KeyDeviceCopyInfo}, false},
+			{"Sort", "Change the sort order of devices", []keybindings.Key{keybindings.KeyDeviceSort}, false},
+			{"Signal Filter", "Hide discovered devices below a signal strength", []keybindings.Key{keybindings.KeyDeviceMinRSSI}, false},
 			{"Connect", "Toggle connection with selected device", []keybindings.Key{keybindings.KeyDeviceConnect}, true},
+			{"Connect Last", "Connect to the most recently connected device", []keybindings.Key{keybindings.KeyDeviceConnectLast}, false},
 			{"Pair", "Toggle pair with selected device", []keybindings.Key{keybindings.KeyDevicePair}, true},
 			{"Trust", "Toggle trust with selected device", []keybindings.Key{keybindings.KeyDeviceTrust}, false},
-			{"Remove", "Remove device from adapter", []keybindings.Key{keybindings.KeyDeviceRemove}, false},
+			{"Trust and Connect", "Trust and connect to selected device", []keybindings.Key{keybindings.KeyDeviceTrustConnect}, false},
+			{"Rename", "Set an alias for the selected device", []keybindings.Key{keybindings.KeyDeviceRename}, false},
+			{"Remove", "Remove device from adapter and delete its pairing keys, so that a device with a stale bond can be paired again", []keybindings.Key{keybindings.KeyDeviceRemove}, false},
+			{"Identify", "Make the selected device beep or vibrate, if it supports the Immediate Alert service", []keybindings.Key{keybindings.KeyDeviceIdentify}, false},
+			{"Select", "Select/Unselect the device for batch actions", []keybindings.Key{keybindings.KeyDeviceSelect}, false},
+			{"Batch Actions", "Connect, disconnect, trust or remove all the selected devices", []keybindings.Key{keybindings.KeyDeviceBatch}, false},
 			{"Cancel", "Cancel operation", []keybindings.Key{keybindings.KeyCancel}, false},
-			{"Help", "Show help", []keybindings.Key{keybindings.KeyHelp}, true},
+			{"Find", "Jump to a device by typing its name", []keybindings.Key{keybindings.KeyFind}, false},
+			{"Help", "Show help, type within it to search", []keybindings.Key{keybindings.KeyHelp}, true},
+			{"Context Help", "Show help for the actions which are currently available", []keybindings.Key{keybindings.KeyHelpContext}, false},
+			{"Message Log", "Show the history of status messages", []keybindings.Key{keybindings.KeyMessageLog}, false},
+			{"Clear Log", "Clear the message log (in the message log window)", []keybindings.Key{keybindings.KeyMessageLogClear}, false},
+			{"Reload Config", "Reload the configuration file", []keybindings.Key{keybindings.KeyConfigReload}, false},
+			{"Reload Theme", "Reload the theme file and redraw the views", []keybindings.Key{keybindings.KeyThemeReload}, false},
+			{"Refresh", "Re-read the adapters and devices and redraw the views", []keybindings.Key{keybindings.KeyRefresh}, false},
 			{"Quit", "Quit", []keybindings.Key{keybindings.KeyQuit}, false},
 		},
 		"File Picker": {
@@ -259,14 +428,35 @@ func (h *helpView) initHelpData() {
 			{"All", "Select all files", []keybindings.Key{keybindings.KeyFilebrowserSelectAll}, true},
 			{"Refresh", "Refresh current directory", []keybindings.Key{keybindings.KeyFilebrowserRefresh}, false},
 			{"Hidden", "Toggle hidden files", []keybindings.Key{keybindings.KeyFilebrowserToggleHidden}, false},
+			{"Filter", "Filter files by a pattern", []keybindings.Key{keybindings.KeyFilebrowserFilter}, true},
+			{"Find", "Jump to a file by typing its name", []keybindings.Key{keybindings.KeyFind}, false},
+			{"Sort", "Cycle the sort order of files", []keybindings.Key{keybindings.KeyFilebrowserSort}, true},
+			{"Reverse", "Reverse the sort order of files", []keybindings.Key{keybindings.KeyFilebrowserSortReverse}, false},
+			{"Go To", "Go to a directory by its path", []keybindings.Key{keybindings.KeyFilebrowserGoTo}, true},
+			{"Bookmarks", "Show bookmarked directories", []keybindings.Key{keybindings.KeyFilebrowserBookmarks}, true},
+			{"Bookmark", "Bookmark/Unbookmark current directory", []keybindings.Key{keybindings.KeyFilebrowserToggleBookmark}, false},
 			{"Confirm", "Confirm file(s) selection", []keybindings.Key{keybindings.KeyFilebrowserConfirmSelection}, true},
+			{"Context Help", "Show help for the actions which are currently available", []keybindings.Key{keybindings.KeyHelpContext}, false},
 			{"Exit", "Exit", []keybindings.Key{keybindings.KeyClose}, false},
 		},
+		"Remote Files": {
+			{"Navigation", "Navigate between folder entries", []keybindings.Key{keybindings.KeyNavigateUp, keybindings.KeyNavigateDown}, true},
+			{"ChgDir Fwd/Back", "Enter/Go back a folder", []keybindings.Key{keybindings.KeyNavigateRight, keybindings.KeyNavigateLeft}, true},
+			{"Download", "Download the selected file", []keybindings.Key{keybindings.KeyFilebrowserDownload}, true},
+			{"Upload", "Upload files to the current folder", []keybindings.Key{keybindings.KeyFilebrowserUpload}, true},
+			{"Refresh", "Refresh current folder", []keybindings.Key{keybindings.KeyFilebrowserRefresh}, false},
+			{"Context Help", "Show help for the actions which are currently available", []keybindings.Key{keybindings.KeyHelpContext}, false},
+			{"Exit", "Exit", []keybindings.Key{keybindings.KeyClose}, true},
+		},
 		"Progress View": {
 			{"Navigation", "Navigate between transfers", []keybindings.Key{keybindings.KeyNavigateUp, keybindings.KeyNavigateDown}, true},
 			{"Suspend", "Suspend transfer", []keybindings.Key{keybindings.KeyProgressTransferSuspend}, true},
 			{"Resume", "Resume transfer", []keybindings.Key{keybindings.KeyProgressTransferResume}, true},
-			{"Cancel", "Cancel transfer", []keybindings.Key{keybindings.KeyProgressTransferCancel}, true},
+			{"Cancel", "Cancel transfer or queued file", []keybindings.Key{keybindings.KeyProgressTransferCancel}, true},
+			{"Suspend/Resume All", "Suspend or resume all outgoing transfers", []keybindings.Key{keybindings.KeyProgressTransferToggleAll}, true},
+			{"Front", "Send queued file next", []keybindings.Key{keybindings.KeyProgressQueueFront}, true},
+			{"Cancel All", "Cancel all queued files", []keybindings.Key{keybindings.KeyProgressQueueCancel}, true},
+			{"Context Help", "Show help for the actions which are currently available", []keybindings.Key{keybindings.KeyHelpContext}, false},
 			{"Exit", "Exit", []keybindings.Key{keybindings.KeyClose}, true},
 		},
 		"Media Player": {
@@ -276,6 +466,9 @@ func (h *helpView) initHelpData() {
 			{"Rewind", "Rewind", []keybindings.Key{keybindings.KeyPlayerSeekBackward}, false},
 			{"Forward", "Fast forward", []keybindings.Key{keybindings.KeyPlayerSeekForward}, false},
 			{"Stop", "Stop", []keybindings.Key{keybindings.KeyPlayerStop}, false},
+			{"Seek To", "Seek to a position (mm:ss)", []keybindings.Key{keybindings.KeyPlayerSeekTo}, false},
+			{"Repeat", "Cycle the repeat mode", []keybindings.Key{keybindings.KeyPlayerToggleRepeat}, false},
+			{"Shuffle", "Toggle shuffle", []keybindings.Key{keybindings.KeyPlayerToggleShuffle}, false},
 		},
 	}
 }
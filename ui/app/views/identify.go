@@ -0,0 +1,106 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	bluezGattServiceIface        = "org.bluez.GattService1"
+	bluezGattCharacteristicIface = "org.bluez.GattCharacteristic1"
+
+	immediateAlertServiceClass   = 0x1802
+	immediateAlertServiceUUID    = "00001802-0000-1000-8000-00805f9b34fb"
+	alertLevelCharacteristicUUID = "00002a06-0000-1000-8000-00805f9b34fb"
+)
+
+// The alert levels of the Immediate Alert service.
+const (
+	alertLevelNone byte = 0x00
+	alertLevelHigh byte = 0x02
+)
+
+// identifyDuration is the duration after which a device that is identifying itself is stopped.
+const identifyDuration = 10 * time.Second
+
+// identifyResolveTimeout is the time to wait for the services of a newly connected device to be resolved.
+const identifyResolveTimeout = 5 * time.Second
+
+// errIdentifyUnsupported is returned if the alert level of a device cannot be set.
+var errIdentifyUnsupported = errors.New("the Immediate Alert service is not available")
+
+// getAlertLevelPath returns the object path of the Alert Level characteristic of the device's
+// Immediate Alert service, using the "org.bluez.GattCharacteristic1" interface. The Link Loss
+// service has an Alert Level characteristic as well, so the service of the characteristic is checked.
+// This is only supported by BlueZ.
+func getAlertLevelPath(adapterName string, address bluetooth.MacAddress) (dbus.ObjectPath, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return "", err
+	}
+
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := conn.Object(bluezDest, "/").
+		Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).
+		Store(&objects); err != nil {
+		return "", err
+	}
+
+	devicePath := string(bluezDevicePath(adapterName, address)) + "/"
+
+	for path, ifaces := range objects {
+		props, ok := ifaces[bluezGattCharacteristicIface]
+		if !ok || !strings.HasPrefix(string(path), devicePath) {
+			continue
+		}
+
+		if uuid, _ := props["UUID"].Value().(string); !strings.EqualFold(uuid, alertLevelCharacteristicUUID) {
+			continue
+		}
+
+		servicePath, _ := props["Service"].Value().(dbus.ObjectPath)
+		if uuid, _ := objects[servicePath][bluezGattServiceIface]["UUID"].Value().(string); strings.EqualFold(uuid, immediateAlertServiceUUID) {
+			return path, nil
+		}
+	}
+
+	return "", errIdentifyUnsupported
+}
+
+// setAlertLevel writes the alert level to the Alert Level characteristic at the provided object path.
+func setAlertLevel(path dbus.ObjectPath, level byte) error {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return err
+	}
+
+	return conn.Object(bluezDest, path).
+		Call(bluezGattCharacteristicIface+".WriteValue", 0, []byte{level}, map[string]dbus.Variant{}).
+		Err
+}
+
+// waitAlertLevelPath returns the object path of the Alert Level characteristic of the device,
+// and waits for the services of the device to be resolved if it has just connected.
+// Waiting is stopped if the context is cancelled.
+func waitAlertLevelPath(ctx context.Context, adapterName string, address bluetooth.MacAddress) (dbus.ObjectPath, error) {
+	deadline := time.Now().Add(identifyResolveTimeout)
+
+	for {
+		path, err := getAlertLevelPath(adapterName, address)
+		if !errors.Is(err, errIdentifyUnsupported) || time.Now().After(deadline) {
+			return path, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package views
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/tview"
+	"github.com/godbus/dbus/v5"
+)
+
+// linkQualityInterval is the interval at which the link quality of a connected device
+// is refreshed in the device information.
+const linkQualityInterval = 2 * time.Second
+
+// linkQuality describes the signal properties of a device, which are used to estimate the quality of its link.
+type linkQuality struct {
+	rssi, txPower       int16
+	hasRSSI, hasTxPower bool
+}
+
+// getLinkQuality returns the signal properties of the device. The RSSI and the TX power are read
+// from the "org.bluez.Device1" interface if possible, and otherwise the RSSI of the provided device
+// is used. BlueZ only reports these values while the device advertises them, for example during
+// discovery, so they may not be available for connected devices.
+func getLinkQuality(adapterName string, device bluetooth.DeviceData) linkQuality {
+	var quality linkQuality
+
+	if rssi, ok := device.RSSI.Get(); ok && rssi < 0 {
+		quality.rssi, quality.hasRSSI = rssi, true
+	}
+
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return quality
+	}
+
+	devicePath := bluezDevicePath(adapterName, device.Address)
+
+	var props map[string]dbus.Variant
+	if err := conn.Object(bluezDest, devicePath).
+		Call("org.freedesktop.DBus.Properties.GetAll", 0, bluezDeviceIface).
+		Store(&props); err != nil {
+		return quality
+	}
+
+	if rssi, ok := props["RSSI"].Value().(int16); ok && rssi < 0 {
+		quality.rssi, quality.hasRSSI = rssi, true
+	}
+	if txPower, ok := props["TxPower"].Value().(int16); ok {
+		quality.txPower, quality.hasTxPower = txPower, true
+	}
+
+	return quality
+}
+
+// rating returns a simple rating of the link quality. If the TX power is known, the path loss
+// is rated, since it does not depend on how strongly the device transmits, otherwise the RSSI is rated.
+func (l linkQuality) rating() string {
+	level := int(l.rssi)
+	thresholds := []int{-55, -67, -80}
+
+	if l.hasTxPower {
+		level = -(int(l.txPower) - int(l.rssi))
+		thresholds = []int{-60, -75, -90}
+	}
+
+	switch {
+	case level >= thresholds[0]:
+		return "Excellent"
+
+	case level >= thresholds[1]:
+		return "Good"
+
+	case level >= thresholds[2]:
+		return "Fair"
+	}
+
+	return "Poor"
+}
+
+// String returns the signal properties and the rating of the link quality.
+func (l linkQuality) String() string {
+	if !l.hasRSSI {
+		return "Not reported"
+	}
+
+	text := strconv.Itoa(int(l.rssi)) + " dBm"
+	if l.hasTxPower {
+		text += ", TX power " + strconv.Itoa(int(l.txPower)) + " dBm"
+	}
+
+	return text + " (" + l.rating() + ")"
+}
+
+// refreshLinkQuality periodically updates the link quality in the provided row of the device
+// information modal, until the modal is closed or the device is disconnected.
+func (d *deviceView) refreshLinkQuality(infoModal *tableModalView, row int, adapterName string, address bluetooth.DeviceAddress) {
+	ticker := time.NewTicker(linkQualityInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		device, err := d.app.Session().Device(address).Properties()
+		if err != nil {
+			return
+		}
+
+		if connected, _ := device.Connected.Get(); !connected {
+			return
+		}
+
+		quality := getLinkQuality(adapterName, device).String()
+
+		var open bool
+		d.app.QueueDraw(func() {
+			if open = infoModal.isOpen; !open {
+				return
+			}
+
+			infoModal.table.SetCell(row, 1, infoModal.table.GetCell(row, 1).
+				SetText(tview.Escape(quality)).
+				SetReference(quality),
+			)
+		})
+		if !open {
+			return
+		}
+	}
+}
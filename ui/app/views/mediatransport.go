@@ -0,0 +1,181 @@
+package views
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/godbus/dbus/v5"
+)
+
+const bluezMediaTransportIface = "org.bluez.MediaTransport1"
+
+// The A2DP codec identifiers, as specified in the A2DP specification.
+const (
+	a2dpCodecSBC    byte = 0x00
+	a2dpCodecMPEG12 byte = 0x01
+	a2dpCodecAAC    byte = 0x02
+	a2dpCodecVendor byte = 0xFF
+)
+
+// mediaTransport describes an A2DP media transport of a device.
+type mediaTransport struct {
+	path       dbus.ObjectPath
+	codec      string
+	sampleRate uint32
+	state      string
+	delay      uint16
+}
+
+// a2dpVendorCodec describes a vendor-specific A2DP codec.
+type a2dpVendorCodec struct {
+	vendorID uint32
+	codecID  uint16
+}
+
+// a2dpVendorCodecs lists the names of the known vendor-specific A2DP codecs.
+var a2dpVendorCodecs = map[a2dpVendorCodec]string{
+	{0x0000004F, 0x0001}: "aptX",
+	{0x000000D7, 0x0024}: "aptX HD",
+	{0x000000D7, 0x0002}: "aptX Low Latency",
+	{0x0000012D, 0x00AA}: "LDAC",
+	{0x000005F1, 0x1005}: "Opus",
+}
+
+// getMediaTransports returns the A2DP media transports of the device, using the
+// "org.bluez.MediaTransport1" interface. This is only supported by BlueZ.
+func getMediaTransports(adapterName string, address bluetooth.MacAddress) ([]mediaTransport, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := conn.Object(bluezDest, "/").
+		Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).
+		Store(&objects); err != nil {
+		return nil, err
+	}
+
+	devicePath := bluezDevicePath(adapterName, address)
+
+	var transports []mediaTransport
+	for path, ifaces := range objects {
+		props, ok := ifaces[bluezMediaTransportIface]
+		if !ok {
+			continue
+		}
+
+		if device, ok := props["Device"].Value().(dbus.ObjectPath); !ok || device != devicePath {
+			continue
+		}
+
+		codec, _ := props["Codec"].Value().(byte)
+		config, _ := props["Configuration"].Value().([]byte)
+		state, _ := props["State"].Value().(string)
+		delay, _ := props["Delay"].Value().(uint16)
+
+		name, sampleRate := parseA2DPCodec(codec, config)
+		transports = append(transports, mediaTransport{
+			path:       path,
+			codec:      name,
+			sampleRate: sampleRate,
+			state:      state,
+			delay:      delay,
+		})
+	}
+
+	slices.SortFunc(transports, func(a, b mediaTransport) int {
+		return cmp.Compare(a.path, b.path)
+	})
+
+	return transports, nil
+}
+
+// parseA2DPCodec returns the codec name and sample rate from the codec identifier
+// and the codec configuration of an A2DP media transport.
+// If the sample rate cannot be determined, it is returned as zero.
+func parseA2DPCodec(codec byte, config []byte) (string, uint32) {
+	switch codec {
+	case a2dpCodecSBC:
+		return "SBC", parseSampleRate(config, 0, map[byte]uint32{
+			0x80: 16000, 0x40: 32000, 0x20: 44100, 0x10: 48000,
+		})
+
+	case a2dpCodecMPEG12:
+		return "MPEG-1,2 Audio", parseSampleRate(config, 1, map[byte]uint32{
+			0x20: 16000, 0x10: 22050, 0x08: 24000, 0x04: 32000, 0x02: 44100, 0x01: 48000,
+		})
+
+	case a2dpCodecAAC:
+		sampleRate := parseSampleRate(config, 1, map[byte]uint32{
+			0x80: 8000, 0x40: 11025, 0x20: 12000, 0x10: 16000,
+			0x08: 22050, 0x04: 24000, 0x02: 32000, 0x01: 44100,
+		})
+		if sampleRate == 0 {
+			sampleRate = parseSampleRate(config, 2, map[byte]uint32{
+				0x80: 48000, 0x40: 64000, 0x20: 88200, 0x10: 96000,
+			})
+		}
+
+		return "AAC", sampleRate
+
+	case a2dpCodecVendor:
+		if len(config) < 6 {
+			break
+		}
+
+		vendor := a2dpVendorCodec{
+			vendorID: binary.LittleEndian.Uint32(config[0:4]),
+			codecID:  binary.LittleEndian.Uint16(config[4:6]),
+		}
+
+		name, ok := a2dpVendorCodecs[vendor]
+		if !ok {
+			return fmt.Sprintf("Vendor (%#08x:%#04x)", vendor.vendorID, vendor.codecID), 0
+		}
+
+		switch name {
+		case "aptX", "aptX HD", "aptX Low Latency":
+			return name, parseSampleRate(config, 6, map[byte]uint32{
+				0x80: 16000, 0x40: 32000, 0x20: 44100, 0x10: 48000,
+			})
+
+		case "LDAC":
+			return name, parseSampleRate(config, 6, map[byte]uint32{
+				0x20: 44100, 0x10: 48000, 0x08: 88200, 0x04: 96000, 0x02: 176400, 0x01: 192000,
+			})
+		}
+
+		return name, 0
+	}
+
+	return "Unknown (" + strconv.Itoa(int(codec)) + ")", 0
+}
+
+// parseSampleRate returns the sample rate from the provided byte of the codec configuration.
+func parseSampleRate(config []byte, index int, rates map[byte]uint32) uint32 {
+	if index >= len(config) {
+		return 0
+	}
+
+	for mask, rate := range rates {
+		if config[index]&mask != 0 {
+			return rate
+		}
+	}
+
+	return 0
+}
+
+// formatSampleRate formats and returns the sample rate in kHz.
+func formatSampleRate(sampleRate uint32) string {
+	if sampleRate == 0 {
+		return "Unknown"
+	}
+
+	return strconv.FormatFloat(float64(sampleRate)/1000, 'f', -1, 64) + " kHz"
+}
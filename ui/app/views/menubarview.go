@@ -26,6 +26,7 @@ type menuBarView struct {
 
 	menuOptions map[string][]menuOption
 	optionByKey map[keybindings.Key]*menuOptionState
+	lastMenu    viewName
 
 	sync.RWMutex
 
@@ -67,6 +68,8 @@ func (m *menuBarView) Initialize() error {
 			return
 		}
 
+		m.lastMenu = viewName(added[0])
+
 		pos := getRegionStartPosition(m.bar, added[0])
 		_, _, w, _ := m.adapter.topAdapterName.GetInnerRect()
 
@@ -83,6 +86,17 @@ func (m *menuBarView) SetRootView(v *Views) {
 	m.Views = v
 }
 
+// open opens the menu at the adapter menu, or at the most recently
+// used menu if the "menu-remember-last" option is set.
+func (m *menuBarView) open() {
+	name := menuAdapterName
+	if m.cfg.Values.MenuRememberLast && m.lastMenu != "" {
+		name = m.lastMenu
+	}
+
+	m.highlight(name)
+}
+
 // highlight highlights the menu's name in the menu bar.
 func (m *menuBarView) highlight(name viewName) {
 	m.bar.Highlight(name.String())
@@ -233,7 +247,7 @@ func (m *menuBarView) setupSubMenu(x, y int, menuID viewName, device ...struct{}
 		newstate := m.toggleMenuItem(optstate, toggle)
 
 		display := newstate.displayText
-		keybinding := m.kb.Name(newstate.kdata.Kb)
+		keybinding := m.kb.KeyName(menuopt.key)
 		clickedFunc := func() bool {
 			m.exit()
 			return m.actions.handler(menuopt.key, actionInvoke)()
@@ -425,6 +439,13 @@ func (m *menuBarView) inputHandler(event *tcell.EventKey) bool {
 	return ok
 }
 
+// isDeviceOption returns whether the keybinding key is attached to an option of the device menu.
+func (m *menuBarView) isDeviceOption(key keybindings.Key) bool {
+	option, ok := m.optionByKey[key]
+
+	return ok && option.menuName == menuDeviceName.String()
+}
+
 // initOrderedOptions initializes and stores the ordered menu options.
 func (m *menuBarView) initOrderedOptions() {
 	m.menuOptions = map[string][]menuOption{
@@ -454,12 +475,43 @@ func (m *menuBarView) initOrderedOptions() {
 			{
 				key: keybindings.KeyAdapterChange,
 			},
+			{
+				key:             keybindings.KeyAdapterRename,
+				checkVisibility: true,
+			},
+			{
+				key: keybindings.KeyAdapterReset,
+			},
+			{
+				key: keybindings.KeyAdapterInfo,
+			},
+			{
+				key: keybindings.KeyDeviceConnectLast,
+			},
+			{
+				key: keybindings.KeyDeviceSort,
+			},
+			{
+				key: keybindings.KeyDeviceMinRSSI,
+			},
 			{
 				key: keybindings.KeyProgressView,
 			},
 			{
 				key: keybindings.KeyPlayerHide,
 			},
+			{
+				key: keybindings.KeyMessageLog,
+			},
+			{
+				key: keybindings.KeyConfigReload,
+			},
+			{
+				key: keybindings.KeyThemeReload,
+			},
+			{
+				key: keybindings.KeyRefresh,
+			},
 			{
 				key: keybindings.KeyQuit,
 			},
@@ -478,6 +530,9 @@ func (m *menuBarView) initOrderedOptions() {
 				disabledText:     "Untrust",
 				initBeforeInvoke: true,
 			},
+			{
+				key: keybindings.KeyDeviceTrustConnect,
+			},
 			{
 				key:              keybindings.KeyDeviceBlock,
 				disabledText:     "Unblock",
@@ -487,14 +542,34 @@ func (m *menuBarView) initOrderedOptions() {
 				key:             keybindings.KeyDeviceSendFiles,
 				checkVisibility: true,
 			},
+			{
+				key:             keybindings.KeyDeviceBrowseFiles,
+				checkVisibility: true,
+			},
 			{
 				key:             keybindings.KeyDeviceNetwork,
 				checkVisibility: true,
 			},
+			{
+				key:             keybindings.KeyDeviceNetworkDisconnect,
+				checkVisibility: true,
+			},
 			{
 				key:             keybindings.KeyDeviceAudioProfiles,
 				checkVisibility: true,
 			},
+			{
+				key:             keybindings.KeyDeviceAudioHighQuality,
+				checkVisibility: true,
+			},
+			{
+				key:             keybindings.KeyDeviceAudioHeadset,
+				checkVisibility: true,
+			},
+			{
+				key:             keybindings.KeyDeviceAudioDefault,
+				checkVisibility: true,
+			},
 			{
 				key:             keybindings.KeyPlayerShow,
 				checkVisibility: true,
@@ -502,9 +577,36 @@ func (m *menuBarView) initOrderedOptions() {
 			{
 				key: keybindings.KeyDeviceInfo,
 			},
+			{
+				key: keybindings.KeyDeviceProfiles,
+			},
+			{
+				key:              keybindings.KeyDeviceFavorite,
+				disabledText:     "Unfavorite",
+				initBeforeInvoke: true,
+			},
+			{
+				key:              keybindings.KeyDeviceSetDefault,
+				disabledText:     "Unset Default",
+				initBeforeInvoke: true,
+			},
+			{
+				key: keybindings.KeyDeviceCopyAddress,
+			},
+			{
+				key:             keybindings.KeyDeviceRename,
+				checkVisibility: true,
+			},
 			{
 				key: keybindings.KeyDeviceRemove,
 			},
+			{
+				key:             keybindings.KeyDeviceIdentify,
+				checkVisibility: true,
+			},
+			{
+				key: keybindings.KeyDeviceBatch,
+			},
 		},
 	}
 }
@@ -0,0 +1,120 @@
+package views
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/ui/keybindings"
+	"github.com/darkhz/bluetuith/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// messageLogSize is the maximum number of messages stored in the message log.
+const messageLogSize = 500
+
+// messageLog holds a history of all the info and error messages
+// that were sent to the status bar.
+type messageLog struct {
+	entries []messageLogEntry
+
+	mu sync.Mutex
+}
+
+// messageLogEntry describes a single message in the message log.
+type messageLogEntry struct {
+	time    time.Time
+	text    string
+	isError bool
+}
+
+// newMessageLog returns a new message log.
+func newMessageLog() *messageLog {
+	return &messageLog{}
+}
+
+// add adds a message to the message log.
+// If the message log is full, the oldest message is removed.
+func (m *messageLog) add(text string, isError bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.entries) >= messageLogSize {
+		m.entries = slices.Delete(m.entries, 0, len(m.entries)-messageLogSize+1)
+	}
+
+	m.entries = append(m.entries, messageLogEntry{time.Now(), text, isError})
+}
+
+// clear removes all messages from the message log.
+func (m *messageLog) clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = nil
+}
+
+// list returns all messages in the message log, from the oldest to the newest.
+func (m *messageLog) list() []messageLogEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.entries)
+}
+
+// showMessageLog shows a popup with the history of the status bar messages.
+func (s *statusBarView) showMessageLog() {
+	entries := s.log.list()
+	if len(entries) == 0 {
+		s.InfoMessage("No messages have been logged", false)
+		return
+	}
+
+	logModal := s.modals.newModalWithTable("messagelog", "Message Log", 40, 100)
+
+	inputCapture := logModal.table.GetInputCapture()
+	logModal.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if s.kb.Key(event) == keybindings.KeyMessageLogClear {
+			s.log.clear()
+			logModal.remove(false)
+
+			s.InfoMessage("The message log was cleared", false)
+
+			return nil
+		}
+
+		return inputCapture(event)
+	})
+
+	for row, entry := range entries {
+		textColor := theme.ThemeStatusInfo
+		if entry.isError {
+			textColor = theme.ThemeStatusError
+		}
+
+		logModal.table.SetCell(
+			row, 0, tview.NewTableCell(entry.time.Format("15:04:05")).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(tcell.ColorGrey).
+				SetSelectedStyle(
+					tcell.Style{}.Bold(true).Reverse(true),
+				),
+		)
+
+		logModal.table.SetCell(
+			row, 1, tview.NewTableCell(entry.text).
+				SetExpansion(1).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(textColor)).
+				SetSelectedStyle(
+					tcell.Style{}.Bold(true).Reverse(true),
+				),
+		)
+	}
+
+	logModal.height = min(len(entries)+4, 40)
+	logModal.table.Select(len(entries)-1, 0)
+
+	logModal.show()
+}
@@ -2,6 +2,8 @@ package views
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/darkhz/bluetuith/ui/keybindings"
 	"github.com/darkhz/bluetuith/ui/theme"
@@ -102,6 +104,13 @@ func (m *modalViews) newModalWithTable(name, title string, height, width int) *t
 
 		return ignoreDefaultEvent(event)
 	})
+	table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if m.rv.scrollTable(table, action, event) {
+			return action, nil
+		}
+
+		return action, event
+	})
 
 	modal = m.newModal(name, title, table, height, width)
 
@@ -157,10 +166,28 @@ func (m *modalViews) newDisplayModal(name, title, message string) *displayModalV
 	}
 }
 
+// confirmModalLabels describes the labels of the buttons of a confirmation modal.
+// If the label of the 'always' button is empty, the button is not shown.
+type confirmModalLabels struct {
+	confirm, cancel, always string
+}
+
 // newConfirmModal returns a confirmation modal.
 func (m *modalViews) newConfirmModal(name, title, message string) *confirmModalView {
-	message += "\n\nPress y/n to Confirm/Cancel, click the required button or click the 'X' button to close this dialog."
-	buttonsText := `["confirm"][::b][Confirm[] ["cancel"][::b][Cancel[]`
+	return m.newLabeledConfirmModal(name, title, message, confirmModalLabels{confirm: "Confirm", cancel: "Cancel"})
+}
+
+// newLabeledConfirmModal returns a confirmation modal, with the provided button labels.
+func (m *modalViews) newLabeledConfirmModal(name, title, message string, labels confirmModalLabels) *confirmModalView {
+	keys, names := "y/n", labels.confirm+"/"+labels.cancel
+	buttonsText := `["confirm"][::b][` + labels.confirm + `[] ["cancel"][::b][` + labels.cancel + `[]`
+	if labels.always != "" {
+		keys += "/a"
+		names += "/" + labels.always
+		buttonsText += ` ["always"][::b][` + labels.always + `[]`
+	}
+
+	message += "\n\nPress " + keys + " to " + names + ", click the required button or click the 'X' button to close this dialog."
 
 	width, height := m.getModalDimensions(message, buttonsText)
 
@@ -191,6 +218,48 @@ func (m *modalViews) newConfirmModal(name, title, message string) *confirmModalV
 	}
 }
 
+// newInputModal returns a modal which asks the user to enter a value.
+// If the acceptance function is not nil, only the text accepted by it can be entered.
+func (m *modalViews) newInputModal(name, title, message, label string, accept func(text string, ch rune) bool) *inputModalView {
+	message += "\n\nPress Enter to confirm, or press Escape or click the 'X' button to cancel."
+
+	width, height := m.getModalDimensions(message, "")
+
+	textview := tview.NewTextView()
+	textview.SetText(message)
+	textview.SetDynamicColors(true)
+	textview.SetTextAlign(tview.AlignCenter)
+	textview.SetTextColor(theme.GetColor(theme.ThemeText))
+	textview.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	input := tview.NewInputField()
+	input.SetLabel("[::b]" + label + " ")
+	input.SetAcceptanceFunc(accept)
+	input.SetLabelColor(theme.GetColor(theme.ThemeText))
+	input.SetFieldTextColor(theme.GetColor(theme.ThemeText))
+	input.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	input.SetFieldBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	remaining := tview.NewTextView()
+	remaining.SetDynamicColors(true)
+	remaining.SetTextAlign(tview.AlignCenter)
+	remaining.SetTextColor(theme.GetColor(theme.ThemeStatusInfo))
+	remaining.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(textview, 0, 1, false).
+		AddItem(input, 1, 0, true).
+		AddItem(remaining, 1, 0, false)
+
+	return &inputModalView{
+		textview:  textview,
+		input:     input,
+		remaining: remaining,
+		modalView: m.newModal(name, title, flex, height+2, width),
+	}
+}
+
 // isModalDisplayed checks if the specified modal is displayed.
 func (m *modalViews) isModalDisplayed(modalName string) bool {
 	for _, modal := range m.modals {
@@ -469,6 +538,89 @@ func (d *displayModalView) display(ctx context.Context) {
 	}
 }
 
+// inputModalView holds a modal which displays a message and asks the user to enter a value.
+type inputModalView struct {
+	textview, remaining *tview.TextView
+	input               *tview.InputField
+
+	*modalView
+}
+
+// getInput displays the modal, and waits for the user to enter a value or the provided context
+// to be completed to close the modal. If the context has a deadline, the time remaining until
+// the deadline is displayed. If the input is cancelled, false is returned.
+func (i *inputModalView) getInput(ctx context.Context) (string, bool) {
+	type inputReply struct {
+		text string
+		ok   bool
+	}
+
+	reply := make(chan inputReply, 1)
+
+	send := func(r inputReply) {
+		i.remove(false)
+
+		select {
+		case reply <- r:
+		default:
+		}
+	}
+
+	i.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch i.mgr.rv.kb.Key(event) {
+		case keybindings.KeySelect:
+			send(inputReply{i.input.GetText(), true})
+
+		case keybindings.KeyClose:
+			send(inputReply{})
+		}
+
+		return event
+	})
+	i.closeButton.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		send(inputReply{})
+		return event
+	})
+
+	deadline, hasDeadline := ctx.Deadline()
+	showRemaining := func() {
+		if hasDeadline {
+			i.remaining.SetText(fmt.Sprintf("Time remaining: %ds", max(0, int(time.Until(deadline).Round(time.Second).Seconds()))))
+		}
+	}
+
+	go i.mgr.rv.app.QueueDraw(func() {
+		if m, ok := i.mgr.getModal(i.name); ok {
+			m.remove(false)
+		}
+
+		showRemaining()
+		i.show()
+	})
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			i.mgr.rv.app.QueueDraw(func() {
+				if i.isOpen {
+					i.remove(false)
+				}
+			})
+
+			return "", false
+
+		case r := <-reply:
+			return r.text, r.ok
+
+		case <-ticker.C:
+			i.mgr.rv.app.QueueDraw(showRemaining)
+		}
+	}
+}
+
 // confirmModalView holds a modal which displays a message and asks for confirmation from the user.
 type confirmModalView textModalView
 
@@ -494,12 +646,20 @@ func (c *confirmModalView) getReply(ctx context.Context) string {
 
 		case "cancel":
 			send("n")
+
+		case "always":
+			send("a")
 		}
 	})
 	c.buttons.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Rune() {
 		case 'y', 'n':
 			send(string(event.Rune()))
+
+		case 'a':
+			if containsRegionID(c.buttons, "always") {
+				send("a")
+			}
 		}
 
 		if c.mgr.rv.kb.Key(event) == keybindings.KeyClose {
@@ -0,0 +1,369 @@
+package views
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const (
+	mprisName        = "org.mpris.MediaPlayer2.bluetuith"
+	mprisPath        = "/org/mpris/MediaPlayer2"
+	mprisIface       = "org.mpris.MediaPlayer2"
+	mprisPlayerIface = mprisIface + ".Player"
+	mprisTrackPath   = "/org/bluetuith/track"
+)
+
+// mprisServer holds an MPRIS2 server, which mirrors the media player of the
+// most recently active device on the session bus, so that desktop media controls
+// can display and control the device's media.
+type mprisServer struct {
+	v *Views
+
+	conn  *dbus.Conn
+	props *prop.Properties
+
+	player  bluetooth.MediaPlayer
+	address bluetooth.DeviceAddress
+	cached  bluetooth.MediaEventData
+
+	mu sync.Mutex
+}
+
+// mprisRoot holds the methods of the "org.mpris.MediaPlayer2" interface.
+type mprisRoot struct{}
+
+// mprisPlayer holds the methods of the "org.mpris.MediaPlayer2.Player" interface.
+type mprisPlayer struct {
+	m *mprisServer
+}
+
+// newMprisServer returns a new MPRIS2 server.
+func newMprisServer(v *Views) *mprisServer {
+	return &mprisServer{v: v}
+}
+
+// start registers the MPRIS2 server on the session bus, and starts mirroring media events.
+func (m *mprisServer) start() error {
+	if !desktopBusSupported {
+		return errors.New("MPRIS2 is not supported on this platform")
+	}
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(mprisName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return errors.New("the MPRIS2 name " + mprisName + " is already in use")
+	}
+
+	props, err := prop.Export(conn, mprisPath, prop.Map{
+		mprisIface: {
+			"CanQuit":             {Value: false, Emit: prop.EmitFalse},
+			"CanRaise":            {Value: false, Emit: prop.EmitFalse},
+			"HasTrackList":        {Value: false, Emit: prop.EmitFalse},
+			"Identity":            {Value: "bluetuith", Emit: prop.EmitFalse},
+			"SupportedUriSchemes": {Value: []string{}, Emit: prop.EmitFalse},
+			"SupportedMimeTypes":  {Value: []string{}, Emit: prop.EmitFalse},
+		},
+		mprisPlayerIface: {
+			"PlaybackStatus": {Value: "Stopped", Emit: prop.EmitTrue},
+			"LoopStatus":     {Value: "None", Emit: prop.EmitTrue},
+			"Rate":           {Value: 1.0, Emit: prop.EmitTrue},
+			"Shuffle":        {Value: false, Emit: prop.EmitTrue},
+			"Metadata":       {Value: mprisMetadata(bluetooth.TrackData{}), Emit: prop.EmitTrue},
+			"Volume":         {Value: 1.0, Emit: prop.EmitTrue},
+			"Position":       {Value: int64(0), Emit: prop.EmitFalse},
+			"MinimumRate":    {Value: 1.0, Emit: prop.EmitTrue},
+			"MaximumRate":    {Value: 1.0, Emit: prop.EmitTrue},
+			"CanGoNext":      {Value: false, Emit: prop.EmitTrue},
+			"CanGoPrevious":  {Value: false, Emit: prop.EmitTrue},
+			"CanPlay":        {Value: false, Emit: prop.EmitTrue},
+			"CanPause":       {Value: false, Emit: prop.EmitTrue},
+			"CanSeek":        {Value: false, Emit: prop.EmitTrue},
+			"CanControl":     {Value: true, Emit: prop.EmitFalse},
+		},
+	})
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	// The "Seek" method is exported from "SeekBy", since a method named "Seek"
+	// is expected to implement io.Seeker.
+	playerMethods := map[string]string{"SeekBy": "Seek"}
+
+	root, player := mprisRoot{}, mprisPlayer{m: m}
+	if err := conn.Export(root, mprisPath, mprisIface); err != nil {
+		conn.Close()
+		return err
+	}
+	if err := conn.ExportWithMap(player, playerMethods, mprisPath, mprisPlayerIface); err != nil {
+		conn.Close()
+		return err
+	}
+
+	playerIntrospection := introspect.Methods(player)
+	for i, method := range playerIntrospection {
+		if name, ok := playerMethods[method.Name]; ok {
+			playerIntrospection[i].Name = name
+		}
+	}
+
+	node := &introspect.Node{
+		Name: mprisPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{
+				Name:       mprisIface,
+				Methods:    introspect.Methods(root),
+				Properties: props.Introspection(mprisIface),
+			},
+			{
+				Name:       mprisPlayerIface,
+				Methods:    playerIntrospection,
+				Properties: props.Introspection(mprisPlayerIface),
+			},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), mprisPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		conn.Close()
+		return err
+	}
+
+	m.mu.Lock()
+	m.conn, m.props = conn, props
+	m.mu.Unlock()
+
+	go m.event(conn)
+
+	return nil
+}
+
+// stop releases the MPRIS2 name and closes the session bus connection.
+func (m *mprisServer) stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conn == nil {
+		return
+	}
+
+	m.conn.ReleaseName(mprisName)
+	m.conn.Close()
+
+	m.conn, m.props, m.player = nil, nil, nil
+}
+
+// setPlayer sets the device whose media player is mirrored by the MPRIS2 server.
+func (m *mprisServer) setPlayer(address bluetooth.DeviceAddress, media bluetooth.MediaData) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conn == nil {
+		return
+	}
+
+	m.address = address
+	m.player = m.v.app.Session().MediaPlayer(address)
+	m.cached = bluetooth.MediaEventData(media)
+
+	m.update(true, true)
+}
+
+// event handles media events to update the MPRIS2 properties.
+// It returns once the server has been stopped, or restarted with a new connection (conn).
+func (m *mprisServer) event(conn *dbus.Conn) {
+	mediaSub, ok := bluetooth.MediaEvents().Subscribe()
+	if !ok {
+		return
+	}
+
+	for {
+		select {
+		case <-mediaSub.Done:
+			return
+
+		case ev, ok := <-mediaSub.UpdatedEvents:
+			if !ok {
+				return
+			}
+
+			if ev.Address.IsNil() {
+				continue
+			}
+
+			m.mu.Lock()
+			if m.conn != conn {
+				m.mu.Unlock()
+				return
+			}
+
+			if m.player == nil || ev.DeviceAddress != m.address {
+				m.mu.Unlock()
+
+				media, err := m.v.app.Session().MediaPlayer(ev.DeviceAddress).Properties()
+				if err != nil {
+					continue
+				}
+
+				m.setPlayer(ev.DeviceAddress, media)
+
+				continue
+			}
+
+			var track, status bool
+
+			if ev.TrackData != (bluetooth.TrackData{}) && ev.TrackData != m.cached.TrackData {
+				m.cached.TrackData = ev.TrackData
+				track = true
+			}
+			if ev.Status != "" && ev.Status != m.cached.Status {
+				m.cached.Status = ev.Status
+				status = true
+			}
+			if ev.Position > 0 {
+				m.cached.Position = ev.Position
+			}
+
+			m.update(track, status)
+			m.mu.Unlock()
+		}
+	}
+}
+
+// update updates the MPRIS2 properties from the cached media data.
+// This must be called with the lock held.
+func (m *mprisServer) update(track, status bool) {
+	m.props.SetMust(mprisPlayerIface, "Position", int64(m.cached.Position)*1000)
+
+	if track {
+		m.props.SetMust(mprisPlayerIface, "Metadata", mprisMetadata(m.cached.TrackData))
+	}
+
+	if status {
+		m.props.SetMust(mprisPlayerIface, "PlaybackStatus", mprisPlaybackStatus(m.cached.Status))
+	}
+
+	for _, capability := range []string{"CanGoNext", "CanGoPrevious", "CanPlay", "CanPause"} {
+		if value, ok := m.props.GetMust(mprisPlayerIface, capability).(bool); !ok || !value {
+			m.props.SetMust(mprisPlayerIface, capability, true)
+		}
+	}
+}
+
+// control invokes the provided function on the mirrored media player.
+func (m *mprisServer) control(f func(bluetooth.MediaPlayer) error) *dbus.Error {
+	m.mu.Lock()
+	player := m.player
+	m.mu.Unlock()
+
+	if player == nil {
+		return dbus.MakeFailedError(errors.New("no media player is available"))
+	}
+
+	if err := f(player); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
+
+// Raise brings the media player's user interface to the front. This is not supported.
+func (mprisRoot) Raise() *dbus.Error {
+	return nil
+}
+
+// Quit causes the media player to stop running. This is not supported.
+func (mprisRoot) Quit() *dbus.Error {
+	return nil
+}
+
+// Next skips to the next track.
+func (p mprisPlayer) Next() *dbus.Error {
+	return p.m.control(bluetooth.MediaPlayer.Next)
+}
+
+// Previous skips to the previous track.
+func (p mprisPlayer) Previous() *dbus.Error {
+	return p.m.control(bluetooth.MediaPlayer.Previous)
+}
+
+// Pause pauses playback.
+func (p mprisPlayer) Pause() *dbus.Error {
+	return p.m.control(bluetooth.MediaPlayer.Pause)
+}
+
+// PlayPause toggles playback.
+func (p mprisPlayer) PlayPause() *dbus.Error {
+	return p.m.control(bluetooth.MediaPlayer.TogglePlayPause)
+}
+
+// Stop stops playback.
+func (p mprisPlayer) Stop() *dbus.Error {
+	return p.m.control(bluetooth.MediaPlayer.Stop)
+}
+
+// Play starts or resumes playback.
+func (p mprisPlayer) Play() *dbus.Error {
+	return p.m.control(bluetooth.MediaPlayer.Play)
+}
+
+// SeekBy seeks forward or backward in the current track. This is not supported.
+func (p mprisPlayer) SeekBy(_ int64) *dbus.Error {
+	return nil
+}
+
+// SetPosition sets the position in the current track. This is not supported.
+func (p mprisPlayer) SetPosition(_ dbus.ObjectPath, _ int64) *dbus.Error {
+	return nil
+}
+
+// OpenUri opens the provided URI. This is not supported.
+func (p mprisPlayer) OpenUri(_ string) *dbus.Error {
+	return nil
+}
+
+// mprisMetadata returns the MPRIS2 metadata for the provided track.
+func mprisMetadata(track bluetooth.TrackData) map[string]dbus.Variant {
+	metadata := map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath(mprisTrackPath)),
+		"mpris:length":  dbus.MakeVariant(int64(track.Duration) * 1000),
+		"xesam:title":   dbus.MakeVariant(track.Title),
+		"xesam:album":   dbus.MakeVariant(track.Album),
+	}
+
+	if track.Artist != "" {
+		metadata["xesam:artist"] = dbus.MakeVariant([]string{track.Artist})
+	}
+	if track.TrackNumber > 0 {
+		metadata["xesam:trackNumber"] = dbus.MakeVariant(int32(track.TrackNumber))
+	}
+
+	return metadata
+}
+
+// mprisPlaybackStatus returns the MPRIS2 playback status for the provided media status.
+func mprisPlaybackStatus(status bluetooth.MediaStatus) string {
+	switch status {
+	case bluetooth.MediaPlaying, bluetooth.MediaForwardSeek, bluetooth.MediaReverseSeek:
+		return "Playing"
+
+	case bluetooth.MediaPaused:
+		return "Paused"
+	}
+
+	return "Stopped"
+}
@@ -0,0 +1,158 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	nmDest                  = "org.freedesktop.NetworkManager"
+	nmPath                  = "/org/freedesktop/NetworkManager"
+	nmSettingsPath          = "/org/freedesktop/NetworkManager/Settings"
+	nmSettingsIface         = "org.freedesktop.NetworkManager.Settings"
+	nmConnectionIface       = "org.freedesktop.NetworkManager.Settings.Connection"
+	nmActiveConnectionIface = "org.freedesktop.NetworkManager.Connection.Active"
+)
+
+// The states of an active NetworkManager connection.
+const (
+	nmActiveStateUnknown uint32 = iota
+	nmActiveStateActivating
+	nmActiveStateActivated
+)
+
+// errDunEstablish is returned if NetworkManager cannot activate the DUN connection.
+var errDunEstablish = errors.New("the DUN connection could not be established")
+
+// connectDun activates the DUN connection profile of the device in NetworkManager with the provided APN and
+// number to dial, and returns the object path of the active connection. The profile is created if it does not
+// exist yet. The network interface of the session is not used, since it does not accept the APN and the number.
+// If the context is cancelled while the connection is being activated, the connection is deactivated.
+// This is only supported by BlueZ.
+func connectDun(ctx context.Context, name string, address bluetooth.MacAddress, dun bluetooth.NetworkDunSettings) (dbus.ObjectPath, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return "", err
+	}
+
+	profile, err := setDunProfile(conn, name, address, dun)
+	if err != nil {
+		return "", err
+	}
+
+	manager := conn.Object(nmDest, nmPath)
+
+	var device dbus.ObjectPath
+	if err := manager.Call(nmDest+".GetDeviceByIpIface", 0, address.String()).Store(&device); err != nil {
+		return "", err
+	}
+
+	var active dbus.ObjectPath
+	if err := manager.Call(nmDest+".ActivateConnection", 0, profile, device, dbus.ObjectPath("/")).Store(&active); err != nil {
+		return "", err
+	}
+
+	for {
+		state, err := conn.Object(nmDest, active).GetProperty(nmActiveConnectionIface + ".State")
+		if err != nil {
+			return "", errDunEstablish
+		}
+
+		switch state.Value() {
+		case nmActiveStateActivated:
+			return active, nil
+
+		case nmActiveStateUnknown, nmActiveStateActivating:
+
+		default:
+			return "", errDunEstablish
+		}
+
+		select {
+		case <-ctx.Done():
+			disconnectDun(active)
+			return "", ctx.Err()
+
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// disconnectDun deactivates the active DUN connection at the provided object path.
+func disconnectDun(active dbus.ObjectPath) error {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return err
+	}
+
+	return conn.Object(nmDest, nmPath).Call(nmDest+".DeactivateConnection", 0, active).Err
+}
+
+// setDunProfile sets the APN and the number to dial of the DUN connection profile of the device,
+// and returns the object path of the profile. If the device has no such profile, it is created.
+func setDunProfile(conn *dbus.Conn, name string, address bluetooth.MacAddress, dun bluetooth.NetworkDunSettings) (dbus.ObjectPath, error) {
+	settingsManager := conn.Object(nmDest, nmSettingsPath)
+
+	var profiles []dbus.ObjectPath
+	if err := settingsManager.Call(nmSettingsIface+".ListConnections", 0).Store(&profiles); err != nil {
+		return "", err
+	}
+
+	for _, profile := range profiles {
+		var settings map[string]map[string]dbus.Variant
+		if err := conn.Object(nmDest, profile).Call(nmConnectionIface+".GetSettings", 0).Store(&settings); err != nil {
+			continue
+		}
+
+		bdaddr, _ := settings["bluetooth"]["bdaddr"].Value().([]byte)
+		connType, _ := settings["bluetooth"]["type"].Value().(string)
+		if connType != bluetooth.NetworkDun.String() || !bytes.Equal(bdaddr, address[:]) {
+			continue
+		}
+
+		gsm := settings["gsm"]
+		if gsm == nil {
+			gsm = make(map[string]dbus.Variant)
+		}
+		gsm["apn"] = dbus.MakeVariant(dun.APN)
+		gsm["number"] = dbus.MakeVariant(dun.Number)
+		settings["gsm"] = gsm
+
+		// The IPv6 settings are returned in a deprecated format, which is rejected when
+		// the settings are updated, so they are reset to their defaults instead.
+		delete(settings, "ipv6")
+
+		return profile, conn.Object(nmDest, profile).Call(nmConnectionIface+".Update", 0, settings).Err
+	}
+
+	settings := map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":          dbus.MakeVariant(fmt.Sprintf("%s Access Point (DUN)", name)),
+			"type":        dbus.MakeVariant("bluetooth"),
+			"uuid":        dbus.MakeVariant(uuid.NewString()),
+			"autoconnect": dbus.MakeVariant(false),
+		},
+		"bluetooth": {
+			"bdaddr": dbus.MakeVariant(address[:]),
+			"type":   dbus.MakeVariant(bluetooth.NetworkDun.String()),
+		},
+		"gsm": {
+			"apn":    dbus.MakeVariant(dun.APN),
+			"number": dbus.MakeVariant(dun.Number),
+		},
+	}
+
+	var profile dbus.ObjectPath
+	if err := settingsManager.Call(nmSettingsIface+".AddConnection", 0, settings).Store(&profile); err != nil {
+		return "", err
+	}
+
+	return profile, nil
+}
@@ -1,27 +1,49 @@
 package views
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
+	"sync"
 
+	"github.com/godbus/dbus/v5"
 	"go.uber.org/atomic"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/bluetuith/ui/btdevice"
 	"github.com/darkhz/bluetuith/ui/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
 
+const bluezNetworkIface = "org.bluez.Network1"
+
 // networkView holds the network selector view.
 type networkView struct {
 	isSupported atomic.Bool
 
+	active map[bluetooth.MacAddress]bluetooth.NetworkType
+	dun    map[bluetooth.MacAddress]dbus.ObjectPath
+	mu     sync.Mutex
+
 	*Views
 }
 
+// networkOption describes a network connection type which can be established with a device.
+type networkOption struct {
+	connType bluetooth.NetworkType
+	desc     string
+}
+
 // Initialize initializes the network selector view.
 func (n *networkView) Initialize() error {
+	n.mu.Lock()
+	n.active = make(map[bluetooth.MacAddress]bluetooth.NetworkType)
+	n.dun = make(map[bluetooth.MacAddress]dbus.ObjectPath)
+	n.mu.Unlock()
+
 	n.isSupported.Store(true)
 
 	return nil
@@ -33,50 +55,35 @@ func (n *networkView) SetRootView(v *Views) {
 }
 
 // networkSelect shows a popup to select the network type.
+// The connection types are determined from the services advertised by the device,
+// and the currently active connection type, if any, is marked.
 func (n *networkView) networkSelect() {
 	if !n.isSupported.Load() {
 		n.status.ErrorMessage(errors.New("this operation is not supported"))
 		return
 	}
 
-	type nwTypeDesc struct {
-		connType bluetooth.NetworkType
-		desc     string
-	}
-
-	var connTypes []nwTypeDesc
-
 	device := n.device.getSelection(false)
 	if device.IsNil() {
 		return
 	}
 
-	if device.HaveService(bluetooth.PanuServiceClass) {
-		connTypes = append(connTypes, nwTypeDesc{
-			bluetooth.NetworkPanu,
-			"Personal Area Network",
-		})
-	}
-	if device.HaveService(bluetooth.DialupNetServiceClass) {
-		connTypes = append(connTypes, nwTypeDesc{
-			bluetooth.NetworkDun,
-			"Dialup Network",
-		})
-	}
-
-	deviceName := getDeviceDisplayName(device.DeviceEventData)
+	deviceName := btdevice.DisplayName(device.DeviceEventData)
 
+	connTypes := networkOptions(device)
 	if connTypes == nil {
 		n.status.InfoMessage("No network options exist for "+deviceName, false)
 		return
 	}
 
+	active, isActive := n.activeType(device)
+
 	n.menu.drawContextMenu(
 		menuDeviceName.String(),
 		func(networkMenu *tview.Table) {
 			row, _ := networkMenu.GetSelection()
 
-			cell := networkMenu.GetCell(row, 0)
+			cell := networkMenu.GetCell(row, 1)
 			if cell == nil {
 				return
 			}
@@ -89,7 +96,7 @@ func (n *networkView) networkSelect() {
 			go n.networkConnect(device, connType)
 		}, nil,
 		func(networkMenu *tview.Table) (int, int) {
-			var width int
+			var width, index int
 
 			for row, nw := range connTypes {
 				ctype := nw.connType
@@ -99,8 +106,22 @@ func (n *networkView) networkSelect() {
 					width = len(description)
 				}
 
+				var activeIndicator string
+				if isActive && ctype == active {
+					activeIndicator = string('\u2022')
+					index = row
+				}
+
+				networkMenu.SetCell(
+					row, 0, tview.NewTableCell(activeIndicator).
+						SetSelectable(false).
+						SetTextColor(theme.GetColor(theme.ThemeText)).
+						SetSelectedStyle(
+							tcell.Style{}.Reverse(true),
+						),
+				)
 				networkMenu.SetCell(
-					row, 0, tview.NewTableCell(description).
+					row, 1, tview.NewTableCell(description).
 						SetExpansion(1).
 						SetReference(ctype).
 						SetAlign(tview.AlignLeft).
@@ -110,7 +131,7 @@ func (n *networkView) networkSelect() {
 						),
 				)
 				networkMenu.SetCell(
-					row, 1, tview.NewTableCell("("+strings.ToUpper(ctype.String())+")").
+					row, 2, tview.NewTableCell("("+strings.ToUpper(ctype.String())+")").
 						SetAlign(tview.AlignRight).
 						SetTextColor(theme.GetColor(theme.ThemeText)).
 						SetSelectedStyle(
@@ -119,37 +140,295 @@ func (n *networkView) networkSelect() {
 				)
 			}
 
-			return width, 0
+			return width, index
 		},
 	)
 }
 
 // networkConnect connects to the network with the selected network type.
+// For DUN connections with BlueZ, the APN and the number to dial are requested first.
 func (n *networkView) networkConnect(device bluetooth.DeviceData, connType bluetooth.NetworkType) {
 	info := fmt.Sprintf(
 		"%s (%s)",
-		getDeviceDisplayName(device.DeviceEventData), strings.ToUpper(connType.String()),
+		btdevice.DisplayName(device.DeviceEventData), strings.ToUpper(connType.String()),
 	)
 
-	deviceName := getDeviceDisplayName(device.DeviceEventData)
+	deviceName := btdevice.DisplayName(device.DeviceEventData)
 
-	n.op.startOperation(
+	ctx, cancel := context.WithCancel(context.Background())
+
+	connect := func() error {
+		return n.app.Session().Network(device.DeviceAddress).Connect(deviceName, connType)
+	}
+	if connType == bluetooth.NetworkDun && bluezSupported {
+		settings, ok := n.dunSettings(device)
+		if !ok {
+			cancel()
+			return
+		}
+
+		connect = func() error {
+			active, err := connectDun(ctx, deviceName, device.Address, settings)
+			if err == nil {
+				n.mu.Lock()
+				n.dun[device.Address] = active
+				n.mu.Unlock()
+			}
+
+			return err
+		}
+	}
+
+	if !n.op.startOperation(
 		func() {
+			defer cancel()
+
 			n.status.InfoMessage("Connecting to "+info, true)
-			err := n.app.Session().Network(device.DeviceAddress).Connect(deviceName, connType)
-			if err != nil {
-				n.status.ErrorMessage(err)
+			if err := connect(); err != nil {
+				if !errors.Is(err, context.Canceled) {
+					n.status.ErrorMessage(err)
+				}
+
 				return
 			}
+
+			n.setActive(device.DeviceAddress, connType)
 			n.status.InfoMessage("Connected to "+info, false)
 		},
 		func() {
-			err := n.app.Session().Network(device.DeviceAddress).Disconnect()
-			if err != nil {
+			cancel()
+
+			if err := n.disconnect(device.DeviceAddress, connType); err != nil {
 				n.status.ErrorMessage(err)
 				return
 			}
+
+			n.clearActive(device.Address)
+			n.device.redrawDevice(device.DeviceAddress)
+
 			n.status.InfoMessage("Cancelled connection to "+info, false)
 		},
-	)
+	) {
+		cancel()
+	}
+}
+
+// dunSettings requests the APN and the number to dial for a DUN connection with the device.
+// The last used APN of the device, or the APN in the configuration, is suggested, and the
+// entered APN is stored for the device.
+func (n *networkView) dunSettings(device bluetooth.DeviceData) (bluetooth.NetworkDunSettings, bool) {
+	address := device.Address.String()
+
+	apn, ok := n.cfg.Values.NetworkApns[address]
+	if !ok {
+		apn = n.cfg.Values.GsmApn
+	}
+
+	apn, ok = n.status.SetInputText("APN:", apn)
+	if !ok {
+		return bluetooth.NetworkDunSettings{}, false
+	}
+
+	apn = strings.TrimSpace(apn)
+	if apn == "" {
+		n.status.ErrorMessage(errors.New("the APN cannot be empty for DUN connections"))
+		return bluetooth.NetworkDunSettings{}, false
+	}
+
+	number := n.cfg.Values.GsmNumber
+	if number == "" {
+		number = "*99#"
+	}
+
+	number, ok = n.status.SetInputText("Number to dial:", number)
+	if !ok {
+		return bluetooth.NetworkDunSettings{}, false
+	}
+
+	number = strings.TrimSpace(number)
+	if number == "" {
+		n.status.ErrorMessage(errors.New("the number to dial cannot be empty for DUN connections"))
+		return bluetooth.NetworkDunSettings{}, false
+	}
+
+	if n.cfg.Values.NetworkApns[address] != apn {
+		apns := maps.Clone(n.cfg.Values.NetworkApns)
+		if apns == nil {
+			apns = make(map[string]string)
+		}
+		apns[address] = apn
+
+		if err := n.cfg.Save("network-apns", apns); err != nil {
+			n.status.ErrorMessage(err)
+		} else {
+			n.cfg.Values.NetworkApns = apns
+		}
+	}
+
+	return bluetooth.NetworkDunSettings{APN: apn, Number: number}, true
+}
+
+// disconnect deactivates the network connection of the device. DUN connections with BlueZ are
+// activated using NetworkManager directly, so they are deactivated in the same way.
+func (n *networkView) disconnect(address bluetooth.DeviceAddress, connType bluetooth.NetworkType) error {
+	if connType != bluetooth.NetworkDun || !bluezSupported {
+		return n.app.Session().Network(address).Disconnect()
+	}
+
+	n.mu.Lock()
+	active, ok := n.dun[address.Address]
+	delete(n.dun, address.Address)
+	n.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return disconnectDun(active)
+}
+
+// networkDisconnect disconnects the active network connection of the device.
+func (n *networkView) networkDisconnect(device bluetooth.DeviceData) {
+	if !n.isSupported.Load() {
+		n.status.ErrorMessage(errors.New("this operation is not supported"))
+		return
+	}
+
+	deviceName := btdevice.DisplayName(device.DeviceEventData)
+
+	connType, ok := n.activeType(device)
+	if !ok {
+		n.status.InfoMessage("No network connection is active for "+deviceName, false)
+		return
+	}
+
+	info := fmt.Sprintf("%s (%s)", deviceName, strings.ToUpper(connType.String()))
+
+	n.status.InfoMessage("Disconnecting from "+info, true)
+	if err := n.disconnect(device.DeviceAddress, connType); err != nil {
+		n.status.ErrorMessage(err)
+		return
+	}
+
+	n.clearActive(device.Address)
+	n.device.redrawDevice(device.DeviceAddress)
+
+	n.status.InfoMessage("Disconnected from "+info, false)
+}
+
+// activeType returns the currently active network connection type of the device.
+// A PAN connection is detected using the "org.bluez.Network1" interface, which is only
+// supported by BlueZ. Other connection types are only known if they were established
+// from this application, while the device is connected.
+func (n *networkView) activeType(device bluetooth.DeviceData) (bluetooth.NetworkType, bool) {
+	if adapter := n.adapter.getAdapter(); adapter != nil {
+		if connected, err := getNetworkConnected(adapter.UniqueName, device.Address); err == nil && connected {
+			n.setActive(device.DeviceAddress, bluetooth.NetworkPanu)
+			return bluetooth.NetworkPanu, true
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	connType, ok := n.active[device.Address]
+	if !ok || !device.Connected.Value() {
+		delete(n.active, device.Address)
+		return "", false
+	}
+
+	return connType, true
+}
+
+// activeTag returns a short name for the active network connection type of the device, if any.
+func (n *networkView) activeTag(address bluetooth.MacAddress) string {
+	if !n.isSupported.Load() {
+		return ""
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	switch n.active[address] {
+	case bluetooth.NetworkPanu:
+		return "PAN"
+
+	case bluetooth.NetworkDun:
+		return "DUN"
+	}
+
+	return ""
+}
+
+// setActive stores the active network connection type of the device, and redraws the device row.
+func (n *networkView) setActive(address bluetooth.DeviceAddress, connType bluetooth.NetworkType) {
+	n.mu.Lock()
+	previous := n.active[address.Address]
+	n.active[address.Address] = connType
+	n.mu.Unlock()
+
+	if previous != connType {
+		n.device.redrawDevice(address)
+	}
+}
+
+// clearActive removes the stored network connection type of the device.
+func (n *networkView) clearActive(address bluetooth.MacAddress) {
+	if !n.isSupported.Load() {
+		return
+	}
+
+	n.mu.Lock()
+	delete(n.active, address)
+	n.mu.Unlock()
+}
+
+// networkOptions returns the network connection types supported by the device.
+// A PAN connection is established with the network access point (NAP) or group
+// ad-hoc network (GN) of the device, so it is listed for both services.
+func networkOptions(device bluetooth.DeviceData) []networkOption {
+	var options []networkOption
+
+	switch {
+	case device.HaveService(bluetooth.NapServiceClass):
+		options = append(options, networkOption{
+			bluetooth.NetworkPanu,
+			"Personal Area Network (Access Point)",
+		})
+
+	case device.HaveService(bluetooth.GnServiceClass):
+		options = append(options, networkOption{
+			bluetooth.NetworkPanu,
+			"Personal Area Network (Group Network)",
+		})
+	}
+
+	if device.HaveService(bluetooth.DialupNetServiceClass) {
+		options = append(options, networkOption{
+			bluetooth.NetworkDun,
+			"Dialup Network",
+		})
+	}
+
+	return options
+}
+
+// getNetworkConnected returns whether a PAN connection is established with the device,
+// using the "org.bluez.Network1" interface. This is only supported by BlueZ.
+func getNetworkConnected(adapterName string, address bluetooth.MacAddress) (bool, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return false, err
+	}
+
+	devicePath := bluezDevicePath(adapterName, address)
+
+	var connected bool
+	if err := conn.Object(bluezDest, devicePath).
+		Call("org.freedesktop.DBus.Properties.Get", 0, bluezNetworkIface, "Connected").
+		Store(&connected); err != nil {
+		return false, err
+	}
+
+	return connected, nil
 }
@@ -0,0 +1,58 @@
+package views
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notificationsDest  = "org.freedesktop.Notifications"
+	notificationsPath  = "/org/freedesktop/Notifications"
+	notificationsIface = notificationsDest + ".Notify"
+)
+
+// notifier holds a desktop notification manager, which sends notifications
+// via the "org.freedesktop.Notifications" interface on the session bus.
+// If the session bus or the notification service is not available, or the
+// platform does not provide them, all notifications are silently discarded.
+type notifier struct {
+	enabled bool
+
+	obj  dbus.BusObject
+	once sync.Once
+}
+
+// newNotifier returns a new desktop notification manager.
+func newNotifier(enabled bool) *notifier {
+	return &notifier{enabled: enabled && desktopBusSupported}
+}
+
+// notify sends a desktop notification in the background.
+func (n *notifier) notify(summary, body string) {
+	if n == nil || !n.enabled {
+		return
+	}
+
+	go func() {
+		n.once.Do(func() {
+			conn, err := dbus.ConnectSessionBus()
+			if err != nil {
+				return
+			}
+
+			n.obj = conn.Object(notificationsDest, dbus.ObjectPath(notificationsPath))
+		})
+
+		if n.obj == nil {
+			return
+		}
+
+		n.obj.Call(
+			notificationsIface, 0,
+			"bluetuith", uint32(0), "bluetooth",
+			summary, body,
+			[]string{}, map[string]dbus.Variant{}, int32(-1),
+		)
+	}()
+}
@@ -0,0 +1,176 @@
+package views
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/godbus/dbus/v5"
+)
+
+const obexFileTransferIface = "org.bluez.obex.FileTransfer1"
+
+// obexFileTransfer holds a session of the OBEX File Transfer profile with a device, which is
+// used to browse and transfer files on the device's filesystem. Since the session implementation
+// only supports the Object Push profile, the "org.bluez.obex.FileTransfer1" interface of the
+// OBEX daemon is called directly. The transfers are still published as Object Push events,
+// so that their progress can be displayed in the progress view.
+type obexFileTransfer struct {
+	conn    *dbus.Conn
+	address bluetooth.DeviceAddress
+	session dbus.ObjectPath
+}
+
+// remoteFileInfo describes a file or folder on the device's filesystem.
+type remoteFileInfo struct {
+	name    string
+	size    int64
+	isDir   bool
+	modTime time.Time
+}
+
+// newObexFileTransfer creates a new file transfer session with the device.
+// The context (ctx) can be provided to cancel the session creation, since it can take some time to complete.
+func newObexFileTransfer(ctx context.Context, address bluetooth.DeviceAddress) (*obexFileTransfer, error) {
+	conn, err := bluezSessionBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var session dbus.ObjectPath
+	if err := conn.Object(obexDest, "/org/bluez/obex").
+		CallWithContext(ctx, obexClientIface+".CreateSession", 0, address.Address.String(), map[string]dbus.Variant{
+			"Target": dbus.MakeVariant("ftp"),
+			"Source": dbus.MakeVariant(address.AssociatedAdapter.String()),
+		}).
+		Store(&session); err != nil {
+		return nil, err
+	}
+
+	return &obexFileTransfer{conn: conn, address: address, session: session}, nil
+}
+
+// remove removes the file transfer session.
+func (o *obexFileTransfer) remove() error {
+	return o.conn.Object(obexDest, "/org/bluez/obex").
+		Call(obexClientIface+".RemoveSession", 0, o.session).
+		Store()
+}
+
+// listFolder returns the contents of the current folder.
+func (o *obexFileTransfer) listFolder() ([]fs.FileInfo, error) {
+	var entries []map[string]dbus.Variant
+	if err := o.call("ListFolder").Store(&entries); err != nil {
+		return nil, err
+	}
+
+	files := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		var file remoteFileInfo
+
+		file.name, _ = entry["Name"].Value().(string)
+		if file.name == "" {
+			continue
+		}
+
+		kind, _ := entry["Type"].Value().(string)
+		file.isDir = kind == "folder"
+
+		if size, ok := entry["Size"].Value().(uint64); ok {
+			file.size = int64(size)
+		}
+
+		if modified, ok := entry["Modified"].Value().(string); ok {
+			file.modTime = parseObexTime(modified)
+		}
+
+		files = append(files, &file)
+	}
+
+	return files, nil
+}
+
+// changeFolder changes the current folder. The parent folder is selected with "..".
+func (o *obexFileTransfer) changeFolder(folder string) error {
+	return o.call("ChangeFolder", folder).Store()
+}
+
+// getFile downloads the source file from the current folder to the target path.
+func (o *obexFileTransfer) getFile(targetFile, sourceFile string) (bluetooth.ObjectPushData, error) {
+	return o.transfer("GetFile", targetFile, sourceFile)
+}
+
+// putFile uploads the source file to the current folder with the target name.
+func (o *obexFileTransfer) putFile(sourceFile, targetFile string) (bluetooth.ObjectPushData, error) {
+	return o.transfer("PutFile", sourceFile, targetFile)
+}
+
+// transfer starts a transfer with the provided method, and returns the properties of the transfer.
+func (o *obexFileTransfer) transfer(method, source, target string) (bluetooth.ObjectPushData, error) {
+	var transfer dbus.ObjectPath
+	var props map[string]dbus.Variant
+
+	if err := o.call(method, source, target).Store(&transfer, &props); err != nil {
+		return bluetooth.ObjectPushData{}, err
+	}
+
+	data := bluetooth.ObjectPushData{
+		ObjectPushEventData: bluetooth.ObjectPushEventData{
+			DeviceAddress: o.address,
+			TransferID:    bluetooth.ObjectPushTransferID(transfer),
+			SessionID:     bluetooth.ObjectPushSessionID(o.session),
+		},
+	}
+
+	data.Name, _ = props["Name"].Value().(string)
+	data.Type, _ = props["Type"].Value().(string)
+	data.Filename, _ = props["Filename"].Value().(string)
+	data.Size, _ = props["Size"].Value().(uint64)
+	data.Transferred, _ = props["Transferred"].Value().(uint64)
+
+	status, _ := props["Status"].Value().(string)
+	data.Status = bluetooth.ObjectPushStatus(status)
+
+	if data.Filename == "" && method == "GetFile" {
+		data.Filename = source
+	}
+	if data.Name == "" {
+		data.Name = filepath.Base(target)
+	}
+
+	return data, nil
+}
+
+// call calls the provided method of the "org.bluez.obex.FileTransfer1" interface of the session.
+func (o *obexFileTransfer) call(method string, args ...any) *dbus.Call {
+	return o.conn.Object(obexDest, o.session).
+		Call(obexFileTransferIface+"."+method, 0, args...)
+}
+
+// parseObexTime parses the modification time of a remote file, which is in the
+// ISO 8601 basic format, either in UTC or in local time.
+func parseObexTime(value string) time.Time {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t
+	}
+
+	t, _ := time.ParseInLocation("20060102T150405", value, time.Local)
+
+	return t
+}
+
+func (f *remoteFileInfo) Name() string       { return f.name }
+func (f *remoteFileInfo) Size() int64        { return f.size }
+func (f *remoteFileInfo) ModTime() time.Time { return f.modTime }
+func (f *remoteFileInfo) IsDir() bool        { return f.isDir }
+func (f *remoteFileInfo) Sys() any           { return nil }
+
+func (f *remoteFileInfo) Mode() fs.FileMode {
+	if f.isDir {
+		return fs.ModeDir | 0o755
+	}
+
+	return 0o644
+}
@@ -0,0 +1,337 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	scfg "github.com/bluetuith-org/bluetooth-classic/api/config"
+	"github.com/darkhz/tview"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/google/uuid"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+const (
+	bluezAgentIface        = "org.bluez.Agent1"
+	bluezAgentManagerIface = "org.bluez.AgentManager1"
+	bluezAgentManagerPath  = "/org/bluez"
+
+	// pairingAgentPath is the object path at which the pairing agent is exported.
+	pairingAgentPath dbus.ObjectPath = "/org/bluetuith/agent"
+)
+
+// pinEntryTimeout is the duration within which a pincode or passkey requested by a device has to be entered.
+const pinEntryTimeout = 30 * time.Second
+
+// The errors which are returned to BlueZ if a request is rejected or cancelled.
+var (
+	errAgentRejected = dbus.NewError("org.bluez.Error.Rejected", nil)
+	errAgentCanceled = dbus.NewError("org.bluez.Error.Canceled", nil)
+)
+
+// pairingAgent is a BlueZ agent, which asks the user to enter the pincode or passkey that a device requests
+// while pairing. The agent of the session replies to these requests with a fixed value, so this agent is
+// registered as the default agent over its own connection to the system bus, and pairing requests from the
+// UI are sent over the same connection, so that BlueZ uses this agent for them. All other requests are
+// handled by the authorizer.
+//
+// The exported methods of the agent are called by BlueZ only.
+type pairingAgent struct {
+	v *Views
+
+	conn   *dbus.Conn
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+}
+
+// newPairingAgent returns a new pairing agent.
+func newPairingAgent(v *Views) *pairingAgent {
+	return &pairingAgent{v: v}
+}
+
+// start exports the agent on a new connection to the system bus, and registers it as the default agent
+// with the IO capability from the "agent-capability" option. The agent is only started if the session uses BlueZ.
+func (a *pairingAgent) start() error {
+	if !bluezSupported {
+		return nil
+	}
+
+	conn, err := bluezPrivateSystemBus()
+	if err != nil {
+		return err
+	}
+
+	node := &introspect.Node{
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    bluezAgentIface,
+				Methods: introspect.Methods(a),
+			},
+		},
+	}
+
+	manager := conn.Object(bluezDest, bluezAgentManagerPath)
+
+	for _, fn := range []func() error{
+		func() error { return conn.Export(a, pairingAgentPath, bluezAgentIface) },
+		func() error {
+			return conn.Export(introspect.NewIntrospectable(node), pairingAgentPath, "org.freedesktop.DBus.Introspectable")
+		},
+		func() error {
+			return manager.Call(bluezAgentManagerIface+".RegisterAgent", 0, pairingAgentPath, a.v.cfg.Values.AgentCapability).Err
+		},
+		func() error {
+			return manager.Call(bluezAgentManagerIface+".RequestDefaultAgent", 0, pairingAgentPath).Err
+		},
+	} {
+		if err := fn(); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	a.mu.Lock()
+	a.conn = conn
+	a.mu.Unlock()
+
+	return nil
+}
+
+// stop unregisters the agent, and closes its connection to the system bus.
+func (a *pairingAgent) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn == nil {
+		return
+	}
+
+	a.conn.Object(bluezDest, bluezAgentManagerPath).Call(bluezAgentManagerIface+".UnregisterAgent", 0, pairingAgentPath)
+	a.conn.Close()
+	a.conn = nil
+}
+
+// pair pairs with the device. If the agent is registered, the pairing request is sent over the connection
+// of the agent, so that the pincode or passkey can be entered by the user. Otherwise, the session is used.
+func (a *pairingAgent) pair(address bluetooth.DeviceAddress) error {
+	a.mu.Lock()
+	conn := a.conn
+	a.mu.Unlock()
+
+	if conn == nil {
+		return a.v.app.Session().Device(address).Pair()
+	}
+
+	adapter, err := a.v.app.Session().Adapter(address.AdapterAddress()).Properties()
+	if err != nil {
+		return err
+	}
+
+	return conn.Object(bluezDest, bluezDevicePath(adapter.UniqueName, address.Address)).
+		Call("org.bluez.Device1.Pair", 0).
+		Err
+}
+
+// RequestPinCode asks the user to enter the pincode requested by the device.
+func (a *pairingAgent) RequestPinCode(devicePath dbus.ObjectPath) (string, *dbus.Error) {
+	pincode, err := a.requestInput(devicePath, "pincode", "Pin Code", acceptPinCode)
+	if err != nil {
+		return "", err
+	}
+
+	return pincode, nil
+}
+
+// RequestPasskey asks the user to enter the passkey requested by the device.
+func (a *pairingAgent) RequestPasskey(devicePath dbus.ObjectPath) (uint32, *dbus.Error) {
+	text, err := a.requestInput(devicePath, "passkey", "Passkey", acceptPasskey)
+	if err != nil {
+		return 0, err
+	}
+
+	passkey, perr := strconv.ParseUint(text, 10, 32)
+	if perr != nil {
+		return 0, errAgentRejected
+	}
+
+	return uint32(passkey), nil
+}
+
+// DisplayPinCode displays the pincode of the device using the authorizer.
+func (a *pairingAgent) DisplayPinCode(devicePath dbus.ObjectPath, pincode string) *dbus.Error {
+	return a.authorize(devicePath, func(timeout bluetooth.AuthTimeout, address bluetooth.DeviceAddress) error {
+		return a.v.auth.DisplayPinCode(timeout, pincode, address)
+	})
+}
+
+// DisplayPasskey displays the passkey of the device using the authorizer.
+func (a *pairingAgent) DisplayPasskey(devicePath dbus.ObjectPath, passkey uint32, entered uint16) *dbus.Error {
+	return a.authorize(devicePath, func(timeout bluetooth.AuthTimeout, address bluetooth.DeviceAddress) error {
+		return a.v.auth.DisplayPasskey(timeout, passkey, entered, address)
+	})
+}
+
+// RequestConfirmation asks the user to confirm the passkey of the device using the authorizer.
+func (a *pairingAgent) RequestConfirmation(devicePath dbus.ObjectPath, passkey uint32) *dbus.Error {
+	return a.authorize(devicePath, func(timeout bluetooth.AuthTimeout, address bluetooth.DeviceAddress) error {
+		return a.v.auth.ConfirmPasskey(timeout, passkey, address)
+	})
+}
+
+// RequestAuthorization asks the user to authorize the pairing request using the authorizer.
+func (a *pairingAgent) RequestAuthorization(devicePath dbus.ObjectPath) *dbus.Error {
+	return a.authorize(devicePath, func(timeout bluetooth.AuthTimeout, address bluetooth.DeviceAddress) error {
+		return a.v.auth.AuthorizePairing(timeout, address)
+	})
+}
+
+// AuthorizeService asks the user to authorize the service of the device using the authorizer.
+func (a *pairingAgent) AuthorizeService(devicePath dbus.ObjectPath, serviceUUID string) *dbus.Error {
+	profileUUID, _ := uuid.Parse(serviceUUID)
+
+	return a.authorize(devicePath, func(timeout bluetooth.AuthTimeout, address bluetooth.DeviceAddress) error {
+		return a.v.auth.AuthorizeService(timeout, profileUUID, address)
+	})
+}
+
+// Cancel cancels the request which is currently displayed.
+func (a *pairingAgent) Cancel() *dbus.Error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cancel != nil {
+		a.cancel()
+	}
+
+	return nil
+}
+
+// Release is called when the agent is unregistered by BlueZ.
+func (a *pairingAgent) Release() *dbus.Error {
+	return nil
+}
+
+// requestInput asks the user to enter the pincode or passkey of the device within a modal,
+// which shows the time remaining to enter it.
+func (a *pairingAgent) requestInput(devicePath dbus.ObjectPath, request, title string, accept func(string, rune) bool) (string, *dbus.Error) {
+	address, err := a.deviceAddress(devicePath)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+
+	if a.v.auth.isBlocklisted(address, request+" entry") || a.v.safe.check(address, request+" entry") {
+		return "", errAgentRejected
+	}
+
+	name := address.Address.String()
+	if device, err := a.v.app.Session().Device(address).Properties(); err == nil {
+		name = btdevice.DisplayName(device.DeviceEventData)
+	}
+
+	a.v.notifier.notify("Pairing request", fmt.Sprintf("%s requests a %s", name, request))
+
+	timeout := a.newRequest(pinEntryTimeout)
+	defer a.endRequest(timeout)
+
+	modal := a.v.modals.newInputModal(
+		request+"-entry:"+address.Address.String(), title,
+		fmt.Sprintf("Enter the %s for [::bu]%s[-:-:-]", request, tview.Escape(name)),
+		title+":", accept,
+	)
+
+	text, ok := modal.getInput(timeout)
+	switch {
+	case errors.Is(timeout.Err(), context.Canceled):
+		return "", errAgentCanceled
+
+	case !ok || text == "":
+		return "", errAgentRejected
+	}
+
+	return text, nil
+}
+
+// authorize calls the authorization function with the address of the device and a new timeout.
+func (a *pairingAgent) authorize(devicePath dbus.ObjectPath, authFunc func(bluetooth.AuthTimeout, bluetooth.DeviceAddress) error) *dbus.Error {
+	address, err := a.deviceAddress(devicePath)
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+
+	timeout := a.newRequest(scfg.DefaultAuthTimeout)
+	defer a.endRequest(timeout)
+
+	if err := authFunc(timeout, address); err != nil {
+		return errAgentRejected
+	}
+
+	return nil
+}
+
+// newRequest returns a new timeout for a request, which is cancelled if BlueZ cancels the request.
+func (a *pairingAgent) newRequest(duration time.Duration) bluetooth.AuthTimeout {
+	timeout := bluetooth.NewAuthTimeout(duration)
+
+	a.mu.Lock()
+	a.cancel = timeout.Cancel
+	a.mu.Unlock()
+
+	return timeout
+}
+
+// endRequest releases the timeout of a request once it has been answered.
+func (a *pairingAgent) endRequest(timeout bluetooth.AuthTimeout) {
+	a.mu.Lock()
+	a.cancel = nil
+	a.mu.Unlock()
+
+	timeout.Cancel()
+}
+
+// deviceAddress returns the address of the device and its adapter from the object path of the device,
+// which is of the form "/org/bluez/<adapter>/dev_XX_XX_XX_XX_XX_XX".
+func (a *pairingAgent) deviceAddress(devicePath dbus.ObjectPath) (bluetooth.DeviceAddress, error) {
+	adapterName, device, ok := strings.Cut(strings.TrimPrefix(string(devicePath), "/org/bluez/"), "/")
+	if !ok || !strings.HasPrefix(device, "dev_") {
+		return bluetooth.DeviceAddress{}, fmt.Errorf("invalid device path %s", devicePath)
+	}
+
+	address, err := bluetooth.ParseMAC(strings.ReplaceAll(strings.TrimPrefix(device, "dev_"), "_", ":"))
+	if err != nil {
+		return bluetooth.DeviceAddress{}, err
+	}
+
+	adapters, err := a.v.app.Session().Adapters()
+	if err != nil {
+		return bluetooth.DeviceAddress{}, err
+	}
+
+	for _, adapter := range adapters {
+		if adapter.UniqueName == adapterName {
+			return bluetooth.NewDeviceAddress(address, adapter.Address), nil
+		}
+	}
+
+	return bluetooth.DeviceAddress{}, fmt.Errorf("no adapter found for the device path %s", devicePath)
+}
+
+// acceptPinCode accepts pincodes of up to 16 alphanumeric characters.
+func acceptPinCode(text string, ch rune) bool {
+	return len(text) <= 16 && ch < unicode.MaxASCII && (unicode.IsLetter(ch) || unicode.IsDigit(ch))
+}
+
+// acceptPasskey accepts passkeys of up to 6 digits.
+func acceptPasskey(text string, ch rune) bool {
+	return len(text) <= 6 && unicode.IsDigit(ch)
+}
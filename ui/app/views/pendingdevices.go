@@ -0,0 +1,65 @@
+package views
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// pendingDevices holds the discovered devices which are not listed yet, since
+// their names have not been resolved.
+type pendingDevices struct {
+	timers map[bluetooth.DeviceAddress]*time.Timer
+	mu     sync.Mutex
+}
+
+// newPendingDevices returns a new pending devices tracker.
+func newPendingDevices() *pendingDevices {
+	return &pendingDevices{
+		timers: make(map[bluetooth.DeviceAddress]*time.Timer),
+	}
+}
+
+// hold marks the device as pending, and calls the expired function once the wait has elapsed,
+// unless the device is released before that. If the device is already pending, nothing is done.
+func (p *pendingDevices) hold(address bluetooth.DeviceAddress, wait time.Duration, expired func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.timers[address]; ok {
+		return
+	}
+
+	p.timers[address] = time.AfterFunc(wait, func() {
+		if p.release(address) {
+			expired()
+		}
+	})
+}
+
+// has returns whether the device is pending.
+func (p *pendingDevices) has(address bluetooth.DeviceAddress) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.timers[address]
+
+	return ok
+}
+
+// release stops waiting for the device, and returns whether the device was pending.
+func (p *pendingDevices) release(address bluetooth.DeviceAddress) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	timer, ok := p.timers[address]
+	if !ok {
+		return false
+	}
+
+	timer.Stop()
+	delete(p.timers, address)
+
+	return true
+}
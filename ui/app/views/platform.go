@@ -0,0 +1,30 @@
+//go:build linux
+
+package views
+
+import "github.com/godbus/dbus/v5"
+
+// bluezSupported reports whether the session uses BlueZ, which is the case on Linux.
+// Features which are not provided by the session are retrieved from BlueZ directly,
+// and are only available if it is supported.
+const bluezSupported = true
+
+// desktopBusSupported reports whether the desktop services on the session bus, like
+// the notification service and the MPRIS2 media player interface, are available.
+const desktopBusSupported = true
+
+// bluezSystemBus returns the shared system bus connection, to call BlueZ directly.
+func bluezSystemBus() (*dbus.Conn, error) {
+	return dbus.SystemBus()
+}
+
+// bluezSessionBus returns the shared session bus connection, to call the BlueZ OBEX daemon directly.
+func bluezSessionBus() (*dbus.Conn, error) {
+	return dbus.SessionBus()
+}
+
+// bluezPrivateSystemBus returns a new connection to the system bus, for objects which BlueZ
+// identifies by their connection, like the pairing agent.
+func bluezPrivateSystemBus() (*dbus.Conn, error) {
+	return dbus.ConnectSystemBus()
+}
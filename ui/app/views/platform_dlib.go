@@ -0,0 +1,26 @@
+//go:build !linux
+
+package views
+
+import "github.com/godbus/dbus/v5"
+
+// bluezSupported reports whether the session uses BlueZ, which is not the case on this platform.
+const bluezSupported = false
+
+// desktopBusSupported reports whether the desktop services on the session bus are available.
+const desktopBusSupported = false
+
+// bluezSystemBus returns an error, since BlueZ cannot be called directly on this platform.
+func bluezSystemBus() (*dbus.Conn, error) {
+	return nil, errBluezUnsupported
+}
+
+// bluezSessionBus returns an error, since the BlueZ OBEX daemon cannot be called directly on this platform.
+func bluezSessionBus() (*dbus.Conn, error) {
+	return nil, errBluezUnsupported
+}
+
+// bluezPrivateSystemBus returns an error, since BlueZ cannot be called directly on this platform.
+func bluezPrivateSystemBus() (*dbus.Conn, error) {
+	return nil, errBluezUnsupported
+}
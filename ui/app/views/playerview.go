@@ -2,6 +2,7 @@ package views
 
 import (
 	"errors"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,31 +14,59 @@ import (
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 
+	"github.com/darkhz/bluetuith/ui/btdevice"
 	"github.com/darkhz/bluetuith/ui/keybindings"
 	"github.com/darkhz/bluetuith/ui/theme"
 )
 
+// playerTickInterval is the interval at which the position of the playing track is advanced,
+// between the position updates sent by the device.
+const playerTickInterval = time.Second
+
 // mediaPlayer holds the media player view.
 type mediaPlayer struct {
 	isSupported atomic.Bool
 	isOpen      atomic.Bool
 	skip        atomic.Bool
+	seeking     atomic.Bool
 
-	keyEvent               chan string
-	stopEvent, buttonEvent chan struct{}
+	keyEvent                          chan string
+	stopEvent, buttonEvent, modeEvent chan struct{}
 
 	currentMedia bluetooth.MediaPlayer
 	address      bluetooth.DeviceAddress
 
+	adapterName atomic.String
+	repeat      atomic.String
+	shuffle     atomic.Bool
+
+	state   bluetooth.MediaEventData
+	stateMu sync.Mutex
+
+	bar progressBar
+	art albumArt
+
 	*Views
 
 	sync.Mutex
 }
 
+// progressBar holds the currently rendered progress bar. It is only accessed
+// from the UI goroutine, so it does not need to be locked.
+type progressBar struct {
+	view  *tview.TextView
+	media bluetooth.MediaEventData
+
+	width, offset, length int
+}
+
 // playerElements holds the individual player view display elements.
 type playerElements struct {
-	player                                *tview.Flex
-	info, title, progress, track, buttons *tview.TextView
+	player                                       *tview.Flex
+	info, title, progress, track, buttons, modes *tview.TextView
+
+	art         *tview.Box
+	artProtocol graphicsProtocol
 }
 
 // Initialize initializes the media player.
@@ -47,6 +76,7 @@ func (m *mediaPlayer) Initialize() error {
 	m.stopEvent = make(chan struct{})
 	m.keyEvent = make(chan string, 1)
 	m.buttonEvent = make(chan struct{}, 1)
+	m.modeEvent = make(chan struct{}, 1)
 
 	return nil
 }
@@ -83,6 +113,19 @@ func (m *mediaPlayer) show() {
 	}
 
 	m.address = device.DeviceAddress
+	m.mpris.setPlayer(device.DeviceAddress, properties)
+
+	m.adapterName.Store("")
+	m.repeat.Store("")
+	m.shuffle.Store(false)
+	if adapter, err := m.app.Session().Adapter(device.AdapterAddress()).Properties(); err == nil {
+		m.adapterName.Store(adapter.UniqueName)
+
+		if repeat, shuffle, err := getPlaybackModes(adapter.UniqueName, device.Address); err == nil {
+			m.repeat.Store(repeat)
+			m.shuffle.Store(shuffle != "" && shuffle != "off")
+		}
+	}
 
 	go m.updateLoop(device, properties)
 }
@@ -117,43 +160,87 @@ func (m *mediaPlayer) closeForDevice(deviceAddress bluetooth.DeviceAddress) {
 	}
 }
 
-// renderProgress renders the player progress bar.
+// renderProgress renders the player progress bar, which fills the width of the layout.
+// If the layout is too narrow to display the bar, only the position and duration are shown.
 func (m *mediaPlayer) renderProgress(progressView *tview.TextView, media bluetooth.MediaEventData) {
-	var length int
+	const minLength = 5
 
-	_, _, width, _ := m.pages.GetRect()
-	position := media.Position
+	width := m.progressWidth()
+	position := min(media.Position, media.Duration)
 	duration := media.Duration
 
-	width /= 2
-	if position >= duration {
-		position = duration
-	}
+	m.bar = progressBar{view: progressView, media: media, width: width}
 
-	if duration <= 0 {
-		length = 0
-	} else {
-		length = width * int(position) / int(duration)
+	start := " " + formatDuration(position) + " |"
+	end := "| " + formatDuration(duration) + " "
+
+	length := width - len(start) - len(end)
+	if length < minLength {
+		progressView.SetText(formatDuration(position) + " / " + formatDuration(duration))
+		return
 	}
 
-	endlength := width - length
-	if endlength < 0 {
-		endlength = width
+	var filled int
+	if duration > 0 {
+		filled = length * int(position) / int(duration)
 	}
 
+	m.bar.offset = len(start)
+	m.bar.length = length
+
 	var sb strings.Builder
 
-	sb.WriteString(" ")
-	sb.WriteString(formatDuration(position))
-	sb.WriteString(" |")
-	sb.WriteString(strings.Repeat("█", length))
-	sb.WriteString(strings.Repeat(" ", endlength))
-	sb.WriteString("| ")
-	sb.WriteString(formatDuration(duration))
+	sb.WriteString(start)
+	sb.WriteString(strings.Repeat("█", filled))
+	sb.WriteString(strings.Repeat(" ", length-filled))
+	sb.WriteString(end)
 
 	progressView.SetText(sb.String())
 }
 
+// resizeProgress re-renders the progress bar if the width of the layout has changed.
+func (m *mediaPlayer) resizeProgress() {
+	if m.bar.view == nil {
+		return
+	}
+
+	if m.progressWidth() != m.bar.width {
+		m.renderProgress(m.bar.view, m.bar.media)
+	}
+}
+
+// progressWidth returns the width available to the progress bar, which is
+// the width of the layout without the album art.
+func (m *mediaPlayer) progressWidth() int {
+	_, _, width, _ := m.layout.GetInnerRect()
+
+	return width - m.art.width
+}
+
+// progressMouseHandler seeks the playing track to the position on the progress bar
+// that was clicked.
+func (m *mediaPlayer) progressMouseHandler(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	if action != tview.MouseLeftClick || m.bar.view == nil || m.bar.length == 0 {
+		return action, event
+	}
+
+	x, y := event.Position()
+	if !m.bar.view.InRect(x, y) {
+		return action, event
+	}
+
+	viewX, _, width, _ := m.bar.view.GetInnerRect()
+	column := x - viewX - (width-m.bar.width)/2 - m.bar.offset
+	if column < 0 || column >= m.bar.length || m.bar.media.Duration == 0 {
+		return action, event
+	}
+
+	target := uint32(uint64(m.bar.media.Duration) * uint64(column) / uint64(m.bar.length-1))
+	go m.seekToPosition(target)
+
+	return tview.MouseConsumed, nil
+}
+
 // renderButtons renders the player buttons.
 func (m *mediaPlayer) renderButtons(buttonsView *tview.TextView, mediaStatus bluetooth.MediaStatus, skip bool) {
 	const (
@@ -185,6 +272,22 @@ func (m *mediaPlayer) renderButtons(buttonsView *tview.TextView, mediaStatus blu
 	buttonsView.SetText(sb.String())
 }
 
+// renderModes renders the repeat and shuffle mode indicators.
+func (m *mediaPlayer) renderModes(modesView *tview.TextView) {
+	repeat := m.repeat.Load()
+	if repeat == "" {
+		modesView.Clear()
+		return
+	}
+
+	shuffle := "off"
+	if m.shuffle.Load() {
+		shuffle = "on"
+	}
+
+	modesView.SetText("[::b]Repeat:[-:-:-] " + repeat + " [::b]Shuffle:[-:-:-] " + shuffle)
+}
+
 // renderTrackData renders the track details.
 func (m *mediaPlayer) renderTrackData(infoView, titleView, trackView *tview.TextView, trackData bluetooth.TrackData) {
 	number := strconv.FormatUint(uint64(trackData.TrackNumber), 10)
@@ -214,20 +317,42 @@ func (m *mediaPlayer) renderPlayer(cached bluetooth.MediaEventData, elements pla
 	return track || progress || buttons
 }
 
-// updateLoop updates the media player.
+// setState stores the current state of the player, as tracked by the update loop.
+func (m *mediaPlayer) setState(media bluetooth.MediaEventData) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	m.state = media
+}
+
+// currentState returns the current state of the player, without querying the device.
+func (m *mediaPlayer) currentState() bluetooth.MediaEventData {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	return m.state
+}
+
+// updateLoop updates the media player. The properties of the player are only retrieved once when
+// the player is shown, after which the player is updated from the media events of the device.
+// Since devices do not send position updates continuously, the position of a playing track is
+// advanced locally at each tick.
 func (m *mediaPlayer) updateLoop(device bluetooth.DeviceData, props bluetooth.MediaData) {
 	mediaSub, ok := bluetooth.MediaEvents().Subscribe()
 	if !ok {
 		return
 	}
 
-	deviceName := getDeviceDisplayName(device.DeviceEventData)
+	deviceName := btdevice.DisplayName(device.DeviceEventData)
 
 	elements := m.setup(deviceName)
 	go m.app.QueueDraw(func() {
+		m.art.protocol, m.art.parent, m.art.view = elements.artProtocol, elements.player, elements.art
 		m.help.swapStatusHelp(elements.player, true)
 	})
 	defer m.app.QueueDraw(func() {
+		m.bar = progressBar{}
+		m.art.parent, m.art.view, m.art.image, m.art.handle, m.art.width = nil, nil, nil, "", 0
 		m.help.swapStatusHelp(elements.player, false)
 	})
 
@@ -238,11 +363,20 @@ func (m *mediaPlayer) updateLoop(device bluetooth.DeviceData, props bluetooth.Me
 	if cached.Title == "" {
 		cached.Title = "<No media is playing>"
 	}
+	m.setState(cached)
 
 	m.app.QueueDraw(func() {
 		m.renderPlayer(bluetooth.MediaEventData(props), elements, true, true, true)
+		m.renderModes(elements.modes)
 	})
 
+	if elements.art != nil {
+		go m.updateAlbumArt()
+	}
+
+	ticker := time.NewTicker(playerTickInterval)
+	defer ticker.Stop()
+
 PlayerLoop:
 	for {
 		select {
@@ -254,16 +388,42 @@ PlayerLoop:
 				elements.buttons.Highlight(h)
 			})
 
+		case <-m.modeEvent:
+			go m.app.QueueDraw(func() {
+				m.renderModes(elements.modes)
+			})
+
+		case <-ticker.C:
+			if cached.Status != bluetooth.MediaPlaying || cached.Position >= cached.Duration {
+				continue
+			}
+
+			cached.Position = min(cached.Position+uint32(playerTickInterval.Milliseconds()), cached.Duration)
+			m.setState(cached)
+
+			data := cached
+			m.app.QueueDraw(func() {
+				m.renderProgress(elements.progress, data)
+			})
+
 		case ev, ok := <-mediaSub.UpdatedEvents:
 			if !ok {
 				break PlayerLoop
 			}
+			if ev.DeviceAddress != device.DeviceAddress {
+				continue
+			}
 
 			var track, progress, buttons bool
 
 			if ev.TrackData != (bluetooth.TrackData{}) && ev.TrackData != cached.TrackData {
 				cached.TrackData = ev.TrackData
-				track = true
+				cached.Position = ev.Position
+				track, progress = true, true
+
+				if elements.art != nil {
+					go m.updateAlbumArt()
+				}
 			}
 
 			if ev != (bluetooth.MediaEventData{}) && ev != cached {
@@ -280,6 +440,8 @@ PlayerLoop:
 				}
 			}
 
+			m.setState(cached)
+
 			data := cached
 			m.app.QueueDraw(func() {
 				m.renderPlayer(data, elements, track, progress, buttons)
@@ -305,9 +467,11 @@ func (m *mediaPlayer) setup(deviceName string) playerElements {
 
 	progress := tview.NewTextView()
 	progress.SetDynamicColors(true)
+	progress.SetWrap(false)
 	progress.SetTextAlign(tview.AlignCenter)
 	progress.SetTextColor(theme.GetColor(theme.ThemeText))
 	progress.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	progress.SetMouseCapture(m.progressMouseHandler)
 
 	track := tview.NewTextView()
 	track.SetDynamicColors(true)
@@ -315,6 +479,12 @@ func (m *mediaPlayer) setup(deviceName string) playerElements {
 	track.SetTextColor(theme.GetColor(theme.ThemeText))
 	track.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
+	modes := tview.NewTextView()
+	modes.SetDynamicColors(true)
+	modes.SetTextAlign(tview.AlignCenter)
+	modes.SetTextColor(theme.GetColor(theme.ThemeText))
+	modes.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
 	device := tview.NewTextView()
 	device.SetText(deviceName)
 	device.SetDynamicColors(true)
@@ -383,8 +553,7 @@ func (m *mediaPlayer) setup(deviceName string) playerElements {
 			return action, event
 		}
 
-		keyData := m.kb.Data(key)
-		go m.keyEvents(tcell.NewEventKey(keyData.Kb.Key, keyData.Kb.Rune, keyData.Kb.Mod))
+		go m.keyEvents(key)
 
 		return action, event
 	})
@@ -412,6 +581,8 @@ func (m *mediaPlayer) setup(deviceName string) playerElements {
 		AddItem(nil, 1, 0, false).
 		AddItem(buttons, 0, 1, false).
 		AddItem(nil, 1, 0, false).
+		AddItem(modes, 0, 1, false).
+		AddItem(nil, 1, 0, false).
 		AddItem(device, 0, 1, false)
 	buttonFlex.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
@@ -427,11 +598,35 @@ func (m *mediaPlayer) setup(deviceName string) playerElements {
 		SetDirection(tview.FlexRow)
 	player.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
-	return playerElements{player, info, title, progress, track, buttons}
+	elements := playerElements{
+		info: info, title: title, progress: progress,
+		track: track, buttons: buttons, modes: modes,
+		player: player,
+	}
+
+	// The album art is hidden until the cover art of the track is found.
+	if m.cfg.Values.AlbumArt {
+		elements.artProtocol = detectGraphicsProtocol()
+
+		elements.art = tview.NewBox()
+		elements.art.SetBorderPadding(0, 0, 1, 1)
+		elements.art.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		if elements.artProtocol == graphicsText {
+			elements.art.SetDrawFunc(m.drawTextAlbumArt)
+		}
+
+		elements.player = tview.NewFlex().
+			SetDirection(tview.FlexColumn).
+			AddItem(elements.art, 0, 0, false).
+			AddItem(player, 0, 1, false)
+		elements.player.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	}
+
+	return elements
 }
 
 // keyEvents handles the media player events.
-func (m *mediaPlayer) keyEvents(event *tcell.EventKey) {
+func (m *mediaPlayer) keyEvents(key keybindings.Key) {
 	if !m.isSupported.Load() || !m.isOpen.Load() {
 		return
 	}
@@ -439,7 +634,7 @@ func (m *mediaPlayer) keyEvents(event *tcell.EventKey) {
 	var nokey bool
 	var highlight string
 
-	switch m.kb.Key(event, keybindings.ContextDevice) {
+	switch key {
 	case keybindings.KeyPlayerSeekForward:
 		highlight = "fastforward"
 		m.currentMedia.FastForward()
@@ -462,6 +657,18 @@ func (m *mediaPlayer) keyEvents(event *tcell.EventKey) {
 		highlight = "play"
 		m.currentMedia.Stop()
 
+	case keybindings.KeyPlayerSeekTo:
+		go m.seekTo()
+		return
+
+	case keybindings.KeyPlayerToggleRepeat:
+		go m.toggleRepeat()
+		return
+
+	case keybindings.KeyPlayerToggleShuffle:
+		go m.toggleShuffle()
+		return
+
 	case keybindings.KeyPlayerTogglePlay:
 		highlight = "play"
 		if m.skip.Load() {
@@ -486,16 +693,209 @@ func (m *mediaPlayer) keyEvents(event *tcell.EventKey) {
 	}
 }
 
-// formatDuration converts a duration into a human-readable format.
-func formatDuration(duration uint32) string {
-	var durationtext strings.Builder
+// toggleRepeat cycles the repeat mode of the player.
+func (m *mediaPlayer) toggleRepeat() {
+	if m.repeat.Load() == "" {
+		m.status.InfoMessage("The player does not support repeat modes", false)
+		return
+	}
+
+	repeatModes := []string{"off", "singletrack", "alltracks"}
+
+	index := slices.Index(repeatModes, m.repeat.Load())
+	mode := repeatModes[(index+1)%len(repeatModes)]
+
+	if err := setPlaybackMode(m.adapterName.Load(), m.address.Address, "Repeat", mode); err != nil {
+		m.status.ErrorMessage(err)
+		return
+	}
+
+	m.repeat.Store(mode)
+	m.sendModeEvent()
+}
+
+// toggleShuffle toggles the shuffle mode of the player.
+func (m *mediaPlayer) toggleShuffle() {
+	if !bluezSupported {
+		m.status.InfoMessage("The player does not support shuffle modes", false)
+		return
+	}
+
+	shuffle := !m.shuffle.Load()
+
+	mode := "off"
+	if shuffle {
+		mode = "alltracks"
+	}
+
+	if err := setPlaybackMode(m.adapterName.Load(), m.address.Address, "Shuffle", mode); err != nil {
+		m.status.ErrorMessage(err)
+		return
+	}
+
+	if m.repeat.Load() == "" {
+		m.repeat.Store("off")
+	}
+
+	m.shuffle.Store(shuffle)
+	m.sendModeEvent()
+}
+
+// sendModeEvent notifies the player to render the repeat and shuffle modes.
+func (m *mediaPlayer) sendModeEvent() {
+	select {
+	case m.modeEvent <- struct{}{}:
+
+	default:
+	}
+}
+
+// seekTo asks the user for a position (in the mm:ss format) and seeks the playing track to it.
+func (m *mediaPlayer) seekTo() {
+	if m.currentMedia == nil {
+		return
+	}
+
+	if m.currentState().Duration == 0 {
+		m.status.InfoMessage("The player cannot seek in the current track", false)
+		return
+	}
+
+	input := strings.TrimSpace(m.status.SetInput("Seek to (mm:ss):", struct{}{}))
+	if input == "" {
+		return
+	}
+
+	target, err := parseDuration(input)
+	if err != nil {
+		m.status.ErrorMessage(err)
+		return
+	}
+
+	m.seekToPosition(target)
+}
+
+// seekToPosition seeks the playing track to the provided position (in milliseconds).
+// Since the position cannot be set directly, the track is fast-forwarded or rewound until
+// the reported position is near the requested position. Afterwards, the playback state
+// from before the seek is restored. Only one seek can run at a time, so requests which are
+// made while seeking, for example by repeatedly clicking the progress bar, are ignored.
+func (m *mediaPlayer) seekToPosition(target uint32) {
+	const (
+		pollInterval  = 100 * time.Millisecond
+		seekThreshold = 1000
+		stallLimit    = 20
+		seekTimeout   = time.Minute
+	)
+
+	media := m.currentMedia
+	if media == nil {
+		return
+	}
+
+	if !m.seeking.CompareAndSwap(false, true) {
+		m.status.InfoMessage("The player is already seeking", false)
+		return
+	}
+	defer m.seeking.Store(false)
+
+	props := m.currentState()
+	if props.Duration == 0 {
+		m.status.InfoMessage("The player cannot seek in the current track", false)
+		return
+	}
+	target = min(target, props.Duration)
+
+	var err error
 
-	input, err := time.ParseDuration(strconv.FormatUint(uint64(duration), 10) + "ms")
+	forward := target > props.Position
+	if forward {
+		err = media.FastForward()
+	} else {
+		err = media.Rewind()
+	}
 	if err != nil {
-		return "00:00"
+		m.status.InfoMessage("The player cannot seek in the current track", false)
+		return
+	}
+
+	m.status.InfoMessage("Seeking to "+formatDuration(target), true)
+
+	var stalled int
+
+	position := props.Position
+	deadline := time.Now().Add(seekTimeout)
+
+	for time.Now().Before(deadline) && stalled < stallLimit {
+		time.Sleep(pollInterval)
+
+		props, err := media.Properties()
+		if err != nil {
+			break
+		}
+
+		if props.Position == position {
+			stalled++
+		} else {
+			stalled = 0
+		}
+		position = props.Position
+
+		if (forward && position+seekThreshold >= target) || (!forward && position <= target+seekThreshold) {
+			break
+		}
+	}
+
+	if props.Status == bluetooth.MediaPaused {
+		media.Pause()
+	} else {
+		media.Play()
+	}
+	m.skip.Store(false)
+
+	if stalled >= stallLimit {
+		m.status.InfoMessage("The player cannot seek in the current track", false)
+		return
+	}
+
+	m.status.InfoMessage("Seeked to "+formatDuration(position), false)
+}
+
+// parseDuration parses a duration in the [hh:]mm:ss format into milliseconds.
+func parseDuration(duration string) (uint32, error) {
+	var seconds uint64
+
+	parts := strings.Split(duration, ":")
+	if len(parts) > 3 {
+		return 0, errors.New("invalid position: " + duration)
 	}
 
-	d := input.Round(time.Second)
+	for _, part := range parts {
+		value, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return 0, errors.New("invalid position: " + duration)
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	if seconds*1000 > uint64(^uint32(0)) {
+		return 0, errors.New("invalid position: " + duration)
+	}
+
+	return uint32(seconds * 1000), nil
+}
+
+// formatDuration converts a duration (in milliseconds) into a human-readable format.
+func formatDuration(duration uint32) string {
+	return formatTimeDuration(time.Duration(duration) * time.Millisecond)
+}
+
+// formatTimeDuration converts a duration into a human-readable format.
+func formatTimeDuration(duration time.Duration) string {
+	var durationtext strings.Builder
+
+	d := duration.Round(time.Second)
 
 	h := d / time.Hour
 	d -= h * time.Hour
@@ -0,0 +1,110 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/tview"
+)
+
+func TestMediaPlayerEventUpdates(t *testing.T) {
+	session := newFakeSession(2)
+	device := session.devices[1]
+
+	player := &fakeMediaPlayer{}
+	player.properties.Status = bluetooth.MediaPaused
+	player.properties.Title = "First Track"
+	player.properties.Duration = 60000
+	session.player = player
+
+	v := newFakeViews(session)
+	v.layout = tview.NewFlex()
+	v.help = &helpView{Views: v}
+	v.mpris = &mprisServer{v: v}
+	v.player = &mediaPlayer{}
+	v.player.SetRootView(v)
+	if err := v.player.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	v.device.list()
+	row, _ := v.device.getRowByAddress(device.DeviceAddress)
+	v.device.table.Select(row, 0)
+
+	v.player.show()
+	defer v.player.close()
+
+	waitFor(t, "the player to open", v.player.isOpen.Load)
+
+	var update bluetooth.MediaEventData
+	update.DeviceAddress = device.DeviceAddress
+	update.Status = bluetooth.MediaPaused
+	update.Duration = 90000
+	update.Position = 1000
+
+	for _, title := range []string{"Second Track", "Third Track"} {
+		update.Title = title
+		bluetooth.MediaEvents().PublishUpdated(update)
+
+		waitFor(t, "the player to show "+title, func() bool {
+			state := v.player.currentState()
+			return state.Title == title && state.Position == update.Position
+		})
+	}
+
+	if calls := player.calls.Load(); calls != 1 {
+		t.Fatalf("expected the player properties to be retrieved once, got %d", calls)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     uint32
+		invalid  bool
+	}{
+		{duration: "0", want: 0},
+		{duration: "90", want: 90_000},
+		{duration: "1:30", want: 90_000},
+		{duration: "01:02:03", want: 3_723_000},
+		{duration: "1:75", want: 135_000},
+		{duration: "1193:02:47", want: 4_294_967_000},
+		{duration: "1193:02:48", invalid: true},
+		{duration: "", invalid: true},
+		{duration: "1:", invalid: true},
+		{duration: "-1", invalid: true},
+		{duration: "1:2:3:4", invalid: true},
+		{duration: " 1:00", invalid: true},
+		{duration: "1m30s", invalid: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseDuration(test.duration)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("parseDuration(%q) = %d, want an error", test.duration, got)
+			}
+
+			continue
+		}
+
+		if err != nil || got != test.want {
+			t.Errorf("parseDuration(%q) = %d, %v, want %d", test.duration, got, err, test.want)
+		}
+	}
+}
+
+// waitFor waits until the condition is true, and fails the test if it is not true within a second.
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
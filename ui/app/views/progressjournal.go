@@ -0,0 +1,240 @@
+package views
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/config"
+)
+
+const transferJournalFileName = "transfers.json"
+
+// transferJournal describes a record of pending outgoing file transfers,
+// which is stored in the state directory so that unfinished transfers
+// can be resumed after the application is restarted.
+type transferJournal struct {
+	path    string
+	entries []transferJournalEntry
+
+	mu sync.Mutex
+}
+
+// transferJournalEntry describes a list of files that are being sent to a device.
+type transferJournalEntry struct {
+	Address bluetooth.DeviceAddress `json:"address"`
+	Files   []transferJournalFile   `json:"files"`
+}
+
+// transferJournalFile describes a file that is being sent to a device.
+type transferJournalFile struct {
+	TransferID bluetooth.ObjectPushTransferID `json:"transfer_id"`
+	Path       string                         `json:"path"`
+}
+
+// newTransferJournal loads the transfer journal from the state directory.
+func newTransferJournal(cfg *config.Config) (*transferJournal, error) {
+	journal := &transferJournal{}
+
+	path, err := cfg.StateFilePath(transferJournalFileName)
+	if err != nil {
+		return journal, err
+	}
+
+	journal.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return journal, nil
+		}
+
+		return journal, err
+	}
+
+	if len(data) == 0 {
+		return journal, nil
+	}
+
+	if err := json.Unmarshal(data, &journal.entries); err != nil {
+		return journal, fmt.Errorf("cannot parse the transfer journal: %w", err)
+	}
+
+	return journal, nil
+}
+
+// add records the files that are being sent to a device.
+func (j *transferJournal) add(address bluetooth.DeviceAddress, files []bluetooth.ObjectPushData) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry := transferJournalEntry{Address: address}
+	for _, file := range files {
+		if file.Receiving || file.Filename == "" {
+			continue
+		}
+
+		entry.Files = append(entry.Files, transferJournalFile{TransferID: file.TransferID, Path: file.Filename})
+	}
+
+	if entry.Files == nil {
+		return nil
+	}
+
+	j.entries = append(j.entries, entry)
+
+	return j.write()
+}
+
+// start records the transfer ID of a queued file, once it is being sent to a device.
+func (j *transferJournal) start(address bluetooth.DeviceAddress, path string, transferID bluetooth.ObjectPushTransferID) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.entries {
+		if j.entries[i].Address != address {
+			continue
+		}
+
+		for f, file := range j.entries[i].Files {
+			if file.TransferID == "" && file.Path == path {
+				j.entries[i].Files[f].TransferID = transferID
+				return j.write()
+			}
+		}
+	}
+
+	return nil
+}
+
+// remove removes a transferred file from the journal.
+func (j *transferJournal) remove(address bluetooth.DeviceAddress, transferID bluetooth.ObjectPushTransferID) error {
+	return j.removeFunc(address, func(file transferJournalFile) bool {
+		return file.TransferID == transferID
+	})
+}
+
+// removeQueued removes a file, which was queued but not sent, from the journal.
+func (j *transferJournal) removeQueued(address bluetooth.DeviceAddress, path string) error {
+	removed := false
+
+	return j.removeFunc(address, func(file transferJournalFile) bool {
+		if removed || file.TransferID != "" || file.Path != path {
+			return false
+		}
+
+		removed = true
+
+		return true
+	})
+}
+
+// removeFunc removes the files of a device which match the provided function from the journal.
+func (j *transferJournal) removeFunc(address bluetooth.DeviceAddress, match func(file transferJournalFile) bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var modified bool
+
+	for i := range j.entries {
+		if j.entries[i].Address != address {
+			continue
+		}
+
+		files := slices.DeleteFunc(j.entries[i].Files, match)
+		if len(files) != len(j.entries[i].Files) {
+			modified = true
+		}
+
+		j.entries[i].Files = files
+	}
+
+	if !modified {
+		return nil
+	}
+
+	j.entries = slices.DeleteFunc(j.entries, func(entry transferJournalEntry) bool {
+		return len(entry.Files) == 0
+	})
+
+	return j.write()
+}
+
+// pending returns all unfinished transfers, and clears the journal.
+func (j *transferJournal) pending() ([]transferJournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := j.entries
+	j.entries = nil
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	return entries, j.write()
+}
+
+// write stores the journal in the state directory.
+// This must be called with the lock held.
+func (j *transferJournal) write() error {
+	if j.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(j.entries)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(j.path, data, 0o600)
+}
+
+// resumeTransfers asks the user whether to resume any transfers which were
+// not completed when the application last exited, and resends the files.
+func (p *progressView) resumeTransfers() {
+	entries, err := p.journal.pending()
+	if err != nil {
+		p.status.ErrorMessage(err)
+	}
+
+	for _, entry := range entries {
+		name := p.deviceName(entry.Address)
+		count := strconv.Itoa(len(entry.Files))
+		if p.status.SetInput("Resume "+count+" unfinished transfer(s) to "+name+" (y/n)?") != "y" {
+			continue
+		}
+
+		p.status.InfoMessage("Initializing Object Push session..", true)
+		oppSession := p.app.Session().Obex(entry.Address).ObjectPush()
+		if err := oppSession.CreateSession(context.Background()); err != nil {
+			p.status.ErrorMessage(err)
+			continue
+		}
+
+		files := make([]string, 0, len(entry.Files))
+		for _, file := range entry.Files {
+			if _, err := os.Stat(file.Path); err != nil {
+				p.status.ErrorMessage(errors.New("skipping " + file.Path + ", the file is not accessible"))
+				continue
+			}
+
+			files = append(files, file.Path)
+		}
+
+		if len(files) == 0 {
+			oppSession.RemoveSession()
+			continue
+		}
+
+		p.queueTransfer(entry.Address, oppSession, files)
+	}
+}
@@ -1,11 +1,17 @@
 package views
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +23,8 @@ import (
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/schollz/progressbar/v3"
 
+	"github.com/darkhz/bluetuith/ui/btdevice"
+	"github.com/darkhz/bluetuith/ui/config"
 	"github.com/darkhz/bluetuith/ui/keybindings"
 	"github.com/darkhz/bluetuith/ui/theme"
 )
@@ -30,11 +38,14 @@ type progressView struct {
 	isSupported atomic.Bool
 
 	view, statusProgress *tview.Table
+	totals               *tview.TextView
 	flex                 *tview.Flex
 
 	total atomic.Uint32
 
-	sessions *xsync.MapOf[bluetooth.DeviceAddress, *progressViewSession]
+	sessions      *xsync.MapOf[bluetooth.DeviceAddress, *progressViewSession]
+	fileTransfers *xsync.MapOf[bluetooth.DeviceAddress, *progressViewSession]
+	journal       *transferJournal
 
 	*Views
 }
@@ -42,12 +53,29 @@ type progressView struct {
 type progressViewSession struct {
 	sessionRemoved bool
 
+	// sending is set while a queued file is being sent, and finished holds
+	// the transfers which ended before the file was recorded as being sent.
+	sending  bool
+	finished map[bluetooth.ObjectPushTransferID]bool
+
 	transferSession bluetooth.ObexObjectPush
 	transfers       map[bluetooth.ObjectPushTransferID]struct{}
+	queue           []*queuedTransfer
+
+	files, completedFiles uint32
+	size, completedSize   uint64
 
 	mu sync.Mutex
 }
 
+// queuedTransfer describes a file which is waiting to be sent to a device.
+type queuedTransfer struct {
+	address    bluetooth.DeviceAddress
+	deviceName string
+	path       string
+	size       uint64
+}
+
 // progressIndicator describes a progress indicator, which will display
 // a description and a progress bar.
 type progressIndicator struct {
@@ -58,11 +86,31 @@ type progressIndicator struct {
 	recv, drawn bool
 	status      bluetooth.ObjectPushStatus
 
+	size     uint64
+	samples  []transferSample
+	rateText string
+	barText  string
+
 	deviceAddress bluetooth.DeviceAddress
 
 	appDrawFunc func(func())
 }
 
+// transferSample describes the number of bytes transferred at a point in time.
+type transferSample struct {
+	at          time.Time
+	transferred uint64
+}
+
+const (
+	// transferRateWindow is the duration of the rolling window of samples
+	// used to calculate the transfer speed.
+	transferRateWindow = 5 * time.Second
+
+	// transferRateMaxSamples is the maximum number of samples in the rolling window.
+	transferRateMaxSamples = 32
+)
+
 func (p *progressView) Initialize() error {
 	title := tview.NewTextView()
 	title.SetDynamicColors(true)
@@ -70,6 +118,12 @@ func (p *progressView) Initialize() error {
 	title.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 	title.SetText(theme.ColorWrap(theme.ThemeText, "Progress View", "::bu"))
 
+	p.totals = tview.NewTextView()
+	p.totals.SetDynamicColors(true)
+	p.totals.SetTextAlign(tview.AlignLeft)
+	p.totals.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	p.totals.SetTextColor(theme.GetColor(theme.ThemeProgressText))
+
 	p.view = tview.NewTable()
 	p.view.SetSelectable(true, false)
 	p.view.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
@@ -91,12 +145,31 @@ func (p *progressView) Initialize() error {
 		case keybindings.KeyProgressTransferResume:
 			p.resumeTransfer()
 
+		case keybindings.KeyProgressTransferToggleAll:
+			p.toggleAllTransfers()
+
+		case keybindings.KeyProgressQueueFront:
+			p.moveQueuedToFront()
+
+		case keybindings.KeyProgressQueueCancel:
+			p.cancelQueue()
+
+		case keybindings.KeyHelpContext:
+			p.help.showContextHelp()
+
 		case keybindings.KeyQuit:
 			go p.actions.quit()
 		}
 
 		return ignoreDefaultEvent(event)
 	})
+	p.view.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if p.scrollTable(p.view, action, event) {
+			return action, nil
+		}
+
+		return action, event
+	})
 
 	progressViewButtons := tview.NewTextView()
 	progressViewButtons.SetRegions(true)
@@ -128,6 +201,7 @@ func (p *progressView) Initialize() error {
 	p.flex = tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(title, 1, 0, false).
+		AddItem(p.totals, 1, 0, false).
 		AddItem(p.view, 0, 10, true).
 		AddItem(progressViewButtons, 2, 0, false)
 
@@ -139,8 +213,16 @@ func (p *progressView) Initialize() error {
 
 	p.isSupported.Store(true)
 	p.sessions = xsync.NewMapOf[bluetooth.DeviceAddress, *progressViewSession]()
+	p.fileTransfers = xsync.NewMapOf[bluetooth.DeviceAddress, *progressViewSession]()
+
+	journal, err := newTransferJournal(p.cfg)
+	if err != nil {
+		p.status.ErrorMessage(err)
+	}
+	p.journal = journal
 
 	go p.monitorTransfers()
+	go p.resumeTransfers()
 
 	return nil
 }
@@ -183,12 +265,14 @@ func (p *progressView) showStatus() {
 // newIndicator returns a new Progress.
 func (p *progressView) newIndicator(props bluetooth.ObjectPushData, recv bool) *progressIndicator {
 	var progress progressIndicator
-	var progressText string
+	var progressText, deviceText string
 
 	if recv {
 		progressText = "Receiving"
+		deviceText = "from"
 	} else {
 		progressText = "Sending"
+		deviceText = "to"
 	}
 
 	p.total.Add(1)
@@ -202,9 +286,13 @@ func (p *progressView) newIndicator(props bluetooth.ObjectPushData, recv bool) *
 		name = "Unknown file transfer"
 	}
 
-	title := fmt.Sprintf(" [::b]%s %s[-:-:-]", progressText, name)
+	title := fmt.Sprintf(
+		" [::b]%s %s[-:-:-] %s [::b]%s[-:-:-]",
+		progressText, tview.Escape(name), deviceText, tview.Escape(p.deviceName(props.DeviceAddress)),
+	)
 
 	progress.recv = recv
+	progress.size = props.Size
 	progress.deviceAddress = props.DeviceAddress
 	progress.appDrawFunc = p.app.QueueDraw
 
@@ -242,6 +330,11 @@ func (p *progressView) drawIndicator(progress *progressIndicator, props bluetoot
 	count := p.total.Load()
 	p.app.QueueDraw(func() {
 		rows := p.view.GetRowCount()
+		if row, ok := p.firstQueuedRow(); ok {
+			p.view.InsertRow(row - 1)
+			p.view.InsertRow(row - 1)
+			rows = row - 1
+		}
 
 		p.statusProgress.SetCell(0, 0, progress.desc)
 		p.statusProgress.SetCell(0, 1, progress.progress)
@@ -323,6 +416,8 @@ func (p *progressView) monitorTransfers() {
 
 	updateIndicator := func(property *transferProperty, ev bluetooth.ObjectPushEventData) {
 		p.drawIndicator(property.indicator, property.ObjectPushEventData)
+		property.indicator.setStatus(ev.Status)
+		property.indicator.updateRate(ev)
 		property.indicator.progressBar.Set64(int64(ev.Transferred))
 
 		switch property.Status {
@@ -367,19 +462,381 @@ Transfer:
 	}
 }
 
-// startTransfer creates a new progress indicator, monitors the OBEX DBus interface for transfer events,
-// and displays the progress on the screen. If the optional path parameter is provided, it means that
-// a file is being received, and on transfer completion, the received file should be moved to a user-accessible
-// directory.
-func (p *progressView) startTransfer(address bluetooth.DeviceAddress, session bluetooth.ObexObjectPush, files []bluetooth.ObjectPushData) {
-	psession := &progressViewSession{transferSession: session}
-	psession.transfers = make(map[bluetooth.ObjectPushTransferID]struct{})
+// startTransfer tracks the transfers of a File Transfer session, so that they are included
+// in the totals of the progress view. The transfers are kept separate from the Object Push
+// session of the device, and are added to the earlier transfers of the session if they
+// have not completed yet.
+func (p *progressView) startTransfer(address bluetooth.DeviceAddress, files []bluetooth.ObjectPushData) {
+	var psession *progressViewSession
+	for {
+		psession, _ = p.fileTransfers.LoadOrCompute(address, func() *progressViewSession {
+			return &progressViewSession{transfers: make(map[bluetooth.ObjectPushTransferID]struct{})}
+		})
+
+		psession.mu.Lock()
+		if !psession.sessionRemoved {
+			break
+		}
+		psession.mu.Unlock()
+	}
+
 	for _, f := range files {
+		if _, ok := psession.transfers[f.TransferID]; ok {
+			continue
+		}
+
 		psession.transfers[f.TransferID] = struct{}{}
+		psession.files++
+		psession.size += f.Size
+	}
+	psession.mu.Unlock()
+
+	p.updateTotals()
+	p.showStatus()
+}
+
+// finishFileTransfer records the completion of a transfer of a File Transfer session, and
+// removes the session once all of its transfers have ended. It returns false if the transfer
+// does not belong to a File Transfer session.
+func (p *progressView) finishFileTransfer(transferProps bluetooth.ObjectPushData, isComplete bool) bool {
+	psession, ok := p.fileTransfers.Load(transferProps.DeviceAddress)
+	if !ok {
+		return false
+	}
+
+	psession.mu.Lock()
+	defer psession.mu.Unlock()
+
+	if _, ok := psession.transfers[transferProps.TransferID]; !ok {
+		return false
+	}
+
+	if isComplete {
+		psession.completedFiles++
+		psession.completedSize += transferProps.Size
+	}
+
+	delete(psession.transfers, transferProps.TransferID)
+	if len(psession.transfers) == 0 {
+		psession.sessionRemoved = true
+		p.fileTransfers.Delete(transferProps.DeviceAddress)
+	}
+
+	return true
+}
+
+// queueTransfer queues files to be sent to a device over the Object Push session.
+// The files are sent one at a time, so that the files which are yet to be sent
+// can be reordered or cancelled from the progress view.
+func (p *progressView) queueTransfer(address bluetooth.DeviceAddress, session bluetooth.ObexObjectPush, files []string) {
+	psession := &progressViewSession{transferSession: session}
+	psession.transfers = make(map[bluetooth.ObjectPushTransferID]struct{})
+
+	psession.mu.Lock()
+	journalFiles := p.enqueue(address, psession, files)
+	psession.mu.Unlock()
+
+	if err := p.journal.add(address, journalFiles); err != nil {
+		p.status.ErrorMessage(err)
 	}
 
 	p.sessions.Store(address, psession)
+	p.updateTotals()
 	p.showStatus()
+
+	p.sendNext(address, psession)
+}
+
+// appendQueue adds files to the queue of the Object Push session of a device, if the files
+// of the session are still being sent. It returns false if the device has no such session,
+// in which case a new session has to be created.
+func (p *progressView) appendQueue(address bluetooth.DeviceAddress, files []string) bool {
+	psession, ok := p.sessions.Load(address)
+	if !ok || psession.transferSession == nil {
+		return false
+	}
+
+	psession.mu.Lock()
+	if psession.sessionRemoved {
+		psession.mu.Unlock()
+		return false
+	}
+	journalFiles := p.enqueue(address, psession, files)
+	psession.mu.Unlock()
+
+	if err := p.journal.add(address, journalFiles); err != nil {
+		p.status.ErrorMessage(err)
+	}
+
+	p.updateTotals()
+	p.sendNext(address, psession)
+
+	return true
+}
+
+// enqueue adds files to the queue of the session, and returns the files which have to be recorded in the journal.
+// This must be called with the session lock held.
+func (p *progressView) enqueue(address bluetooth.DeviceAddress, psession *progressViewSession, files []string) []bluetooth.ObjectPushData {
+	name := p.deviceName(address)
+
+	journalFiles := make([]bluetooth.ObjectPushData, 0, len(files))
+	for _, file := range files {
+		var size uint64
+		if info, err := os.Stat(file); err == nil {
+			size = uint64(info.Size())
+		}
+
+		psession.queue = append(psession.queue, &queuedTransfer{address: address, deviceName: name, path: file, size: size})
+		psession.files++
+		psession.size += size
+
+		journalFiles = append(journalFiles, bluetooth.ObjectPushData{Filename: file})
+	}
+
+	return journalFiles
+}
+
+// sendNext sends the next queued file of the session, if no file is currently being sent.
+// Files which cannot be sent are skipped, and the session is removed if no files are left.
+func (p *progressView) sendNext(address bluetooth.DeviceAddress, psession *progressViewSession) {
+	psession.mu.Lock()
+	for !psession.sessionRemoved && !psession.sending && len(psession.transfers) == 0 {
+		if len(psession.queue) == 0 {
+			p.removeSession(address, psession)
+			break
+		}
+
+		queued := psession.queue[0]
+		psession.queue = psession.queue[1:]
+		psession.sending = true
+
+		psession.mu.Unlock()
+		props, err := psession.transferSession.SendFile(queued.path)
+		psession.mu.Lock()
+
+		psession.sending = false
+		if err != nil || props.Status == bluetooth.TransferError {
+			if err == nil {
+				err = errors.New("cannot send " + queued.path)
+			}
+			p.status.ErrorMessage(err)
+
+			if err := p.journal.removeQueued(address, queued.path); err != nil {
+				p.status.ErrorMessage(err)
+			}
+
+			continue
+		}
+
+		if complete, ok := psession.finished[props.TransferID]; ok {
+			delete(psession.finished, props.TransferID)
+			if complete {
+				psession.completedFiles++
+				psession.completedSize += queued.size
+
+				if err := p.journal.removeQueued(address, queued.path); err != nil {
+					p.status.ErrorMessage(err)
+				}
+			}
+
+			continue
+		}
+
+		psession.transfers[props.TransferID] = struct{}{}
+
+		if err := p.journal.start(address, queued.path, props.TransferID); err != nil {
+			p.status.ErrorMessage(err)
+		}
+	}
+	psession.mu.Unlock()
+
+	p.drawQueue(nil)
+}
+
+// removeSession removes the transfer session of a device.
+// This must be called with the session lock held.
+func (p *progressView) removeSession(address bluetooth.DeviceAddress, psession *progressViewSession) {
+	psession.sessionRemoved = true
+
+	if psession.transferSession != nil {
+		go psession.transferSession.RemoveSession()
+	}
+
+	p.sessions.Delete(address)
+}
+
+// moveQueuedToFront moves the selected queued file to the front of its queue,
+// so that it is sent after the current transfer completes.
+// This must be called from the UI goroutine, since the queue is redrawn in the background.
+func (p *progressView) moveQueuedToFront() {
+	queued, ok := p.queuedSelection()
+	if !ok {
+		return
+	}
+
+	go p.moveToFront(queued)
+}
+
+// moveToFront moves the queued file to the front of its queue.
+func (p *progressView) moveToFront(queued *queuedTransfer) {
+	psession, ok := p.sessions.Load(queued.address)
+	if !ok {
+		return
+	}
+
+	psession.mu.Lock()
+	if index := slices.Index(psession.queue, queued); index > 0 {
+		psession.queue = slices.Insert(slices.Delete(psession.queue, index, index+1), 0, queued)
+	}
+	psession.mu.Unlock()
+
+	p.drawQueue(queued)
+}
+
+// cancelQueued removes a queued file from its queue.
+func (p *progressView) cancelQueued(queued *queuedTransfer) {
+	psession, ok := p.sessions.Load(queued.address)
+	if !ok {
+		return
+	}
+
+	psession.mu.Lock()
+	if index := slices.Index(psession.queue, queued); index >= 0 {
+		psession.queue = slices.Delete(psession.queue, index, index+1)
+		psession.files--
+		psession.size -= queued.size
+
+		if err := p.journal.removeQueued(queued.address, queued.path); err != nil {
+			p.status.ErrorMessage(err)
+		}
+	}
+	psession.mu.Unlock()
+
+	p.updateTotals()
+	p.drawQueue(nil)
+}
+
+// cancelQueue removes all the queued files of the device whose transfer or queued file is selected.
+// The files which are currently being transferred are not cancelled.
+// This must be called from the UI goroutine, since the queue is redrawn in the background.
+func (p *progressView) cancelQueue() {
+	var address bluetooth.DeviceAddress
+	if queued, ok := p.queuedSelection(); ok {
+		address = queued.address
+	} else if transferProps, _ := p.transferData(); !transferProps.Address.IsNil() {
+		address = transferProps.DeviceAddress
+	} else {
+		return
+	}
+
+	go p.cancelDeviceQueue(address)
+}
+
+// cancelDeviceQueue removes all the queued files of the device.
+func (p *progressView) cancelDeviceQueue(address bluetooth.DeviceAddress) {
+	psession, ok := p.sessions.Load(address)
+	if !ok {
+		return
+	}
+
+	psession.mu.Lock()
+	queue := psession.queue
+	psession.queue = nil
+	for _, queued := range queue {
+		psession.files--
+		psession.size -= queued.size
+
+		if err := p.journal.removeQueued(queued.address, queued.path); err != nil {
+			p.status.ErrorMessage(err)
+		}
+	}
+	psession.mu.Unlock()
+
+	if len(queue) > 0 {
+		p.status.InfoMessage("Cancelled "+strconv.Itoa(len(queue))+" queued file(s)", false)
+	}
+
+	p.updateTotals()
+	p.drawQueue(nil)
+}
+
+// drawQueue draws the files which are waiting to be sent after the current transfers.
+// If a queued file is provided, it is selected after the queue is drawn.
+func (p *progressView) drawQueue(selected *queuedTransfer) {
+	var queue []*queuedTransfer
+
+	p.sessions.Range(func(_ bluetooth.DeviceAddress, psession *progressViewSession) bool {
+		psession.mu.Lock()
+		queue = append(queue, psession.queue...)
+		psession.mu.Unlock()
+
+		return true
+	})
+
+	p.app.QueueDraw(func() {
+		for row := p.view.GetRowCount() - 1; row > 0; row-- {
+			if _, ok := p.queuedAt(row); ok {
+				p.view.RemoveRow(row)
+				p.view.RemoveRow(row - 1)
+				row--
+			}
+		}
+
+		for _, queued := range queue {
+			row := p.view.GetRowCount() + 1
+
+			p.view.SetCell(row, 0, tview.NewTableCell("-").
+				SetReference(queued).
+				SetAlign(tview.AlignCenter),
+			)
+			p.view.SetCell(row, 1, tview.NewTableCell(" [::b]Queued "+tview.Escape(filepath.Base(queued.path))+"[-:-:-] for [::b]"+tview.Escape(queued.deviceName)+"[-:-:-]").
+				SetExpansion(1).
+				SetSelectable(false).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(theme.ThemeProgressText)),
+			)
+			p.view.SetCell(row, 2, tview.NewTableCell(formatSize(int64(queued.size))).
+				SetExpansion(1).
+				SetSelectable(false).
+				SetAlign(tview.AlignRight).
+				SetTextColor(theme.GetColor(theme.ThemeProgressText)),
+			)
+
+			if queued == selected {
+				p.view.Select(row, 0)
+			}
+		}
+	})
+}
+
+// firstQueuedRow returns the row of the first queued file in the progress view.
+func (p *progressView) firstQueuedRow() (int, bool) {
+	for row := range p.view.GetRowCount() {
+		if _, ok := p.queuedAt(row); ok {
+			return row, true
+		}
+	}
+
+	return 0, false
+}
+
+// queuedSelection returns the queued file which is currently selected in the progress view.
+func (p *progressView) queuedSelection() (*queuedTransfer, bool) {
+	row, _ := p.view.GetSelection()
+
+	return p.queuedAt(row)
+}
+
+// queuedAt returns the queued file at the provided row of the progress view.
+func (p *progressView) queuedAt(row int) (*queuedTransfer, bool) {
+	cell := p.view.GetCell(row, 0)
+	if cell == nil {
+		return nil, false
+	}
+
+	queued, ok := cell.GetReference().(*queuedTransfer)
+
+	return queued, ok
 }
 
 // suspendTransfer suspends the transfer.
@@ -414,8 +871,73 @@ func (p *progressView) resumeTransfer() {
 	p.app.Session().Obex(progress.deviceAddress).ObjectPush().ResumeTransfer()
 }
 
-// cancelTransfer cancels the transfer.
+// toggleAllTransfers suspends all outgoing transfers, or resumes them if all of them are suspended.
+// Receiving transfers cannot be suspended, and are skipped.
+func (p *progressView) toggleAllTransfers() {
+	var receiving int
+	suspended := true
+
+	outgoing := make(map[bluetooth.DeviceAddress]struct{})
+	for row := range p.view.GetRowCount() {
+		cell := p.view.GetCell(row, 2)
+		if cell == nil {
+			continue
+		}
+
+		progress, ok := cell.GetReference().(*progressIndicator)
+		if !ok {
+			continue
+		}
+
+		if progress.recv {
+			receiving++
+			continue
+		}
+
+		if progress.status != bluetooth.TransferSuspended {
+			suspended = false
+		}
+		outgoing[progress.deviceAddress] = struct{}{}
+	}
+
+	if receiving > 0 {
+		p.status.InfoMessage("Skipping "+strconv.Itoa(receiving)+" receiving transfer(s), which cannot be suspended", false)
+	}
+
+	if len(outgoing) == 0 {
+		return
+	}
+
+	p.sessions.Range(func(address bluetooth.DeviceAddress, _ *progressViewSession) bool {
+		if _, ok := outgoing[address]; !ok {
+			return true
+		}
+
+		go func() {
+			objectPush := p.app.Session().Obex(address).ObjectPush()
+
+			var err error
+			if suspended {
+				err = objectPush.ResumeTransfer()
+			} else {
+				err = objectPush.SuspendTransfer()
+			}
+			if err != nil {
+				p.status.ErrorMessage(err)
+			}
+		}()
+
+		return true
+	})
+}
+
+// cancelTransfer cancels the transfer, or removes the file from the queue if it is not being sent yet.
 func (p *progressView) cancelTransfer() {
+	if queued, ok := p.queuedSelection(); ok {
+		go p.cancelQueued(queued)
+		return
+	}
+
 	transferProps, progress := p.transferData()
 	if transferProps.Address.IsNil() {
 		return
@@ -431,22 +953,51 @@ func (p *progressView) removeProgress(transferProps bluetooth.ObjectPushData) {
 	isComplete := transferProps.Status == bluetooth.TransferComplete
 	path := transferProps.Filename
 
-	if psession, ok := p.sessions.Load(transferProps.DeviceAddress); ok {
-		psession.mu.Lock()
-		if !psession.sessionRemoved {
-			delete(psession.transfers, transferProps.TransferID)
+	if isComplete && !transferProps.Receiving {
+		if err := p.journal.remove(transferProps.DeviceAddress, transferProps.TransferID); err != nil {
+			p.status.ErrorMessage(err)
+		}
+	}
 
-			if isComplete && len(psession.transfers) == 0 {
-				psession.sessionRemoved = true
+	if p.finishFileTransfer(transferProps, isComplete) {
+		p.updateTotals()
+	} else if psession, ok := p.sessions.Load(transferProps.DeviceAddress); ok {
+		var sendNext bool
 
-				if psession.transferSession != nil {
-					go psession.transferSession.RemoveSession()
+		psession.mu.Lock()
+		if psession.sending {
+			if _, ok := psession.transfers[transferProps.TransferID]; !ok {
+				if psession.finished == nil {
+					psession.finished = make(map[bluetooth.ObjectPushTransferID]bool)
 				}
 
-				p.sessions.Delete(transferProps.DeviceAddress)
+				psession.finished[transferProps.TransferID] = isComplete
+			}
+		}
+
+		if !psession.sessionRemoved && !psession.sending {
+			if _, ok := psession.transfers[transferProps.TransferID]; ok && isComplete {
+				psession.completedFiles++
+				psession.completedSize += transferProps.Size
+			}
+
+			delete(psession.transfers, transferProps.TransferID)
+
+			switch {
+			case len(psession.transfers) == 0 && len(psession.queue) > 0:
+				sendNext = true
+
+			case len(psession.transfers) == 0 && isComplete:
+				p.removeSession(transferProps.DeviceAddress, psession)
 			}
 		}
 		psession.mu.Unlock()
+
+		p.updateTotals()
+
+		if sendNext {
+			go p.sendNext(transferProps.DeviceAddress, psession)
+		}
 	}
 
 	p.app.QueueDraw(func() {
@@ -475,15 +1026,65 @@ func (p *progressView) removeProgress(transferProps bluetooth.ObjectPushData) {
 		}
 	})
 
+	if isComplete {
+		go p.notifyComplete(transferProps)
+	}
+
 	if path != "" && isComplete && transferProps.Receiving {
 		go func() {
-			if err := savefile(path, p.cfg.Values.ReceiveDir); err != nil {
+			var deviceDir string
+			if p.cfg.Values.ReceiveDirPerDevice {
+				deviceDir = p.deviceDirName(transferProps.DeviceAddress)
+			}
+
+			savedPath, err := savefile(path, p.cfg.Values.ReceiveDir, deviceDir)
+			if err != nil {
 				p.status.ErrorMessage(err)
+				return
+			}
+
+			if p.cfg.Values.VerifyReceived {
+				p.verifyReceived(savedPath, transferProps.Size)
 			}
 		}()
 	}
 }
 
+// updateTotals displays the number of files and bytes which have been transferred,
+// out of the total number of files and bytes, across all the current transfer sessions.
+func (p *progressView) updateTotals() {
+	var files, completedFiles uint32
+	var size, completedSize uint64
+
+	addTotals := func(_ bluetooth.DeviceAddress, psession *progressViewSession) bool {
+		psession.mu.Lock()
+		defer psession.mu.Unlock()
+
+		files += psession.files
+		completedFiles += psession.completedFiles
+		size += psession.size
+		completedSize += psession.completedSize
+
+		return true
+	}
+
+	p.sessions.Range(addTotals)
+	p.fileTransfers.Range(addTotals)
+
+	var text string
+	if files > 0 {
+		text = fmt.Sprintf(
+			" %d of %d files, %s of %s",
+			completedFiles, files,
+			formatSize(int64(completedSize)), formatSize(int64(size)),
+		)
+	}
+
+	p.app.QueueDraw(func() {
+		p.totals.SetText(text)
+	})
+}
+
 // transferData gets the file transfer properties and the progress data
 // from the current selection in the progress view.
 func (p *progressView) transferData() (bluetooth.ObjectPushEventData, *progressIndicator) {
@@ -512,34 +1113,221 @@ func (p *progressView) transferData() (bluetooth.ObjectPushEventData, *progressI
 	return props, progress
 }
 
+// updateRate records the number of bytes transferred, and calculates the transfer
+// speed and the estimated time remaining from the rolling window of samples.
+// The samples are discarded if the transfer is suspended, so that the speed is
+// recalculated once the transfer is resumed.
+func (p *progressIndicator) updateRate(ev bluetooth.ObjectPushEventData) {
+	if ev.Status == bluetooth.TransferSuspended {
+		p.samples = p.samples[:0]
+		p.rateText = "Paused"
+
+		return
+	}
+
+	now := time.Now()
+	if len(p.samples) > 0 && ev.Transferred < p.samples[len(p.samples)-1].transferred {
+		p.samples = p.samples[:0]
+	}
+
+	p.samples = append(p.samples, transferSample{at: now, transferred: ev.Transferred})
+
+	start := 0
+	for start < len(p.samples)-1 && (now.Sub(p.samples[start].at) > transferRateWindow || len(p.samples)-start > transferRateMaxSamples) {
+		start++
+	}
+	p.samples = append(p.samples[:0], p.samples[start:]...)
+
+	first, last := p.samples[0], p.samples[len(p.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	speed := float64(last.transferred-first.transferred) / elapsed
+	if speed <= 0 {
+		p.rateText = "Stalled"
+		return
+	}
+
+	p.rateText = formatSize(int64(speed)) + "/s"
+	if p.size > ev.Transferred {
+		eta := time.Duration(float64(p.size-ev.Transferred)/speed) * time.Second
+		p.rateText += ", " + formatDuration(uint32(eta.Milliseconds())) + " left"
+	}
+}
+
+// setStatus sets the status of the transfer, and highlights the progress bar if the transfer is suspended.
+func (p *progressIndicator) setStatus(status bluetooth.ObjectPushStatus) {
+	p.appDrawFunc(func() {
+		if p.status == status {
+			return
+		}
+		p.status = status
+
+		color := theme.ThemeProgressBar
+		if status == bluetooth.TransferSuspended {
+			color = theme.ThemeProgressPaused
+		}
+
+		p.progress.SetTextColor(theme.GetColor(color))
+		p.progress.SetText(p.text(p.barText))
+	})
+}
+
 // Write is used by the progressbar to display the progress on the screen.
 func (p *progressIndicator) Write(b []byte) (int, error) {
+	bar := string(b)
+	text := p.text(bar)
+
 	p.appDrawFunc(func() {
-		p.progress.SetText(string(b))
+		p.barText = bar
+		p.progress.SetText(text)
 	})
 
 	return 0, nil
 }
 
-// savefile moves a file from the obex cache to a specified user-accessible directory.
-// If the directory is not specified, it automatically creates a directory in the
-// user's home path and moves the file there.
-func savefile(path string, userpath string) error {
-	if userpath == "" {
-		homedir, err := os.UserHomeDir()
-		if err != nil {
-			return err
+// text returns the text of the progress bar, prefixed with the transfer speed or status.
+func (p *progressIndicator) text(bar string) string {
+	if p.rateText == "" {
+		return bar
+	}
+
+	return p.rateText + " " + bar
+}
+
+// notifyComplete sends a desktop notification for a completed transfer.
+func (p *progressView) notifyComplete(transferProps bluetooth.ObjectPushData) {
+	name := p.deviceName(transferProps.DeviceAddress)
+
+	filename := transferProps.Name
+	if filename == "" {
+		filename = filepath.Base(transferProps.Filename)
+	}
+
+	body := fmt.Sprintf("Sent '%s' to %s", filename, name)
+	if transferProps.Receiving {
+		body = fmt.Sprintf("Received '%s' from %s", filename, name)
+	}
+
+	p.notifier.notify("Transfer complete", body)
+}
+
+// deviceName returns the display name of the device, or its address if the device cannot be found.
+func (p *progressView) deviceName(address bluetooth.DeviceAddress) string {
+	props, err := p.app.Session().Device(address).Properties()
+	if err != nil {
+		return address.Address.String()
+	}
+
+	return btdevice.DisplayName(props.DeviceEventData)
+}
+
+// deviceDirName returns a directory name for the device, which is used to store
+// files received from it. Any path separators in the device's name are replaced.
+func (p *progressView) deviceDirName(address bluetooth.DeviceAddress) string {
+	fallback := strings.ReplaceAll(address.Address.String(), ":", "-")
+
+	props, err := p.app.Session().Device(address).Properties()
+	if err != nil {
+		return fallback
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator || r == 0 {
+			return '_'
 		}
 
-		userpath = filepath.Join(homedir, "bluetuith")
+		return r
+	}, btdevice.DisplayName(props.DeviceEventData))
 
-		if _, err := os.Stat(userpath); err != nil {
-			err = os.Mkdir(userpath, 0o700)
-			if err != nil {
-				return err
-			}
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return fallback
+	}
+
+	return name
+}
+
+// verifyReceived computes and displays the SHA-256 checksum of a received file, and
+// warns if the size of the file differs from the size announced by the sender.
+func (p *progressView) verifyReceived(path string, size uint64) {
+	file, err := os.Open(path)
+	if err != nil {
+		p.status.ErrorMessage(fmt.Errorf("cannot verify %s: %w", filepath.Base(path), err))
+		return
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	written, err := io.Copy(hash, file)
+	if err != nil {
+		p.status.ErrorMessage(fmt.Errorf("cannot verify %s: %w", filepath.Base(path), err))
+		return
+	}
+
+	name := filepath.Base(path)
+	if size > 0 && uint64(written) != size {
+		p.status.ErrorMessage(fmt.Errorf(
+			"%s was received with a size of %s, but %s was expected",
+			name, formatSize(written), formatSize(int64(size)),
+		))
+	}
+
+	p.status.InfoMessage("SHA-256 of "+name+": "+hex.EncodeToString(hash.Sum(nil)), true)
+}
+
+// savefile moves a file from the obex cache to a specified user-accessible directory,
+// and returns the new path of the file. If the directory is not specified, the file is moved
+// to the default directory for received files, as returned by receiveDir. If a subdirectory
+// is specified, the file is moved into the subdirectory instead, which is created if it does not exist.
+func savefile(path string, userpath string, subdir string) (string, error) {
+	userpath, err := receiveDir(userpath)
+	if err != nil {
+		return "", err
+	}
+
+	if subdir != "" {
+		userpath = filepath.Join(userpath, subdir)
+		if err := os.MkdirAll(userpath, 0o700); err != nil {
+			return "", err
 		}
 	}
 
-	return os.Rename(path, filepath.Join(userpath, filepath.Base(path)))
+	savedPath := filepath.Join(userpath, filepath.Base(path))
+
+	return savedPath, os.Rename(path, savedPath)
+}
+
+// receiveDir returns the directory to store received files in.
+// If the directory is not specified, it automatically creates a "received" directory
+// in the state directory and returns it. If a "bluetuith" directory exists in the user's
+// home path, which was used by older versions, it is returned instead.
+func receiveDir(userpath string) (string, error) {
+	if userpath != "" {
+		return userpath, nil
+	}
+
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	if info, err := os.Stat(filepath.Join(homedir, "bluetuith")); err == nil && info.IsDir() {
+		return filepath.Join(homedir, "bluetuith"), nil
+	}
+
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	userpath = filepath.Join(stateDir, "received")
+
+	if err := os.Mkdir(userpath, 0o700); err != nil && !errors.Is(err, fs.ErrExist) {
+		return "", err
+	}
+
+	return userpath, nil
 }
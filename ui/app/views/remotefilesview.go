@@ -0,0 +1,404 @@
+package views
+
+import (
+	"cmp"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/darkhz/bluetuith/ui/keybindings"
+	"github.com/darkhz/bluetuith/ui/theme"
+)
+
+const remoteFilesPage viewName = "remotefiles"
+
+const remoteFilesButtonRegion = `["download"][::b][Download[][""] ["upload"][::b][Upload[][""] ["refresh"][::b][Refresh[][""] ["close"][::b][Close[][""]`
+
+// remoteFilesView holds the remote file browser view.
+type remoteFilesView struct {
+	isSupported bool
+
+	table          *tview.Table
+	title, buttons *tview.TextView
+	flex           *tview.Flex
+
+	address bluetooth.DeviceAddress
+	session *obexFileTransfer
+	folders []string
+
+	mu sync.Mutex
+
+	*Views
+}
+
+// Initialize initializes the remote file browser.
+func (r *remoteFilesView) Initialize() error {
+	infoTitle := tview.NewTextView()
+	infoTitle.SetDynamicColors(true)
+	infoTitle.SetTextAlign(tview.AlignCenter)
+	infoTitle.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	infoTitle.SetText(theme.ColorWrap(theme.ThemeText, "Browse remote files", "::bu"))
+
+	r.title = tview.NewTextView()
+	r.title.SetDynamicColors(true)
+	r.title.SetTextAlign(tview.AlignLeft)
+	r.title.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	r.table = tview.NewTable()
+	r.table.SetSelectorWrap(true)
+	r.table.SetSelectable(true, false)
+	r.table.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	r.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch r.kb.Key(event, keybindings.ContextFiles) {
+		case keybindings.KeyFilebrowserDirForward, keybindings.KeySelect:
+			go r.changeFolder(false)
+
+		case keybindings.KeyFilebrowserDirBack:
+			go r.changeFolder(true)
+
+		case keybindings.KeyFilebrowserRefresh:
+			go r.list()
+
+		case keybindings.KeyFilebrowserDownload:
+			go r.download()
+
+		case keybindings.KeyFilebrowserUpload:
+			go r.upload()
+
+		case keybindings.KeyClose:
+			go r.close()
+
+		case keybindings.KeyQuit:
+			go r.actions.quit()
+
+		case keybindings.KeyHelp:
+			r.help.showHelp()
+
+		case keybindings.KeyHelpContext:
+			r.help.showContextHelp()
+		}
+
+		return ignoreDefaultEvent(event)
+	})
+	r.table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if r.scrollTable(r.table, action, event) {
+			return action, nil
+		}
+
+		return action, event
+	})
+
+	r.buttons = tview.NewTextView()
+	r.buttons.SetRegions(true)
+	r.buttons.SetDynamicColors(true)
+	r.buttons.SetTextAlign(tview.AlignLeft)
+	r.buttons.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	r.buttons.SetText(theme.ColorWrap(theme.ThemeText, remoteFilesButtonRegion))
+	r.buttons.SetHighlightedFunc(func(added, _, _ []string) {
+		if added == nil {
+			return
+		}
+
+		if containsRegionID(r.buttons, added[0]) {
+			switch added[0] {
+			case "download":
+				go r.download()
+
+			case "upload":
+				go r.upload()
+
+			case "refresh":
+				go r.list()
+
+			case "close":
+				go r.close()
+			}
+
+			r.buttons.Highlight("")
+		}
+	})
+
+	r.flex = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(infoTitle, 1, 0, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(r.title, 1, 0, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(r.table, 0, 10, true).
+		AddItem(nil, 1, 0, false).
+		AddItem(r.buttons, 2, 0, false)
+	r.flex.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	r.isSupported = true
+
+	return nil
+}
+
+// SetRootView sets the root view for the remote file browser.
+func (r *remoteFilesView) SetRootView(v *Views) {
+	r.Views = v
+}
+
+// show displays the remote file browser for the provided device,
+// starting from the root folder of the device's filesystem.
+func (r *remoteFilesView) show(address bluetooth.DeviceAddress, session *obexFileTransfer) {
+	if !r.isSupported {
+		r.status.ErrorMessage(errors.New("browsing remote files is not supported"))
+		return
+	}
+
+	r.mu.Lock()
+	r.address = address
+	r.session = session
+	r.folders = nil
+	r.mu.Unlock()
+
+	r.app.QueueDraw(func() {
+		r.pages.AddAndSwitchToPage(remoteFilesPage.String(), r.flex, true)
+	})
+
+	r.list()
+}
+
+// list lists the contents of the current remote folder.
+func (r *remoteFilesView) list() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.session == nil {
+		return
+	}
+
+	entries, err := r.session.listFolder()
+	if err != nil {
+		r.status.ErrorMessage(err)
+		return
+	}
+
+	slices.SortFunc(entries, func(i, j fs.FileInfo) int {
+		if i.IsDir() != j.IsDir() {
+			if i.IsDir() {
+				return -1
+			}
+
+			return 1
+		}
+
+		return cmp.Compare(i.Name(), j.Name())
+	})
+
+	folder := "/" + strings.Join(r.folders, "/")
+	isRoot := len(r.folders) == 0
+
+	r.app.QueueDraw(func() {
+		r.table.Clear()
+
+		row := 0
+		if !isRoot {
+			r.table.SetCell(row, 0, tview.NewTableCell("../").
+				SetExpansion(1).
+				SetAttributes(tcell.AttrBold).
+				SetTextColor(tcell.ColorBlue).
+				SetSelectedStyle(tcell.Style{}.Bold(true).Reverse(true)),
+			)
+
+			row++
+		}
+
+		for _, entry := range entries {
+			var attr tcell.AttrMask
+
+			name := entry.Name()
+			entryColor := theme.GetColor(theme.ThemeText)
+			size := formatSize(entry.Size())
+
+			if entry.IsDir() {
+				attr = tcell.AttrBold
+				entryColor = tcell.ColorBlue
+				name += "/"
+				size = ""
+			}
+
+			r.table.SetCell(row, 0, tview.NewTableCell(tview.Escape(name)).
+				SetExpansion(1).
+				SetReference(entry).
+				SetAttributes(attr).
+				SetTextColor(entryColor).
+				SetSelectedStyle(tcell.Style{}.Bold(true).Reverse(true)),
+			)
+
+			for col, text := range []string{
+				size,
+				entry.ModTime().Format("02 Jan 2006 03:04 PM"),
+			} {
+				r.table.SetCell(row, col+1, tview.NewTableCell(text).
+					SetAlign(tview.AlignRight).
+					SetTextColor(tcell.ColorGrey).
+					SetSelectedStyle(tcell.Style{}.Bold(true).Reverse(true)),
+				)
+			}
+
+			row++
+		}
+
+		r.title.SetText(theme.ColorWrap(theme.ThemeText, "Folder: "+folder))
+
+		r.table.ScrollToBeginning()
+		r.table.Select(0, 0)
+	})
+}
+
+// changeFolder enters the selected remote folder, or goes back to the parent folder.
+func (r *remoteFilesView) changeFolder(back bool) {
+	entry, isParent := r.selection()
+	if !back && !isParent && (entry == nil || !entry.IsDir()) {
+		return
+	}
+
+	r.mu.Lock()
+	if r.session == nil {
+		r.mu.Unlock()
+		return
+	}
+
+	if back || isParent {
+		if len(r.folders) == 0 {
+			r.mu.Unlock()
+			return
+		}
+
+		if err := r.session.changeFolder(".."); err != nil {
+			r.mu.Unlock()
+			r.status.ErrorMessage(err)
+			return
+		}
+
+		r.folders = r.folders[:len(r.folders)-1]
+	} else {
+		if err := r.session.changeFolder(entry.Name()); err != nil {
+			r.mu.Unlock()
+			r.status.ErrorMessage(err)
+			return
+		}
+
+		r.folders = append(r.folders, entry.Name())
+	}
+	r.mu.Unlock()
+
+	r.list()
+}
+
+// download downloads the selected remote file to the download directory.
+func (r *remoteFilesView) download() {
+	entry, _ := r.selection()
+	if entry == nil || entry.IsDir() {
+		return
+	}
+
+	dir, err := receiveDir(r.cfg.Values.ReceiveDir)
+	if err != nil {
+		r.status.ErrorMessage(err)
+		return
+	}
+
+	r.mu.Lock()
+	if r.session == nil {
+		r.mu.Unlock()
+		return
+	}
+
+	props, err := r.session.getFile(filepath.Join(dir, entry.Name()), entry.Name())
+	r.mu.Unlock()
+	if err != nil || props.Status == bluetooth.TransferError {
+		r.status.ErrorMessage(err)
+		return
+	}
+
+	r.progress.startTransfer(r.address, []bluetooth.ObjectPushData{props})
+}
+
+// upload shows the file picker, and uploads the selected files to the current remote folder.
+func (r *remoteFilesView) upload() {
+	fileList, err := r.filepicker.Show(r.progress.deviceName(r.address))
+	r.app.QueueDraw(func() {
+		r.pages.AddAndSwitchToPage(remoteFilesPage.String(), r.flex, true)
+	})
+	if err != nil {
+		r.status.ErrorMessage(err)
+		return
+	}
+	if len(fileList) == 0 {
+		return
+	}
+
+	proplist := make([]bluetooth.ObjectPushData, 0, len(fileList))
+
+	r.mu.Lock()
+	if r.session == nil {
+		r.mu.Unlock()
+		return
+	}
+
+	for _, file := range fileList {
+		props, err := r.session.putFile(file, filepath.Base(file))
+		if err != nil || props.Status == bluetooth.TransferError {
+			r.status.ErrorMessage(err)
+			continue
+		}
+
+		proplist = append(proplist, props)
+	}
+	r.mu.Unlock()
+
+	if len(proplist) > 0 {
+		r.progress.startTransfer(r.address, proplist)
+	}
+
+	r.list()
+}
+
+// close closes the remote file browser and removes the file transfer session.
+func (r *remoteFilesView) close() {
+	r.mu.Lock()
+	if r.session != nil {
+		if err := r.session.remove(); err != nil {
+			r.status.ErrorMessage(err)
+		}
+
+		r.session = nil
+	}
+	r.mu.Unlock()
+
+	r.app.QueueDraw(func() {
+		r.pages.RemovePage(remoteFilesPage.String())
+		r.pages.SwitchToPage(devicePage.String())
+	})
+}
+
+// selection returns the currently selected remote file, and whether the
+// selected entry refers to the parent folder.
+func (r *remoteFilesView) selection() (fs.FileInfo, bool) {
+	var entry fs.FileInfo
+	var isParent bool
+
+	row, _ := r.table.GetSelection()
+	cell := r.table.GetCell(row, 0)
+	if cell == nil {
+		return nil, false
+	}
+
+	if ref, ok := cell.GetReference().(fs.FileInfo); ok {
+		entry = ref
+	} else {
+		isParent = cell.Text == "../"
+	}
+
+	return entry, isParent
+}
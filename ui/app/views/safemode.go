@@ -0,0 +1,164 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
+)
+
+// safeMode holds an instance of the safe mode, which automatically blocks unknown devices
+// that attempt to pair, if the "safe-mode" option is set. A device is known if it is present in
+// the pairing allowlist, if it was already paired when it was first seen, or if the user paired
+// or connected with it from the application. Each blocked device is recorded in the message log.
+type safeMode struct {
+	v *Views
+
+	known map[bluetooth.MacAddress]struct{}
+	mu    sync.Mutex
+}
+
+// newSafeMode returns a new safe mode instance.
+func newSafeMode(v *Views) *safeMode {
+	return &safeMode{
+		v:     v,
+		known: make(map[bluetooth.MacAddress]struct{}),
+	}
+}
+
+// enabled returns whether the safe mode is enabled.
+func (s *safeMode) enabled() bool {
+	return s.v.cfg.Values.SafeMode
+}
+
+// allow marks the device as known, so that it is not blocked.
+// This is used when the user pairs or connects with the device.
+func (s *safeMode) allow(address bluetooth.MacAddress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.known[address] = struct{}{}
+}
+
+// isKnown returns whether the device is known.
+func (s *safeMode) isKnown(address bluetooth.MacAddress) bool {
+	if slices.Contains(s.v.cfg.Values.PairingAllowlist, address.String()) {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.known[address]
+
+	return ok
+}
+
+// check blocks the device if the safe mode is enabled, and the device is unknown and not paired.
+// It returns whether the device was blocked, in which case the request must be rejected.
+func (s *safeMode) check(address bluetooth.DeviceAddress, request string) bool {
+	if !s.enabled() || s.isKnown(address.Address) {
+		return false
+	}
+
+	device, err := s.v.app.Session().Device(address).Properties()
+	if err == nil {
+		if paired, _ := device.Paired.Get(); paired {
+			return false
+		}
+	}
+
+	s.block(address, request)
+
+	return true
+}
+
+// block blocks the device, and records it in the message log.
+func (s *safeMode) block(address bluetooth.DeviceAddress, request string) {
+	name := address.Address.String()
+	if device, err := s.v.app.Session().Device(address).Properties(); err == nil {
+		if blocked, _ := device.Blocked.Get(); blocked {
+			return
+		}
+
+		name = btdevice.DisplayName(device.DeviceEventData) + " (" + name + ")"
+	}
+
+	if err := s.v.app.Session().Device(address).SetBlocked(true); err != nil {
+		s.v.status.ErrorMessage(fmt.Errorf("safe mode: cannot block %s: %w", name, err))
+		return
+	}
+
+	s.v.status.InfoMessage(fmt.Sprintf("Safe mode: blocked the unknown device %s after a %s request", name, request), false)
+}
+
+// event handles device events to block unknown devices which are paired without
+// an authorization request, for example devices which pair without user interaction.
+// The devices which are already paired when the events are first handled are known.
+func (s *safeMode) event() {
+	deviceSub, ok := bluetooth.DeviceEvents().Subscribe()
+	if !ok {
+		s.v.status.ErrorMessage(errors.New("cannot subscribe to device events"))
+		return
+	}
+
+	s.addPairedDevices()
+
+	for {
+		select {
+		case <-deviceSub.Done:
+			return
+
+		case ev := <-deviceSub.AddedEvents:
+			if paired, ok := ev.Paired.Get(); ok && paired {
+				s.checkPaired(ev.DeviceAddress)
+			}
+
+		case ev := <-deviceSub.UpdatedEvents:
+			if paired, ok := ev.Paired.Get(); ok && paired {
+				s.checkPaired(ev.DeviceAddress)
+			}
+		}
+	}
+}
+
+// checkPaired blocks the paired device if the safe mode is enabled, and the device is unknown.
+// If the safe mode is disabled, the device is marked as known, so that enabling the safe mode
+// later does not block it.
+func (s *safeMode) checkPaired(address bluetooth.DeviceAddress) {
+	if s.isKnown(address.Address) {
+		return
+	}
+
+	if !s.enabled() {
+		s.allow(address.Address)
+		return
+	}
+
+	s.block(address, "pairing")
+}
+
+// addPairedDevices marks all the currently paired devices as known.
+func (s *safeMode) addPairedDevices() {
+	adapters, err := s.v.app.Session().Adapters()
+	if err != nil {
+		return
+	}
+
+	for _, adapter := range adapters {
+		devices, err := s.v.app.Session().Adapter(adapter.AdapterAddress).Devices()
+		if err != nil {
+			continue
+		}
+
+		for _, device := range devices {
+			if paired, _ := device.Paired.Get(); paired {
+				s.allow(device.Address)
+			}
+		}
+	}
+}
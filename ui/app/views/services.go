@@ -0,0 +1,172 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/google/uuid"
+)
+
+// serviceGroup describes a group of related service profiles.
+type serviceGroup struct {
+	name     string
+	services []uuid.UUID
+}
+
+// serviceGroupOther is the name of the group of services which do not belong to any known group.
+const serviceGroupOther = "Other"
+
+// serviceGroupNames lists the names of the service groups, in the order in which they are displayed.
+var serviceGroupNames = []string{"Audio", "HID", "Networking", "OBEX", serviceGroupOther}
+
+// serviceGroups maps the 16-bit identifiers of known service profiles to their groups.
+var serviceGroups = map[uint32]string{
+	0x1108: "Audio", // Headset
+	0x110a: "Audio", // Audio Source
+	0x110b: "Audio", // Audio Sink
+	0x110c: "Audio", // A/V Remote Control Target
+	0x110d: "Audio", // Advanced Audio Distribution
+	0x110e: "Audio", // A/V Remote Control
+	0x110f: "Audio", // A/V Remote Control Controller
+	0x1112: "Audio", // Headset AG
+	0x111e: "Audio", // Handsfree
+	0x111f: "Audio", // Handsfree Audio Gateway
+	0x1131: "Audio", // Headset HS
+	0x1203: "Audio", // Generic Audio
+	0x1843: "Audio", // Audio Input Control
+	0x1844: "Audio", // Volume Control
+	0x1845: "Audio", // Volume Offset Control
+	0x1846: "Audio", // Coordinated Set Identification
+	0x1848: "Audio", // Media Control
+	0x1849: "Audio", // Generic Media Control
+	0x184b: "Audio", // Telephony Bearer
+	0x184c: "Audio", // Generic Telephony Bearer
+	0x184d: "Audio", // Microphone Control
+	0x184e: "Audio", // Audio Stream Control
+	0x184f: "Audio", // Broadcast Audio Scan
+	0x1850: "Audio", // Published Audio Capabilities
+	0x1851: "Audio", // Basic Audio Announcement
+	0x1852: "Audio", // Broadcast Audio Announcement
+	0x1853: "Audio", // Common Audio
+	0x1854: "Audio", // Hearing Access
+	0x1855: "Audio", // Telephony and Media Audio
+	0x1856: "Audio", // Public Broadcast Announcement
+	0x1858: "Audio", // Gaming Audio
+
+	0x1124: "HID", // Human Interface Device Service
+	0x1812: "HID", // Human Interface Device
+	0x1813: "HID", // Scan Parameters
+
+	0x1102: "Networking", // LAN Access Using PPP
+	0x1103: "Networking", // Dialup Networking
+	0x1115: "Networking", // PANU
+	0x1116: "Networking", // NAP
+	0x1117: "Networking", // GN
+	0x1201: "Networking", // Generic Networking
+	0x1820: "Networking", // Internet Protocol Support
+	0x1823: "Networking", // HTTP Proxy
+
+	0x1104: "OBEX", // IrMC Sync
+	0x1105: "OBEX", // OBEX Object Push
+	0x1106: "OBEX", // OBEX File Transfer
+	0x1107: "OBEX", // IrMC Sync Command
+	0x111a: "OBEX", // Basic Imaging Profile
+	0x111b: "OBEX", // Imaging Responder
+	0x111c: "OBEX", // Imaging Automatic Archive
+	0x111d: "OBEX", // Imaging Referenced Objects
+	0x112e: "OBEX", // Phonebook Access Client
+	0x112f: "OBEX", // Phonebook Access Server
+	0x1130: "OBEX", // Phonebook Access
+	0x1132: "OBEX", // Message Access Server
+	0x1133: "OBEX", // Message Notification Server
+	0x1134: "OBEX", // Message Access Profile
+	0x1202: "OBEX", // Generic File Transfer
+}
+
+// extraServices holds the names of assigned 16-bit service identifiers
+// which are not present in the service table of the Bluetooth library.
+var extraServices = map[uint32]string{
+	0x112a: "UDI MT",
+	0x112b: "UDI TA",
+	0x112c: "Audio/Video",
+	0x1829: "Reconnection Configuration",
+	0x183a: "Insulin Delivery",
+	0x183b: "Binary Sensor",
+	0x183c: "Emergency Configuration",
+	0x183d: "Authorization Control",
+	0x183e: "Physical Activity Monitor",
+	0x183f: "Elapsed Time",
+	0x1840: "Generic Health Sensor",
+	0x1847: "Device Time",
+	0x184a: "Constant Tone Extension",
+	0x1857: "Electronic Shelf Label",
+	0x1859: "Mesh Proxy Solicitation",
+	0x185a: "Industrial Measurement Device",
+	0x185b: "Ranging",
+	0x185c: "HID ISO",
+}
+
+// vendorServices holds the names of well-known vendor-specific service UUIDs.
+var vendorServices = map[string]string{
+	"00000000-deca-fade-deca-deafdecacafe": "Apple iAP2",
+	"7905f431-b5ce-4e99-a40f-4b1e122d00d0": "Apple Notification Center",
+	"89d3502b-0f36-433a-8ef4-c502ad55f8dc": "Apple Media",
+	"d0611e78-bbb4-4591-a5f8-487910ae4366": "Apple Continuity",
+	"6e400001-b5a3-f393-e0a9-e50e24dcca9e": "Nordic UART",
+}
+
+// serviceName returns a human-readable name of the service UUID.
+// If the service is not known, its 16-bit identifier is included in the name.
+func serviceName(serviceUUID uuid.UUID) string {
+	if bluetooth.IsVendorSpecificUUID(serviceUUID) {
+		if name, ok := vendorServices[serviceUUID.String()]; ok {
+			return name
+		}
+
+		return "Vendor specific"
+	}
+
+	if name, ok := extraServices[serviceUUID.ID()]; ok {
+		return name
+	}
+
+	if _, ok := bluetooth.Services[serviceUUID.ID()]; !ok {
+		return fmt.Sprintf("Unknown (0x%04x)", serviceUUID.ID())
+	}
+
+	return bluetooth.ServiceType(serviceUUID)
+}
+
+// groupServices groups the service UUIDs by their profiles.
+// Only groups with at least one service are returned.
+func groupServices(serviceUUIDs uuid.UUIDs) []serviceGroup {
+	grouped := make(map[string][]uuid.UUID, len(serviceGroupNames))
+
+	for _, serviceUUID := range serviceUUIDs {
+		group := serviceGroupOther
+		if !bluetooth.IsVendorSpecificUUID(serviceUUID) {
+			if name, ok := serviceGroups[serviceUUID.ID()]; ok {
+				group = name
+			}
+		}
+
+		grouped[group] = append(grouped[group], serviceUUID)
+	}
+
+	groups := make([]serviceGroup, 0, len(grouped))
+	for _, name := range serviceGroupNames {
+		services, ok := grouped[name]
+		if !ok {
+			continue
+		}
+
+		slices.SortFunc(services, func(a, b uuid.UUID) int {
+			return slices.Compare(a[:], b[:])
+		})
+
+		groups = append(groups, serviceGroup{name, services})
+	}
+
+	return groups
+}
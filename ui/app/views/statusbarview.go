@@ -16,6 +16,12 @@ const (
 	statusMessagesPage viewName = "messages"
 )
 
+// activityFrames holds the frames of the activity indicator.
+var activityFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// activityInterval is the time after which the next frame of the activity indicator is drawn.
+const activityInterval = 200 * time.Millisecond
+
 type statusBarView struct {
 	// MessageBox is an area to display messages.
 	MessageBox *tview.TextView
@@ -26,10 +32,17 @@ type statusBarView struct {
 	// InputField is an area to interact with messages.
 	InputField *tview.InputField
 
+	// Activity is an area to display an indicator while an operation is in progress.
+	Activity *tview.TextView
+
+	row *tview.Flex
+
 	sctx    context.Context
 	scancel context.CancelFunc
 	msgchan chan message
 
+	log *messageLog
+
 	*Views
 
 	*tview.Pages
@@ -58,18 +71,28 @@ func (s *statusBarView) Initialize() error {
 	s.Help.SetDynamicColors(true)
 	s.Help.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
+	s.Activity = tview.NewTextView()
+	s.Activity.SetTextColor(theme.GetColor(theme.ThemeStatusInfo))
+	s.Activity.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
 	s.AddPage(statusInputPage.String(), s.InputField, true, true)
 	s.AddPage(statusMessagesPage.String(), s.MessageBox, true, true)
 	s.SwitchToPage(statusMessagesPage.String())
 
 	s.msgchan = make(chan message, 10)
+	s.log = newMessageLog()
 	s.sctx, s.scancel = context.WithCancel(context.Background())
 
 	go s.startStatus()
 
+	s.row = tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(s.Activity, 0, 0, false).
+		AddItem(s.Pages, 0, 1, false)
+
 	flex := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(s.Pages, 1, 0, false)
+		AddItem(s.row, 1, 0, false)
 
 	s.layout.AddItem(flex, flex.GetItemCount(), 0, false)
 
@@ -89,8 +112,27 @@ func (s *statusBarView) SetInput(label string, multichar ...struct{}) string {
 	return s.waitForInput(context.Background(), label, multichar...)
 }
 
+// SetInputText sets the inputfield label and text, and returns the edited text.
+// If the input is cancelled, false is returned.
+func (s *statusBarView) SetInputText(label, text string) (string, bool) {
+	return s.readInput(context.Background(), label, text, true)
+}
+
 func (s *statusBarView) waitForInput(ctx context.Context, label string, multichar ...struct{}) string {
-	input := make(chan string)
+	reply, _ := s.readInput(ctx, label, "", multichar != nil)
+
+	return reply
+}
+
+// readInput shows the inputfield with the provided label and text, and waits for the input.
+// If multichar is not set, a single character is read.
+func (s *statusBarView) readInput(ctx context.Context, label, text string, multichar bool) (string, bool) {
+	type inputReply struct {
+		text string
+		ok   bool
+	}
+
+	input := make(chan inputReply)
 
 	go func() {
 		exited := make(chan struct{}, 1)
@@ -104,20 +146,20 @@ func (s *statusBarView) waitForInput(ctx context.Context, label string, multicha
 		}
 
 		s.app.QueueDraw(func() {
-			s.InputField.SetText("")
+			s.InputField.SetText(text)
 			s.InputField.SetLabel("[::b]" + label + " ")
 
-			if multichar != nil {
+			if multichar {
 				s.InputField.SetAcceptanceFunc(nil)
 				s.InputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 					switch s.kb.Key(event) {
 					case keybindings.KeySelect:
-						input <- s.InputField.GetText()
+						input <- inputReply{s.InputField.GetText(), true}
 
 						exit()
 
 					case keybindings.KeyClose:
-						input <- ""
+						input <- inputReply{}
 
 						exit()
 					}
@@ -127,7 +169,7 @@ func (s *statusBarView) waitForInput(ctx context.Context, label string, multicha
 			} else {
 				s.InputField.SetAcceptanceFunc(tview.InputFieldMaxLength(1))
 				s.InputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-					input <- string(event.Rune())
+					input <- inputReply{string(event.Rune()), true}
 					exit()
 
 					return event
@@ -149,7 +191,9 @@ func (s *statusBarView) waitForInput(ctx context.Context, label string, multicha
 		}
 	}()
 
-	return <-input
+	reply := <-input
+
+	return reply.text, reply.ok
 }
 
 // InfoMessage sends an info message to the status bar.
@@ -158,6 +202,17 @@ func (s *statusBarView) InfoMessage(text string, persist bool) {
 		return
 	}
 
+	s.log.add(text, false)
+	s.logger.log("info", text)
+	s.transientMessage(text, persist)
+}
+
+// transientMessage sends an info message to the status bar, without recording it in the message log.
+func (s *statusBarView) transientMessage(text string, persist bool) {
+	if s.msgchan == nil {
+		return
+	}
+
 	select {
 	case s.msgchan <- message{theme.ColorWrap(theme.ThemeStatusInfo, text), persist}:
 		return
@@ -176,6 +231,9 @@ func (s *statusBarView) ErrorMessage(err error) {
 		return
 	}
 
+	s.log.add(err.Error(), true)
+	s.logger.log("error", err.Error())
+
 	select {
 	case s.msgchan <- message{theme.ColorWrap(theme.ThemeStatusError, "Error: "+err.Error()), false}:
 		return
@@ -184,6 +242,36 @@ func (s *statusBarView) ErrorMessage(err error) {
 	}
 }
 
+// showActivity shows an animated activity indicator before the messages, until the context is cancelled.
+func (s *statusBarView) showActivity(ctx context.Context) {
+	t := time.NewTicker(activityInterval)
+	defer t.Stop()
+
+	s.app.QueueDraw(func() {
+		s.Activity.SetText(string(activityFrames[0]))
+		s.row.ResizeItem(s.Activity, 2, 0)
+	})
+
+	for frame := 1; ; frame++ {
+		select {
+		case <-ctx.Done():
+			s.app.QueueDraw(func() {
+				s.Activity.SetText("")
+				s.row.ResizeItem(s.Activity, 0, 0)
+			})
+
+			return
+
+		case <-t.C:
+			text := string(activityFrames[frame%len(activityFrames)])
+
+			s.app.QueueDraw(func() {
+				s.Activity.SetText(text)
+			})
+		}
+	}
+}
+
 // startStatus starts the message event loop
 func (s *statusBarView) startStatus() {
 	var text string
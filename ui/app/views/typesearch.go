@@ -0,0 +1,138 @@
+package views
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// typeSearchTimeout is the idle duration after which a type-to-jump search is reset.
+const typeSearchTimeout = 1500 * time.Millisecond
+
+// typeSearch holds the state of a type-to-jump search within a table.
+// The search is started by a dedicated key, and is reset after a brief idle period,
+// so that it does not conflict with the single-key actions of the table.
+type typeSearch struct {
+	query     string
+	active    bool
+	lastInput time.Time
+
+	mu sync.Mutex
+}
+
+// start starts a new search.
+func (t *typeSearch) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.query = ""
+	t.active = true
+	t.lastInput = time.Now()
+}
+
+// stop stops the search.
+func (t *typeSearch) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.query = ""
+	t.active = false
+}
+
+// isActive returns whether a search is active, and is waiting for input.
+func (t *typeSearch) isActive() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.active && time.Since(t.lastInput) <= typeSearchTimeout
+}
+
+// input handles a keyboard event for an active search, and returns the updated search query.
+// If the event was not handled by the search, for example if no search is active, 'handled' is false.
+func (t *typeSearch) input(event *tcell.EventKey) (query string, handled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.active {
+		return "", false
+	}
+
+	if time.Since(t.lastInput) > typeSearchTimeout {
+		t.query = ""
+		t.active = false
+
+		return "", false
+	}
+
+	switch event.Key() {
+	case tcell.KeyRune:
+		if event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) != 0 {
+			break
+		}
+
+		t.query += string(event.Rune())
+		t.lastInput = time.Now()
+
+		return t.query, true
+
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if t.query != "" {
+			_, size := utf8.DecodeLastRuneInString(t.query)
+			t.query = t.query[:len(t.query)-size]
+		}
+		t.lastInput = time.Now()
+
+		return t.query, true
+
+	case tcell.KeyEscape:
+		t.query = ""
+		t.active = false
+
+		return "", true
+	}
+
+	t.query = ""
+	t.active = false
+
+	return "", false
+}
+
+// jump selects the first row of the table whose name starts with the search query.
+// The name of each row is returned by nameFunc, and rows for which nameFunc returns false
+// are skipped. It returns whether a matching row was found.
+func (t *typeSearch) jump(table *tview.Table, query string, nameFunc func(row int) (string, bool)) bool {
+	if query == "" {
+		return false
+	}
+
+	query = strings.ToLower(query)
+
+	for row := range table.GetRowCount() {
+		name, ok := nameFunc(row)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(name), query) {
+			table.Select(row, 0)
+			return true
+		}
+	}
+
+	return false
+}
+
+// showFindQuery displays the search query in the status bar.
+// The query is not recorded in the message log, since it is updated on every keypress.
+func showFindQuery(status *statusBarView, query string, found bool) {
+	message := "Find: " + tview.Escape(query)
+	if query != "" && !found {
+		message += " (no match)"
+	}
+
+	status.transientMessage(message, false)
+}
@@ -0,0 +1,147 @@
+package views
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+const bluezDeviceIface = "org.bluez.Device1"
+
+// addressVendors lists the manufacturers of some commonly seen device addresses, by their OUI.
+// A complete list can be loaded using the "vendor-file" option.
+var addressVendors = map[string]string{
+	"00025B": "Cambridge Silicon Radio",
+	"000393": "Apple",
+	"000A95": "Apple",
+	"001018": "Broadcom",
+	"001317": "GN Netcom",
+	"00191D": "Nintendo",
+	"001A7D": "cyber-blue(HK)",
+	"001B66": "Sennheiser",
+	"001F20": "Logitech",
+	"001F32": "Nintendo",
+	"240AC4": "Espressif",
+	"30AEA4": "Espressif",
+	"A4CF12": "Espressif",
+	"B827EB": "Raspberry Pi Foundation",
+	"DCA632": "Raspberry Pi Trading",
+	"E45F01": "Raspberry Pi Trading",
+}
+
+// bluetoothVendors lists the manufacturers of some common devices, by their
+// company identifiers assigned by the Bluetooth SIG.
+var bluetoothVendors = map[uint16]string{
+	0x0000: "Ericsson",
+	0x0001: "Nokia",
+	0x0002: "Intel",
+	0x0003: "IBM",
+	0x0004: "Toshiba",
+	0x0005: "3Com",
+	0x0006: "Microsoft",
+	0x0007: "Lucent",
+	0x0008: "Motorola",
+	0x0009: "Infineon",
+	0x000A: "Qualcomm Technologies International",
+	0x000D: "Texas Instruments",
+	0x000F: "Broadcom",
+	0x001D: "Qualcomm",
+	0x0046: "MediaTek",
+	0x004C: "Apple",
+	0x0059: "Nordic Semiconductor",
+	0x0075: "Samsung",
+	0x0087: "Garmin",
+	0x009E: "Bose",
+	0x00E0: "Google",
+	0x012D: "Sony",
+	0x0171: "Amazon",
+}
+
+// usbVendors lists the manufacturers of some common devices, by their USB vendor identifiers.
+var usbVendors = map[uint16]string{
+	0x03F0: "HP",
+	0x045E: "Microsoft",
+	0x046D: "Logitech",
+	0x04E8: "Samsung",
+	0x054C: "Sony",
+	0x057E: "Nintendo",
+	0x05AC: "Apple",
+	0x0A12: "Cambridge Silicon Radio",
+	0x0A5C: "Broadcom",
+	0x0B05: "ASUS",
+	0x0CF3: "Qualcomm Atheros",
+	0x0E8D: "MediaTek",
+	0x1532: "Razer",
+	0x17EF: "Lenovo",
+	0x18D1: "Google",
+	0x2DC8: "8BitDo",
+	0x413C: "Dell",
+	0x8087: "Intel",
+}
+
+// deviceManufacturer returns the manufacturer of the device. The vendor identifier
+// within the modalias of the device is used if it is known, and the OUI of the
+// device address is used otherwise. Addresses which are loaded from the vendor
+// file take precedence over the built-in addresses.
+func (d *deviceView) deviceManufacturer(address bluetooth.MacAddress, modalias string) (string, bool) {
+	if vendor, ok := modaliasVendor(modalias); ok {
+		return vendor, true
+	}
+
+	oui := strings.ToUpper(strings.ReplaceAll(address.String(), ":", ""))[:6]
+	if vendor, ok := d.cfg.Values.Vendors[oui]; ok {
+		return vendor, true
+	}
+
+	vendor, ok := addressVendors[oui]
+
+	return vendor, ok
+}
+
+// modaliasVendor returns the manufacturer from the vendor identifier of the modalias,
+// for example "bluetooth:v004Cp200Ed0100" or "usb:v05ACp0265d0100".
+func modaliasVendor(modalias string) (string, bool) {
+	source, ids, ok := strings.Cut(modalias, ":v")
+	if !ok || len(ids) < 4 {
+		return "", false
+	}
+
+	id, err := strconv.ParseUint(ids[:4], 16, 16)
+	if err != nil {
+		return "", false
+	}
+
+	var vendor string
+	switch source {
+	case "bluetooth":
+		vendor, ok = bluetoothVendors[uint16(id)]
+
+	case "usb":
+		vendor, ok = usbVendors[uint16(id)]
+
+	default:
+		return "", false
+	}
+
+	return vendor, ok
+}
+
+// getDeviceModalias returns the modalias of the device, as published by BlueZ on the system bus.
+func getDeviceModalias(adapterName string, address bluetooth.MacAddress) (string, error) {
+	conn, err := bluezSystemBus()
+	if err != nil {
+		return "", err
+	}
+
+	devicePath := bluezDevicePath(adapterName, address)
+
+	var modalias string
+	if err := conn.Object(bluezDest, devicePath).
+		Call("org.freedesktop.DBus.Properties.Get", 0, bluezDeviceIface, "Modalias").
+		Store(&modalias); err != nil {
+		return "", err
+	}
+
+	return modalias, nil
+}
@@ -3,10 +3,25 @@ package views
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/appfeatures"
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/bluetuith/ui/btdevice"
 	"github.com/darkhz/bluetuith/ui/keybindings"
+	"go.uber.org/atomic"
+)
+
+const (
+	// adapterResetDelay is the duration for which the adapter is powered off during a reset.
+	adapterResetDelay = 2 * time.Second
+
+	// connectRetryMaxDelay is the maximum delay between connection retries.
+	connectRetryMaxDelay = 30 * time.Second
 )
 
 // viewActions holds an instance of a view actions manager,
@@ -14,6 +29,8 @@ import (
 type viewActions struct {
 	rv *Views
 
+	resetting atomic.Bool
+
 	fnmap map[viewActionContext]map[keybindings.Key]func(set ...string) bool
 }
 
@@ -44,18 +61,41 @@ func (v *viewActions) initViewActions() *viewActions {
 			keybindings.KeyAdapterTogglePairable:     v.pairable,
 			keybindings.KeyAdapterToggleScan:         v.scan,
 			keybindings.KeyAdapterChange:             v.changeAdapter,
+			keybindings.KeyAdapterRename:             v.renameAdapter,
+			keybindings.KeyAdapterReset:              v.resetAdapter,
+			keybindings.KeyAdapterInfo:               v.adapterInfo,
 			keybindings.KeyDeviceConnect:             v.connect,
+			keybindings.KeyDeviceConnectLast:         v.connectLast,
 			keybindings.KeyDevicePair:                v.pair,
 			keybindings.KeyDeviceTrust:               v.trust,
+			keybindings.KeyDeviceTrustConnect:        v.trustConnect,
 			keybindings.KeyDeviceBlock:               v.block,
 			keybindings.KeyDeviceSendFiles:           v.send,
+			keybindings.KeyDeviceBrowseFiles:         v.browse,
 			keybindings.KeyDeviceNetwork:             v.networkAP,
+			keybindings.KeyDeviceNetworkDisconnect:   v.networkDisconnect,
 			keybindings.KeyDeviceAudioProfiles:       v.profiles,
+			keybindings.KeyDeviceAudioHighQuality:    v.audioHighQuality,
+			keybindings.KeyDeviceAudioHeadset:        v.audioHeadset,
+			keybindings.KeyDeviceAudioDefault:        v.audioDefault,
 			keybindings.KeyPlayerShow:                v.showplayer,
 			keybindings.KeyDeviceInfo:                v.info,
+			keybindings.KeyDeviceProfiles:            v.deviceProfiles,
+			keybindings.KeyDeviceCopyAddress:         v.copyAddress,
+			keybindings.KeyDeviceFavorite:            v.favorite,
+			keybindings.KeyDeviceSetDefault:          v.setDefault,
+			keybindings.KeyDeviceRename:              v.rename,
 			keybindings.KeyDeviceRemove:              v.remove,
+			keybindings.KeyDeviceIdentify:            v.identify,
+			keybindings.KeyDeviceBatch:               v.batch,
+			keybindings.KeyDeviceSort:                v.sortDevices,
+			keybindings.KeyDeviceMinRSSI:             v.minRSSI,
 			keybindings.KeyProgressView:              v.progress,
 			keybindings.KeyPlayerHide:                v.hideplayer,
+			keybindings.KeyMessageLog:                v.messageLog,
+			keybindings.KeyConfigReload:              v.reloadConfig,
+			keybindings.KeyThemeReload:               v.reloadTheme,
+			keybindings.KeyRefresh:                   v.refresh,
 			keybindings.KeyQuit:                      v.quit,
 		},
 		actionInitializer: {
@@ -65,18 +105,45 @@ func (v *viewActions) initViewActions() *viewActions {
 			keybindings.KeyDeviceConnect:             v.initConnect,
 			keybindings.KeyDeviceTrust:               v.initTrust,
 			keybindings.KeyDeviceBlock:               v.initBlock,
+			keybindings.KeyDeviceFavorite:            v.initFavorite,
+			keybindings.KeyDeviceSetDefault:          v.initSetDefault,
 		},
 		actionVisibility: {
-			keybindings.KeyDeviceSendFiles:     v.visibleSend,
-			keybindings.KeyDeviceNetwork:       v.visibleNetwork,
-			keybindings.KeyDeviceAudioProfiles: v.visibleProfile,
-			keybindings.KeyPlayerShow:          v.visiblePlayer,
+			keybindings.KeyDeviceSendFiles:         v.visibleSend,
+			keybindings.KeyDeviceBrowseFiles:       v.visibleBrowse,
+			keybindings.KeyDeviceNetwork:           v.visibleNetwork,
+			keybindings.KeyDeviceNetworkDisconnect: v.visibleNetwork,
+			keybindings.KeyDeviceAudioProfiles:     v.visibleProfile,
+			keybindings.KeyDeviceAudioHighQuality:  v.visibleProfile,
+			keybindings.KeyDeviceAudioHeadset:      v.visibleProfile,
+			keybindings.KeyDeviceAudioDefault:      v.visibleProfile,
+			keybindings.KeyPlayerShow:              v.visiblePlayer,
+			keybindings.KeyAdapterRename:           v.visibleBluez,
+			keybindings.KeyDeviceRename:            v.visibleBluez,
+			keybindings.KeyDeviceIdentify:          v.visibleBluez,
 		},
 	}
 
 	return v
 }
 
+// adapterlessActions lists the actions which can be invoked even if no adapter is present.
+var adapterlessActions = []keybindings.Key{
+	keybindings.KeyProgressView,
+	keybindings.KeyPlayerHide,
+	keybindings.KeyMessageLog,
+	keybindings.KeyConfigReload,
+	keybindings.KeyThemeReload,
+	keybindings.KeyRefresh,
+	keybindings.KeyQuit,
+}
+
+// confirm asks for a confirmation before performing an action, if enabled is set.
+// It returns whether the action can be performed.
+func (v *viewActions) confirm(enabled bool, prompt string) bool {
+	return !enabled || v.rv.status.SetInput(prompt+" (y/n)?") == "y"
+}
+
 // handler executes the handler assigned to the key type based on
 // the action context.
 func (v *viewActions) handler(key keybindings.Key, actionContext viewActionContext) func() bool {
@@ -84,6 +151,15 @@ func (v *viewActions) handler(key keybindings.Key, actionContext viewActionConte
 
 	if actionContext == actionInvoke {
 		return func() bool {
+			if !v.rv.adapter.hasAdapter() && !slices.Contains(adapterlessActions, key) {
+				v.rv.status.ErrorMessage(errors.New(noAdapterMessage))
+				return false
+			}
+
+			if data := v.rv.kb.Data(key); data != nil {
+				v.rv.logger.log("action", data.Title)
+			}
+
 			go handler()
 			return false
 		}
@@ -94,6 +170,27 @@ func (v *viewActions) handler(key keybindings.Key, actionContext viewActionConte
 	}
 }
 
+// available returns whether the action attached to the keybinding key can currently be invoked.
+// Actions which require an adapter or a selected device are unavailable if there are none,
+// and actions with a visibility handler are only available if the handler allows it.
+func (v *viewActions) available(key keybindings.Key) bool {
+	if _, ok := v.fnmap[actionInvoke][key]; ok && !v.rv.adapter.hasAdapter() && !slices.Contains(adapterlessActions, key) {
+		return false
+	}
+
+	if v.rv.menu.isDeviceOption(key) {
+		if device := v.rv.device.getSelection(false); device.IsNil() {
+			return false
+		}
+	}
+
+	if _, ok := v.fnmap[actionVisibility][key]; ok {
+		return v.handler(key, actionVisibility)()
+	}
+
+	return true
+}
+
 // power checks and toggles the adapter's powered state.
 func (v *viewActions) power(set ...string) bool {
 	var poweredText string
@@ -136,6 +233,49 @@ func (v *viewActions) power(set ...string) bool {
 	return true
 }
 
+// resetAdapter power cycles the adapter, and lists its devices once it is powered on again.
+// Only one reset can be in progress at a time.
+func (v *viewActions) resetAdapter(_ ...string) bool {
+	if !v.resetting.CompareAndSwap(false, true) {
+		v.rv.status.InfoMessage("The adapter is already being reset", false)
+		return false
+	}
+	defer v.resetting.Store(false)
+
+	props, err := v.rv.adapter.currentSession().Properties()
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	name := getAdapterDisplayName(props)
+
+	v.rv.status.InfoMessage("Resetting "+name+": Powering off", true)
+	if err := v.rv.adapter.currentSession().SetPoweredState(false); err != nil {
+		v.rv.status.ErrorMessage(fmt.Errorf("cannot power off %s: %w", name, err))
+		return false
+	}
+	v.rv.menu.toggleItemByKey(keybindings.KeyAdapterTogglePower, false)
+
+	time.Sleep(adapterResetDelay)
+
+	v.rv.status.InfoMessage("Resetting "+name+": Powering on", true)
+	if err := v.rv.adapter.currentSession().SetPoweredState(true); err != nil {
+		v.rv.status.ErrorMessage(fmt.Errorf("cannot power on %s: %w", name, err))
+		return false
+	}
+	v.rv.menu.toggleItemByKey(keybindings.KeyAdapterTogglePower, true)
+
+	v.rv.app.QueueDraw(func() {
+		v.rv.adapter.updateTopStatus()
+		v.rv.device.list()
+	})
+
+	v.rv.status.InfoMessage(name+" has been reset", false)
+
+	return true
+}
+
 // discoverable checks and toggles the adapter's discoverable state.
 func (v *viewActions) discoverable(set ...string) bool {
 	var discoverableText string
@@ -160,11 +300,34 @@ func (v *viewActions) discoverable(set ...string) bool {
 		discoverable = !state
 	}
 
+	timeout := v.rv.cfg.Values.DiscoverableTimeout
+	if !discoverable && timeout > 0 && bluezSupported {
+		if err := setAdapterTimeout(props.UniqueName, "DiscoverableTimeout", uint32(timeout)); err != nil {
+			v.rv.status.ErrorMessage(err)
+			return false
+		}
+	}
+
 	if err := v.rv.adapter.currentSession().SetDiscoverableState(!discoverable); err != nil {
 		v.rv.status.ErrorMessage(err)
 		return false
 	}
 
+	if !discoverable && timeout > 0 && bluezSupported {
+		v.rv.adapter.startStateTimeout(props.AdapterAddress, "discoverable", time.Duration(timeout)*time.Second, func() {
+			if err := v.rv.app.Session().Adapter(props.AdapterAddress).SetDiscoverableState(false); err != nil {
+				v.rv.status.ErrorMessage(err)
+				return
+			}
+
+			if v.rv.adapter.isCurrentAdapter(props.Address) {
+				v.rv.menu.toggleItemByKey(keybindings.KeyAdapterToggleDiscoverable, false)
+			}
+		})
+	} else {
+		v.rv.adapter.stopStateTimeout(props.AdapterAddress, "discoverable")
+	}
+
 	if !discoverable {
 		discoverableText = "discoverable"
 	} else {
@@ -202,11 +365,34 @@ func (v *viewActions) pairable(set ...string) bool {
 		pairable = !state
 	}
 
+	timeout := v.rv.cfg.Values.PairableTimeout
+	if !pairable && timeout > 0 && bluezSupported {
+		if err := setAdapterTimeout(props.UniqueName, "PairableTimeout", uint32(timeout)); err != nil {
+			v.rv.status.ErrorMessage(err)
+			return false
+		}
+	}
+
 	if err := v.rv.adapter.currentSession().SetPairableState(!pairable); err != nil {
 		v.rv.status.ErrorMessage(err)
 		return false
 	}
 
+	if !pairable && timeout > 0 && bluezSupported {
+		v.rv.adapter.startStateTimeout(props.AdapterAddress, "pairable", time.Duration(timeout)*time.Second, func() {
+			if err := v.rv.app.Session().Adapter(props.AdapterAddress).SetPairableState(false); err != nil {
+				v.rv.status.ErrorMessage(err)
+				return
+			}
+
+			if v.rv.adapter.isCurrentAdapter(props.Address) {
+				v.rv.menu.toggleItemByKey(keybindings.KeyAdapterTogglePairable, false)
+			}
+		})
+	} else {
+		v.rv.adapter.stopStateTimeout(props.AdapterAddress, "pairable")
+	}
+
 	if !pairable {
 		pairableText = "pairable"
 	} else {
@@ -242,13 +428,36 @@ func (v *viewActions) scan(set ...string) bool {
 		discover = !state
 	}
 
+	timeout := v.rv.cfg.Values.ScanTimeout
+
 	if !discover {
 		if err := v.rv.app.Session().Adapter(props.AdapterAddress).StartDiscovery(); err != nil {
 			v.rv.status.ErrorMessage(err)
 			return false
 		}
 		v.rv.status.InfoMessage("Scanning for devices...", true)
+
+		if timeout > 0 {
+			v.rv.adapter.startStateTimeout(props.AdapterAddress, "scanning", time.Duration(timeout)*time.Second, func() {
+				adapter, err := v.rv.app.Session().Adapter(props.AdapterAddress).Properties()
+				if err != nil || !adapter.Discovering.Value() {
+					return
+				}
+
+				if err := v.rv.app.Session().Adapter(props.AdapterAddress).StopDiscovery(); err != nil {
+					v.rv.status.ErrorMessage(err)
+					return
+				}
+				v.rv.status.InfoMessage(fmt.Sprintf("Scanning stopped after %d seconds", timeout), false)
+
+				if v.rv.adapter.isCurrentAdapter(props.Address) {
+					v.rv.menu.toggleItemByKey(keybindings.KeyAdapterToggleScan, false)
+				}
+			})
+		}
 	} else {
+		v.rv.adapter.stopStateTimeout(props.AdapterAddress, "scanning")
+
 		if err := v.rv.app.Session().Adapter(props.AdapterAddress).StopDiscovery(); err != nil {
 			v.rv.status.ErrorMessage(err)
 			return false
@@ -270,6 +479,154 @@ func (v *viewActions) changeAdapter(_ ...string) bool {
 	return true
 }
 
+// sortDevices switches to the next sort mode and re-sorts the devices list.
+func (v *viewActions) sortDevices(_ ...string) bool {
+	mode := v.rv.device.nextSortMode()
+
+	v.rv.app.QueueDraw(func() {
+		v.rv.device.list()
+	})
+
+	v.rv.status.InfoMessage("Sorting devices by "+mode.description(), false)
+	if err := v.rv.cfg.Save("device-sort", mode.String()); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	return true
+}
+
+// minRSSI requests the signal strength below which discovered devices are hidden.
+func (v *viewActions) minRSSI(_ ...string) bool {
+	current := strconv.Itoa(int(v.rv.device.minRSSI.Load()))
+
+	input, ok := v.rv.status.SetInputText("Minimum signal strength in dBm (0 shows all devices):", current)
+	if !ok {
+		return false
+	}
+
+	rssi, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || rssi < -127 || rssi > 0 {
+		v.rv.status.ErrorMessage(errors.New("the minimum signal strength must be between -127 and 0 dBm"))
+		return false
+	}
+
+	v.rv.device.setMinRSSI(rssi)
+
+	if rssi == 0 {
+		v.rv.status.InfoMessage("Showing all devices", false)
+	} else {
+		v.rv.status.InfoMessage(fmt.Sprintf("Hiding discovered devices below %d dBm", rssi), false)
+	}
+
+	return true
+}
+
+// connectDevice connects to the device, until the context is cancelled.
+// If the context is cancelled while the device is connecting, the pending connection
+// attempt is aborted by disconnecting the device, which BlueZ uses to cancel a preceding
+// connection request before it has been answered.
+func (v *viewActions) connectDevice(ctx context.Context, address bluetooth.DeviceAddress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	device := v.rv.app.Session().Device(address)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- device.Connect()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+
+	case <-ctx.Done():
+	}
+
+	v.rv.reconnect.ignoreNext(address.Address)
+	if err := device.Disconnect(); err == nil {
+		v.rv.player.closeForDevice(address)
+	}
+
+	<-result
+
+	return ctx.Err()
+}
+
+// connectDeviceWithRetry connects to the device, and retries the connection if it fails, as many
+// times as set in the "connect-retries" option. The delay between the attempts starts at the
+// "connect-retry-delay" option and is doubled after each attempt. The attempts are shown in the
+// status bar, and no more attempts are made once the context is cancelled.
+func (v *viewActions) connectDeviceWithRetry(ctx context.Context, device bluetooth.DeviceData) error {
+	name := btdevice.DisplayName(device.DeviceEventData)
+	attempts := v.rv.cfg.Values.ConnectRetries + 1
+	delay := time.Duration(v.rv.cfg.Values.ConnectRetryDelay) * time.Second
+
+	var err error
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			v.rv.status.InfoMessage(fmt.Sprintf("Could not connect to %s, retrying in %s", name, delay), true)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+
+			case <-time.After(delay):
+			}
+
+			delay = min(delay*2, connectRetryMaxDelay)
+		}
+
+		message := "Connecting to " + name
+		if attempts > 1 {
+			message += fmt.Sprintf(" (attempt %d/%d)", attempt, attempts)
+		}
+		v.rv.status.InfoMessage(message, true)
+
+		if err = v.connectDevice(ctx, device.DeviceAddress); err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// renameAdapter sets the current adapter's alias to the name entered by the user.
+func (v *viewActions) renameAdapter(_ ...string) bool {
+	props, err := v.rv.adapter.currentSession().Properties()
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	alias, ok := v.rv.status.SetInputText("Rename "+getAdapterDisplayName(props)+" to:", "")
+	if !ok {
+		return false
+	}
+
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		v.rv.status.ErrorMessage(errors.New("the adapter name cannot be empty"))
+		return false
+	}
+
+	if err := setAdapterAlias(props.UniqueName, alias); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.app.QueueDraw(func() {
+		v.rv.adapter.refreshHeader()
+	})
+
+	v.rv.status.InfoMessage("Renamed "+props.UniqueName+" to "+alias, false)
+
+	return true
+}
+
 // progress displays the progress view.
 func (v *viewActions) progress(_ ...string) bool {
 	v.rv.app.QueueDraw(func() {
@@ -279,10 +636,79 @@ func (v *viewActions) progress(_ ...string) bool {
 	return true
 }
 
+// messageLog shows the history of the status bar messages.
+func (v *viewActions) messageLog(_ ...string) bool {
+	v.rv.app.QueueDraw(func() {
+		v.rv.status.showMessageLog()
+	})
+
+	return true
+}
+
+// reloadConfig reloads the configuration file.
+func (v *viewActions) reloadConfig(_ ...string) bool {
+	if err := v.rv.reloadConfig(); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	if err := errors.Join(v.rv.cfg.DecodeError(), v.rv.cfg.Values.ThemeFileError); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return true
+	}
+
+	v.rv.status.InfoMessage("Configuration reloaded", false)
+
+	return true
+}
+
+// reloadTheme re-reads the theme file, and redraws the views with the new theme.
+func (v *viewActions) reloadTheme(_ ...string) bool {
+	if err := v.rv.reloadTheme(); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.status.InfoMessage("Theme reloaded", false)
+
+	return true
+}
+
+// refresh restarts the session, so that the adapters and devices are enumerated again,
+// and redraws the views.
+func (v *viewActions) refresh(_ ...string) bool {
+	v.rv.status.InfoMessage("Refreshing...", true)
+
+	v.rv.op.cancelOperation(false)
+
+	if err := v.rv.restartSession(); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.defaults.init()
+
+	found := v.rv.adapter.refresh()
+
+	v.rv.app.QueueDraw(func() {
+		v.rv.adapter.updateTopStatus()
+		v.rv.device.list()
+	})
+
+	if !found {
+		v.rv.status.ErrorMessage(errors.New(noAdapterMessage))
+		return false
+	}
+
+	v.rv.status.InfoMessage("Refreshed", false)
+
+	return true
+}
+
 // quit stops discovery mode for all existing adapters, closes the bluetooth connection
 // and exits the application.
 func (v *viewActions) quit(_ ...string) bool {
-	if v.rv.cfg.Values.ConfirmOnQuit && v.rv.status.SetInput("Quit (y/n)?") != "y" {
+	if !v.confirm(v.rv.cfg.Values.ConfirmOnQuit, "Quit") {
 		return false
 	}
 
@@ -292,6 +718,9 @@ func (v *viewActions) quit(_ ...string) bool {
 		}
 	}
 
+	v.rv.mpris.stop()
+	v.rv.logger.log("app", "Stopped logging")
+	v.rv.logger.stop()
 	v.rv.app.Close()
 
 	return true
@@ -354,6 +783,29 @@ func (v *viewActions) initTrust(_ ...string) bool {
 	return ok && trusted
 }
 
+// initFavorite creates the oncreate handler for the favorite submenu option.
+func (v *viewActions) initFavorite(_ ...string) bool {
+	device := v.rv.device.getSelection(false)
+	if device.IsNil() {
+		return false
+	}
+
+	return v.rv.device.isFavorite(device.Address)
+}
+
+// initSetDefault creates the oncreate handler for the default device submenu option.
+func (v *viewActions) initSetDefault(_ ...string) bool {
+	device := v.rv.device.getSelection(false)
+	adapter := v.rv.adapter.getAdapter()
+	if device.IsNil() || adapter == nil {
+		return false
+	}
+
+	address, ok := v.rv.defaults.get(*adapter)
+
+	return ok && address == device.Address.String()
+}
+
 // initBlock creates the oncreate handler for the block submenu option.
 func (v *viewActions) initBlock(_ ...string) bool {
 	device := v.rv.device.getSelection(false)
@@ -377,6 +829,17 @@ func (v *viewActions) visibleSend(_ ...string) bool {
 		device.HaveService(bluetooth.ObexObjpushServiceClass)
 }
 
+// visibleBrowse creates the visible handler for the browse files submenu option.
+func (v *viewActions) visibleBrowse(_ ...string) bool {
+	device := v.rv.device.getSelection(false)
+	if device.IsNil() {
+		return false
+	}
+
+	return v.rv.app.Features().Has(appfeatures.FeatureSendFile, appfeatures.FeatureReceiveFile) &&
+		device.HaveService(bluetooth.ObexFiletransServiceClass)
+}
+
 // visibleNetwork creates the visible handler for the network submenu option.
 func (v *viewActions) visibleNetwork(_ ...string) bool {
 	device := v.rv.device.getSelection(false)
@@ -385,15 +848,13 @@ func (v *viewActions) visibleNetwork(_ ...string) bool {
 	}
 
 	return v.rv.app.Features().Has(appfeatures.FeatureNetwork) &&
-		device.HaveService(bluetooth.NapServiceClass) &&
-		(device.HaveService(bluetooth.PanuServiceClass) ||
-			device.HaveService(bluetooth.DialupNetServiceClass))
+		networkOptions(device) != nil
 }
 
 // visibleProfile creates the visible handler for the audio profiles submenu option.
 func (v *viewActions) visibleProfile(_ ...string) bool {
 	device := v.rv.device.getSelection(false)
-	if device.IsNil() {
+	if device.IsNil() || !v.rv.audioProfiles.isSupported.Load() {
 		return false
 	}
 
@@ -413,23 +874,56 @@ func (v *viewActions) visiblePlayer(_ ...string) bool {
 		device.HaveService(bluetooth.AvRemoteTargetServiceClass)
 }
 
+// visibleBluez creates the visible handler for options which set BlueZ properties directly,
+// since the session does not provide a way to set them.
+func (v *viewActions) visibleBluez(_ ...string) bool {
+	return bluezSupported
+}
+
+// connectLast connects to the device which has most recently connected in this session, regardless
+// of the selected device. If no device has connected yet, the device which was remembered from
+// an earlier session is used, if enabled.
+func (v *viewActions) connectLast(_ ...string) bool {
+	var query string
+
+	if address, ok := v.rv.device.conns.lastConnected(); ok {
+		query = address.String()
+	} else if v.rv.cfg.Values.RememberLastDevice {
+		query = v.rv.cfg.Values.LastConnectedDevice
+	}
+
+	if query == "" {
+		v.rv.status.InfoMessage("No device has been connected yet", false)
+		return false
+	}
+
+	device, _, err := btdevice.Find(v.rv.app.Session(), v.rv.adapter.getAdapter().Address.String(), query)
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	if connected, _ := device.Connected.Get(); connected {
+		v.rv.status.InfoMessage(btdevice.DisplayName(device.DeviceEventData)+" is already connected", false)
+		return false
+	}
+
+	return v.connect(device.Address.String())
+}
+
 // connect retrieves the selected device, and toggles its connection state.
+// If an address or name is provided, the matching device of the current adapter is used instead.
 func (v *viewActions) connect(set ...string) bool {
 	var device bluetooth.DeviceData
 
 	if set != nil {
-		devices, err := v.rv.adapter.currentSession().Devices()
+		var err error
+
+		device, _, err = btdevice.Find(v.rv.app.Session(), v.rv.adapter.getAdapter().Address.String(), set[0])
 		if err != nil {
 			v.rv.status.ErrorMessage(err)
 			return false
 		}
-
-		for _, d := range devices {
-			if d.Address.String() == set[0] {
-				device = d
-				break
-			}
-		}
 	} else {
 		device = v.rv.device.getSelection(true)
 		if device.IsNil() {
@@ -443,6 +937,7 @@ func (v *viewActions) connect(set ...string) bool {
 	}
 
 	disconnectFunc := func() {
+		v.rv.reconnect.ignoreNext(device.Address)
 		if err := v.rv.app.Session().Device(device.DeviceAddress).Disconnect(); err != nil {
 			v.rv.status.ErrorMessage(err)
 			return
@@ -451,30 +946,45 @@ func (v *viewActions) connect(set ...string) bool {
 		v.rv.player.closeForDevice(device.DeviceAddress)
 	}
 
-	connectFunc := func() {
-		v.rv.status.InfoMessage("Connecting to "+getDeviceDisplayName(device.DeviceEventData), true)
-		if err := v.rv.app.Session().Device(device.DeviceAddress).Connect(); err != nil {
-			v.rv.status.ErrorMessage(err)
-			return
+	if !connected {
+		ctx, cancel := context.WithCancel(context.Background())
+		v.rv.safe.allow(device.Address)
+
+		connectFunc := func() {
+			defer cancel()
+
+			if err := v.connectDeviceWithRetry(ctx, device); err != nil {
+				v.rv.menu.toggleItemByKey(keybindings.KeyDeviceConnect, false)
+
+				if errors.Is(err, context.Canceled) {
+					v.rv.status.InfoMessage("Cancelled connection to "+btdevice.DisplayName(device.DeviceEventData), false)
+					return
+				}
+
+				v.rv.status.ErrorMessage(err)
+				return
+			}
+			v.rv.status.InfoMessage("Connected to "+btdevice.DisplayName(device.DeviceEventData), false)
 		}
-		v.rv.status.InfoMessage("Connected to "+getDeviceDisplayName(device.DeviceEventData), false)
-	}
 
-	if !connected {
-		v.rv.op.startOperation(
-			connectFunc,
-			func() {
-				disconnectFunc()
-				v.rv.status.InfoMessage("Cancelled connection to "+getDeviceDisplayName(device.DeviceEventData), false)
-			},
-		)
+		v.rv.menu.toggleItemByKey(keybindings.KeyDeviceConnect, true)
+		if !v.rv.op.startOperation(connectFunc, cancel) {
+			v.rv.menu.toggleItemByKey(keybindings.KeyDeviceConnect, false)
+			cancel()
+
+			return false
+		}
 	} else {
-		v.rv.status.InfoMessage("Disconnecting from "+getDeviceDisplayName(device.DeviceEventData), true)
+		if !v.confirm(v.rv.cfg.Values.ConfirmOnDisconnect, "Disconnect from "+btdevice.DisplayName(device.DeviceEventData)) {
+			return false
+		}
+
+		v.rv.status.InfoMessage("Disconnecting from "+btdevice.DisplayName(device.DeviceEventData), true)
 		disconnectFunc()
-		v.rv.status.InfoMessage("Disconnected from "+getDeviceDisplayName(device.DeviceEventData), false)
-	}
+		v.rv.status.InfoMessage("Disconnected from "+btdevice.DisplayName(device.DeviceEventData), false)
 
-	v.rv.menu.toggleItemByKey(keybindings.KeyDeviceConnect, !connected)
+		v.rv.menu.toggleItemByKey(keybindings.KeyDeviceConnect, false)
+	}
 
 	return true
 }
@@ -491,25 +1001,26 @@ func (v *viewActions) pair(_ ...string) bool {
 		v.rv.status.ErrorMessage(errors.New("cannot determine if the device is paired"))
 	}
 	if ok && paired {
-		v.rv.status.InfoMessage(getDeviceDisplayName(device.DeviceEventData)+" is already paired", false)
+		v.rv.status.InfoMessage(btdevice.DisplayName(device.DeviceEventData)+" is already paired", false)
 		return false
 	}
 
+	v.rv.safe.allow(device.Address)
 	v.rv.op.startOperation(
 		func() {
-			v.rv.status.InfoMessage("Pairing with "+getDeviceDisplayName(device.DeviceEventData), true)
-			if err := v.rv.app.Session().Device(device.DeviceAddress).Pair(); err != nil {
+			v.rv.status.InfoMessage("Pairing with "+btdevice.DisplayName(device.DeviceEventData), true)
+			if err := v.rv.agent.pair(device.DeviceAddress); err != nil {
 				v.rv.status.ErrorMessage(err)
 				return
 			}
-			v.rv.status.InfoMessage("Paired with "+getDeviceDisplayName(device.DeviceEventData), false)
+			v.rv.status.InfoMessage("Paired with "+btdevice.DisplayName(device.DeviceEventData), false)
 		},
 		func() {
 			if err := v.rv.app.Session().Device(device.DeviceAddress).CancelPairing(); err != nil {
 				v.rv.status.ErrorMessage(err)
 				return
 			}
-			v.rv.status.InfoMessage("Cancelled pairing with "+getDeviceDisplayName(device.DeviceEventData), false)
+			v.rv.status.InfoMessage("Cancelled pairing with "+btdevice.DisplayName(device.DeviceEventData), false)
 		},
 	)
 
@@ -529,7 +1040,7 @@ func (v *viewActions) trust(_ ...string) bool {
 	}
 
 	if err := v.rv.app.Session().Device(device.DeviceAddress).SetTrusted(!trusted); err != nil {
-		v.rv.status.ErrorMessage(errors.New("cannot set trusted property for " + getDeviceDisplayName(device.DeviceEventData)))
+		v.rv.status.ErrorMessage(errors.New("cannot set trusted property for " + btdevice.DisplayName(device.DeviceEventData)))
 		return false
 	}
 
@@ -538,6 +1049,56 @@ func (v *viewActions) trust(_ ...string) bool {
 	return true
 }
 
+// trustConnect retrieves the selected device, trusts it if it is not trusted,
+// and attempts to connect to it.
+func (v *viewActions) trustConnect(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	if connected, ok := device.Connected.Get(); ok && connected {
+		v.rv.status.InfoMessage(btdevice.DisplayName(device.DeviceEventData)+" is already connected", false)
+		return false
+	}
+
+	trusted, _ := device.Trusted.Get()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if !v.rv.op.startOperation(
+		func() {
+			defer cancel()
+
+			v.rv.status.InfoMessage("Trusting and connecting to "+btdevice.DisplayName(device.DeviceEventData), true)
+
+			if !trusted {
+				if err := v.rv.app.Session().Device(device.DeviceAddress).SetTrusted(true); err != nil {
+					v.rv.status.ErrorMessage(errors.New("cannot set trusted property for " + btdevice.DisplayName(device.DeviceEventData)))
+					return
+				}
+			}
+
+			if err := v.connectDevice(ctx, device.DeviceAddress); err != nil {
+				if errors.Is(err, context.Canceled) {
+					v.rv.status.InfoMessage("Cancelled connection to "+btdevice.DisplayName(device.DeviceEventData), false)
+					return
+				}
+
+				v.rv.status.ErrorMessage(err)
+				return
+			}
+
+			v.rv.status.InfoMessage("Trusted and connected to "+btdevice.DisplayName(device.DeviceEventData), false)
+		},
+		cancel,
+	) {
+		cancel()
+		return false
+	}
+
+	return true
+}
+
 // block retrieves the selected device, and toggles its block property.
 func (v *viewActions) block(_ ...string) bool {
 	device := v.rv.device.getSelection(true)
@@ -550,8 +1111,12 @@ func (v *viewActions) block(_ ...string) bool {
 		return false
 	}
 
+	if !blocked && !v.confirm(v.rv.cfg.Values.ConfirmOnBlock, "Block "+btdevice.DisplayName(device.DeviceEventData)) {
+		return false
+	}
+
 	if err := v.rv.app.Session().Device(device.DeviceAddress).SetBlocked(!blocked); err != nil {
-		v.rv.status.ErrorMessage(errors.New("cannot set blocked property for " + getDeviceDisplayName(device.DeviceEventData)))
+		v.rv.status.ErrorMessage(errors.New("cannot set blocked property for " + btdevice.DisplayName(device.DeviceEventData)))
 		return false
 	}
 
@@ -579,10 +1144,33 @@ func (v *viewActions) send(_ ...string) bool {
 	}
 
 	if !paired {
-		displayErr = errors.New(getDeviceDisplayName(device.DeviceEventData) + " is not paired")
+		displayErr = errors.New(btdevice.DisplayName(device.DeviceEventData) + " is not paired")
+		return false
+	}
+
+	fileList, err := v.rv.filepicker.Show(btdevice.DisplayName(device.DeviceEventData))
+	if err != nil {
+		displayErr = err
+		return false
+	}
+	if len(fileList) == 0 {
+		return false
+	}
+
+	msg := fmt.Sprintf(
+		"Send [::b]%d[-:-:-] file(s) ([::b]%s[-:-:-]) to [::bu]%s[-:-:-]?",
+		len(fileList), formatSize(v.rv.filepicker.selectedSize()), btdevice.DisplayName(device.DeviceEventData),
+	)
+	if v.rv.modals.newConfirmModal("send-confirm", "Send Files", msg).getReply(context.Background()) != "y" {
+		v.rv.status.InfoMessage("Cancelled sending files", false)
 		return false
 	}
 
+	if v.rv.progress.appendQueue(device.DeviceAddress, fileList) {
+		v.rv.status.InfoMessage("Queued "+strconv.Itoa(len(fileList))+" file(s) for "+btdevice.DisplayName(device.DeviceEventData), false)
+		return true
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	v.rv.op.startOperation(
@@ -600,34 +1188,50 @@ func (v *viewActions) send(_ ...string) bool {
 
 			v.rv.status.InfoMessage("Created Object Push session", false)
 
-			fileList, err := v.rv.filepicker.Show()
+			v.rv.progress.queueTransfer(device.DeviceAddress, oppSession, fileList)
+		},
+		func() {
+			cancel()
+			v.rv.status.InfoMessage("Cancelled Object Push session creation", false)
+		},
+	)
+
+	return true
+}
+
+// browse opens a file transfer session with the selected device,
+// and shows the remote file browser.
+func (v *viewActions) browse(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	if !device.HaveService(bluetooth.ObexFiletransServiceClass) {
+		v.rv.status.ErrorMessage(errors.New(btdevice.DisplayName(device.DeviceEventData) + " does not support browsing files"))
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v.rv.op.startOperation(
+		func() {
+			v.rv.status.InfoMessage("Initializing File Transfer session..", true)
+
+			ftpSession, err := newObexFileTransfer(ctx, device.DeviceAddress)
 			if err != nil {
 				v.rv.status.ErrorMessage(err)
-				oppSession.RemoveSession()
-				return
-			}
-			if len(fileList) == 0 {
-				oppSession.RemoveSession()
 				return
 			}
 
-			proplist := make([]bluetooth.ObjectPushData, 0, len(fileList))
-			for _, file := range fileList {
-				props, err := oppSession.SendFile(file)
-				if err != nil || props.Status == bluetooth.TransferError {
-					oppSession.RemoveSession()
-					v.rv.status.ErrorMessage(err)
-					return
-				}
-
-				proplist = append(proplist, props)
-			}
+			v.rv.op.cancelOperation(false)
 
-			v.rv.progress.startTransfer(device.DeviceAddress, oppSession, proplist)
+			v.rv.status.InfoMessage("Created File Transfer session", false)
+			v.rv.remoteFiles.show(device.DeviceAddress, ftpSession)
 		},
 		func() {
 			cancel()
-			v.rv.status.InfoMessage("Cancelled Object Push session creation", false)
+			v.rv.status.InfoMessage("Cancelled File Transfer session creation", false)
 		},
 	)
 
@@ -643,6 +1247,18 @@ func (v *viewActions) networkAP(_ ...string) bool {
 	return true
 }
 
+// networkDisconnect disconnects the active network connection of the selected device.
+func (v *viewActions) networkDisconnect(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	v.rv.network.networkDisconnect(device)
+
+	return true
+}
+
 // profiles launches a popup with the available audio profiles.
 func (v *viewActions) profiles(_ ...string) bool {
 	v.rv.app.QueueDraw(func() {
@@ -652,6 +1268,27 @@ func (v *viewActions) profiles(_ ...string) bool {
 	return true
 }
 
+// audioHighQuality switches the selected device to a high quality audio profile.
+func (v *viewActions) audioHighQuality(_ ...string) bool {
+	v.rv.audioProfiles.switchMode(audioModeHighQuality)
+
+	return true
+}
+
+// audioHeadset switches the selected device to a headset audio profile.
+func (v *viewActions) audioHeadset(_ ...string) bool {
+	v.rv.audioProfiles.switchMode(audioModeHeadset)
+
+	return true
+}
+
+// audioDefault sets the selected device as the default audio output.
+func (v *viewActions) audioDefault(_ ...string) bool {
+	v.rv.audioProfiles.setDefaultSink()
+
+	return true
+}
+
 // showplayer starts the media player.
 func (v *viewActions) showplayer(_ ...string) bool {
 	v.rv.player.show()
@@ -668,21 +1305,115 @@ func (v *viewActions) hideplayer(_ ...string) bool {
 
 // info retrieves the selected device, and shows the device information.
 func (v *viewActions) info(_ ...string) bool {
+	v.rv.device.showDetailedInfo()
+
+	return true
+}
+
+// adapterInfo shows detailed information about the current adapter.
+func (v *viewActions) adapterInfo(_ ...string) bool {
+	v.rv.adapter.showDetailedInfo()
+
+	return true
+}
+
+// deviceProfiles shows the profiles of the selected device.
+func (v *viewActions) deviceProfiles(_ ...string) bool {
 	v.rv.app.QueueDraw(func() {
-		v.rv.device.showDetailedInfo()
+		v.rv.device.showProfiles()
 	})
 
 	return true
 }
 
-// remove retrieves the selected device, and removes it from the adapter.
+// copyAddress retrieves the selected device, and copies its address to the clipboard.
+func (v *viewActions) copyAddress(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	if err := copyToClipboard(device.Address.String()); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.status.InfoMessage("Copied the address of "+btdevice.DisplayName(device.DeviceEventData)+" to the clipboard", false)
+
+	return true
+}
+
+// favorite retrieves the selected device, and toggles whether it is a favorite.
+// Favorite devices are stored by their addresses in the configuration, and are listed first.
+func (v *viewActions) favorite(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	address := device.Address.String()
+	favorites := slices.Clone(v.rv.cfg.Values.FavoriteDevices)
+
+	isFavorite := slices.Contains(favorites, address)
+	if isFavorite {
+		favorites = slices.DeleteFunc(favorites, func(favorite string) bool {
+			return favorite == address
+		})
+	} else {
+		favorites = append(favorites, address)
+	}
+
+	if err := v.rv.cfg.Save("favorite-devices", favorites); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.cfg.Values.FavoriteDevices = favorites
+	v.rv.menu.toggleItemByKey(keybindings.KeyDeviceFavorite, !isFavorite)
+
+	v.rv.app.QueueDraw(func() {
+		v.rv.device.list()
+	})
+
+	return true
+}
+
+// setDefault retrieves the selected device, and toggles whether it is the default device
+// of the current adapter. The default device is connected to once the adapter is powered on.
+func (v *viewActions) setDefault(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	adapter := v.rv.adapter.getAdapter()
+	if device.IsNil() || adapter == nil {
+		return false
+	}
+
+	isDefault, err := v.rv.defaults.toggle(*adapter, device)
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.menu.toggleItemByKey(keybindings.KeyDeviceSetDefault, isDefault)
+
+	message := "Unset " + btdevice.DisplayName(device.DeviceEventData) + " as the default device of " + getAdapterDisplayName(*adapter)
+	if isDefault {
+		message = "Set " + btdevice.DisplayName(device.DeviceEventData) + " as the default device of " + getAdapterDisplayName(*adapter)
+	}
+	v.rv.status.InfoMessage(message, false)
+
+	return true
+}
+
+// remove retrieves the selected device, and removes it from the adapter. Removing a device
+// disconnects it and deletes its pairing keys from the adapter, so that a device with a stale bond
+// (for example, one which was reset or paired with another host) can be paired again.
 func (v *viewActions) remove(_ ...string) bool {
 	device := v.rv.device.getSelection(true)
 	if device.IsNil() {
 		return false
 	}
 
-	if txt := v.rv.status.SetInput("Remove " + getDeviceDisplayName(device.DeviceEventData) + " (y/n)?"); txt != "y" {
+	if !v.confirm(!v.rv.cfg.Values.NoConfirmOnRemove, "Remove "+btdevice.DisplayName(device.DeviceEventData)) {
 		return false
 	}
 
@@ -691,7 +1422,134 @@ func (v *viewActions) remove(_ ...string) bool {
 		return false
 	}
 
-	v.rv.status.InfoMessage("Removed "+getDeviceDisplayName(device.DeviceEventData), false)
+	v.rv.status.InfoMessage("Removed "+btdevice.DisplayName(device.DeviceEventData), false)
+
+	return true
+}
+
+// batch shows the actions which can be applied to all the selected devices.
+func (v *viewActions) batch(_ ...string) bool {
+	devices := v.rv.device.selectedDevices()
+	if len(devices) == 0 {
+		v.rv.status.InfoMessage("No devices are selected", false)
+		return false
+	}
+
+	v.rv.app.QueueDraw(func() {
+		v.rv.device.showBatchActions(devices)
+	})
+
+	return true
+}
+
+// identify makes the selected device alert the user, for example by beeping or vibrating, so that it
+// can be located. This uses the Immediate Alert service of the device, which is connected to first
+// if required. The alert is stopped after a short duration.
+func (v *viewActions) identify(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	name := btdevice.DisplayName(device.DeviceEventData)
+
+	if !device.HaveService(immediateAlertServiceClass) {
+		v.rv.status.InfoMessage("Identifying is not supported by "+name, false)
+		return false
+	}
+
+	adapter, err := v.rv.app.Session().Adapter(device.DeviceAddress.AdapterAddress()).Properties()
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	identifyFunc := func() {
+		defer cancel()
+
+		if !device.Connected.Value() {
+			v.rv.status.InfoMessage("Connecting to "+name, true)
+			if err := v.connectDevice(ctx, device.DeviceAddress); err != nil {
+				if !errors.Is(err, context.Canceled) {
+					v.rv.status.ErrorMessage(err)
+				}
+
+				return
+			}
+		}
+
+		path, err := waitAlertLevelPath(ctx, adapter.UniqueName, device.Address)
+		if err == nil {
+			err = setAlertLevel(path, alertLevelHigh)
+		}
+		switch {
+		case errors.Is(err, errIdentifyUnsupported):
+			v.rv.status.InfoMessage("Identifying is not supported by "+name, false)
+			return
+
+		case errors.Is(err, context.Canceled):
+			return
+
+		case err != nil:
+			v.rv.status.ErrorMessage(fmt.Errorf("cannot identify %s: %w", name, err))
+			return
+		}
+
+		v.rv.status.InfoMessage("Identifying "+name, false)
+
+		time.AfterFunc(identifyDuration, func() {
+			setAlertLevel(path, alertLevelNone)
+		})
+	}
+
+	if !v.rv.op.startOperation(identifyFunc, func() {
+		cancel()
+		v.rv.status.InfoMessage("Cancelled identifying "+name, false)
+	}) {
+		cancel()
+		return false
+	}
+
+	return true
+}
+
+// rename retrieves the selected device, and sets its alias to the name entered by the user.
+func (v *viewActions) rename(_ ...string) bool {
+	device := v.rv.device.getSelection(true)
+	if device.IsNil() {
+		return false
+	}
+
+	adapter, err := v.rv.app.Session().Adapter(device.AdapterAddress()).Properties()
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	alias := strings.TrimSpace(v.rv.status.SetInput("Rename "+btdevice.DisplayName(device.DeviceEventData)+" to:", struct{}{}))
+	if alias == "" {
+		return false
+	}
+
+	if err := setDeviceAlias(adapter.UniqueName, device.Address, alias); err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	device, err = v.rv.app.Session().Device(device.DeviceAddress).Properties()
+	if err != nil {
+		v.rv.status.ErrorMessage(err)
+		return false
+	}
+
+	v.rv.app.QueueDraw(func() {
+		if row, ok := v.rv.device.getRowByAddress(device.DeviceAddress); ok {
+			v.rv.device.setInfo(row, device)
+		}
+	})
+
+	v.rv.status.InfoMessage("Renamed "+device.Address.String()+" to "+alias, false)
 
 	return true
 }
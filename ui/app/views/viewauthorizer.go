@@ -4,9 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/tview"
 	"github.com/google/uuid"
+
+	"github.com/darkhz/bluetuith/ui/btdevice"
 )
 
 // authorizer holds a set of functions used to authenticate pairing and receiving
@@ -31,12 +35,21 @@ func (a *authorizer) setInitialized() {
 }
 
 // AuthorizeTransfer asks the user to authorize a file transfer (Object Push) that is about to be sent
-// from the remote device.
+// from the remote device. If the user does not reply before the timeout, the transfer is rejected.
+// Transfers from trusted devices are accepted automatically if the "receive-accept-trusted" option is set.
 func (a *authorizer) AuthorizeTransfer(timeout bluetooth.AuthTimeout, props bluetooth.ObjectPushData) error {
 	if !a.initialized {
 		return nil
 	}
 
+	if decided, err := a.checkPairingLists(props.DeviceAddress, "file transfer"); decided {
+		if err == nil {
+			a.v.progress.showStatus()
+		}
+
+		return err
+	}
+
 	if a.alwaysAuthorize {
 		a.v.progress.showStatus()
 		return nil
@@ -47,13 +60,37 @@ func (a *authorizer) AuthorizeTransfer(timeout bluetooth.AuthTimeout, props blue
 		return err
 	}
 
+	name := btdevice.DisplayName(device.DeviceEventData)
+
 	filename := props.Name
 	if filename == "" {
 		filename = filepath.Base(props.Filename)
 	}
 
-	reply := a.v.status.waitForInput(timeout, fmt.Sprintf("[::bu]%s[-:-:-]: Accept file '%s' (y/n/a)", getDeviceDisplayName(device.DeviceEventData), filename))
-	switch reply {
+	if trusted, _ := device.Trusted.Get(); trusted && a.v.cfg.Values.ReceiveAcceptTrusted {
+		a.v.status.InfoMessage(fmt.Sprintf("Automatically accepted '%s' from the trusted device %s", filename, name), false)
+		a.v.progress.showStatus()
+
+		return nil
+	}
+
+	a.v.notifier.notify("Incoming file", fmt.Sprintf("%s wants to send '%s'", name, filename))
+
+	size := "unknown size"
+	if props.Size > 0 {
+		size = formatSize(int64(props.Size))
+	}
+
+	msg := fmt.Sprintf(
+		"[::bu]%s[-:-:-] wants to send a file:\n\n[::b]%s[-:-:-] (%s)",
+		tview.Escape(name), tview.Escape(filename), size,
+	)
+
+	modal := a.v.modals.newLabeledConfirmModal(
+		"transfer-authorize:"+props.Address.String(), "Incoming File", msg,
+		confirmModalLabels{confirm: "Accept", cancel: "Reject", always: "Always Accept"},
+	)
+	switch modal.getReply(timeout) {
 	case "a":
 		a.alwaysAuthorize = true
 		fallthrough
@@ -72,6 +109,10 @@ func (a *authorizer) DisplayPinCode(timeout bluetooth.AuthTimeout, pincode strin
 		return nil
 	}
 
+	if a.v.safe.check(address, "pincode display") {
+		return errors.New("Rejected")
+	}
+
 	device, err := a.v.app.Session().Device(address).Properties()
 	if err != nil {
 		return err
@@ -79,7 +120,7 @@ func (a *authorizer) DisplayPinCode(timeout bluetooth.AuthTimeout, pincode strin
 
 	msg := fmt.Sprintf(
 		"The pincode for [::bu]%s[-:-:-] is:\n\n[::b]%s[-:-:-]",
-		getDeviceDisplayName(device.DeviceEventData), pincode,
+		btdevice.DisplayName(device.DeviceEventData), pincode,
 	)
 
 	modal := a.generateDisplayModal(address, "pincode", "Pin Code", msg)
@@ -97,6 +138,10 @@ func (a *authorizer) DisplayPasskey(timeout bluetooth.AuthTimeout, passkey uint3
 		return nil
 	}
 
+	if a.v.safe.check(address, "passkey display") {
+		return errors.New("Rejected")
+	}
+
 	device, err := a.v.app.Session().Device(address).Properties()
 	if err != nil {
 		return err
@@ -104,7 +149,7 @@ func (a *authorizer) DisplayPasskey(timeout bluetooth.AuthTimeout, passkey uint3
 
 	msg := fmt.Sprintf(
 		"The passkey for [::bu]%s[-:-:-] is:\n\n[::b]%d[-:-:-]",
-		getDeviceDisplayName(device.DeviceEventData), passkey,
+		btdevice.DisplayName(device.DeviceEventData), passkey,
 	)
 	if entered > 0 {
 		msg += fmt.Sprintf("\n\nYou have entered %d", entered)
@@ -122,6 +167,14 @@ func (a *authorizer) ConfirmPasskey(timeout bluetooth.AuthTimeout, passkey uint3
 		return nil
 	}
 
+	if decided, err := a.checkPairingLists(address, "passkey confirmation"); decided {
+		if err == nil {
+			_ = a.v.app.Session().Device(address).SetTrusted(true)
+		}
+
+		return err
+	}
+
 	device, err := a.v.app.Session().Device(address).Properties()
 	if err != nil {
 		return err
@@ -129,7 +182,7 @@ func (a *authorizer) ConfirmPasskey(timeout bluetooth.AuthTimeout, passkey uint3
 
 	msg := fmt.Sprintf(
 		"Confirm passkey for [::bu]%s[-:-:-] is \n\n[::b]%d[-:-:-]",
-		getDeviceDisplayName(device.DeviceEventData), passkey,
+		btdevice.DisplayName(device.DeviceEventData), passkey,
 	)
 
 	modal := a.generateConfirmModal(address, "passkey-confirm", "Passkey Confirmation", msg)
@@ -149,11 +202,19 @@ func (a *authorizer) AuthorizePairing(timeout bluetooth.AuthTimeout, address blu
 		return nil
 	}
 
+	if decided, err := a.checkPairingLists(address, "pairing"); decided {
+		if err == nil {
+			_ = a.v.app.Session().Device(address).SetTrusted(true)
+		}
+
+		return err
+	}
+
 	device, err := a.v.app.Session().Device(address).Properties()
 	if err != nil {
 		return err
 	}
-	msg := fmt.Sprintf("Confirm pairing with [::bu]%s[-:-:-]", getDeviceDisplayName(device.DeviceEventData))
+	msg := fmt.Sprintf("Confirm pairing with [::bu]%s[-:-:-]", btdevice.DisplayName(device.DeviceEventData))
 
 	modal := a.generateConfirmModal(address, "pairing-confirm", "Pairing Confirmation", msg)
 	reply := modal.getReply(timeout)
@@ -168,17 +229,25 @@ func (a *authorizer) AuthorizePairing(timeout bluetooth.AuthTimeout, address blu
 
 // AuthorizeService asks the user to authorize whether a specific Bluetooth Profile is allowed to be used.
 func (a *authorizer) AuthorizeService(timeout bluetooth.AuthTimeout, profileUUID uuid.UUID, address bluetooth.DeviceAddress) error {
-	if !a.initialized || a.alwaysAuthorize {
+	if !a.initialized {
+		return nil
+	}
+
+	service := serviceName(profileUUID)
+	if decided, err := a.checkPairingLists(address, "service '"+service+"'"); decided {
+		return err
+	}
+
+	if a.alwaysAuthorize {
 		return nil
 	}
 
-	serviceName := bluetooth.ServiceType(profileUUID)
 	device, err := a.v.app.Session().Device(address).Properties()
 	if err != nil {
 		return err
 	}
 
-	reply := a.v.status.waitForInput(timeout, fmt.Sprintf("[::bu]%s[-:-:-]: Authorize service '%s' (y/n/a)", getDeviceDisplayName(device.DeviceEventData), serviceName))
+	reply := a.v.status.waitForInput(timeout, fmt.Sprintf("[::bu]%s[-:-:-]: Authorize service '%s' (y/n/a)", btdevice.DisplayName(device.DeviceEventData), service))
 	switch reply {
 	case "a":
 		a.alwaysAuthorize = true
@@ -191,6 +260,38 @@ func (a *authorizer) AuthorizeService(timeout bluetooth.AuthTimeout, profileUUID
 	return errors.New("Cancelled")
 }
 
+// checkPairingLists checks whether the device is present in the pairing allowlist or blocklist,
+// and whether it is blocked by the safe mode. It returns whether the request was automatically decided,
+// and an error if the request was rejected. Each automatic decision is shown in the status bar.
+func (a *authorizer) checkPairingLists(address bluetooth.DeviceAddress, request string) (bool, error) {
+	if a.isBlocklisted(address, request) {
+		return true, errors.New("Rejected")
+	}
+
+	if slices.Contains(a.v.cfg.Values.PairingAllowlist, address.Address.String()) {
+		a.v.status.InfoMessage(fmt.Sprintf("Automatically accepted the %s request from %s", request, address.Address.String()), false)
+		return true, nil
+	}
+
+	if a.v.safe.check(address, request) {
+		return true, errors.New("Rejected")
+	}
+
+	return false, nil
+}
+
+// isBlocklisted returns whether the device is present in the pairing blocklist.
+// If it is, the rejection of the request is shown in the status bar.
+func (a *authorizer) isBlocklisted(address bluetooth.DeviceAddress, request string) bool {
+	if !slices.Contains(a.v.cfg.Values.PairingBlocklist, address.Address.String()) {
+		return false
+	}
+
+	a.v.status.InfoMessage(fmt.Sprintf("Automatically rejected the %s request from %s", request, address.Address.String()), false)
+
+	return true
+}
+
 // generateConfirmModal generates a confirmation modal with the provided parameters.
 func (a *authorizer) generateConfirmModal(address bluetooth.DeviceAddress, name, title, msg string) *confirmModalView {
 	return a.v.modals.newConfirmModal(name+":"+address.Address.String(), title, msg)
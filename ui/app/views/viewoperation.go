@@ -1,11 +1,15 @@
 package views
 
-import "sync"
+import (
+	"context"
+	"sync"
+)
 
 // viewOperation holds an operation manager instance.
 type viewOperation struct {
-	cancel func()
-	lock   sync.Mutex
+	cancel       func()
+	stopActivity context.CancelFunc
+	lock         sync.Mutex
 
 	root *Views
 }
@@ -15,23 +19,30 @@ func newViewOperation(root *Views) *viewOperation {
 	return &viewOperation{root: root}
 }
 
-// startOperation sets up the cancellation handler,
-// and starts the operation.
-func (v *viewOperation) startOperation(dofunc, cancel func()) {
+// startOperation sets up the cancellation handler, and starts the operation.
+// An activity indicator is shown in the status bar until the operation completes or is cancelled.
+// It returns false if another operation is in progress.
+func (v *viewOperation) startOperation(dofunc, cancel func()) bool {
 	v.lock.Lock()
 	defer v.lock.Unlock()
 
 	if v.cancel != nil {
 		v.root.status.InfoMessage("Operation still in progress", false)
-		return
+		return false
 	}
 
 	v.cancel = cancel
 
+	ctx, stopActivity := context.WithCancel(context.Background())
+	v.stopActivity = stopActivity
+	go v.root.status.showActivity(ctx)
+
 	go func() {
 		dofunc()
 		v.cancelOperation(false)
 	}()
+
+	return true
 }
 
 // cancelOperation cancels the currently running operation.
@@ -48,6 +59,9 @@ func (v *viewOperation) cancelOperation(cancelfunc bool) {
 	cancel = v.cancel
 	v.cancel = nil
 
+	v.stopActivity()
+	v.stopActivity = nil
+
 	if cancelfunc {
 		go cancel()
 	}
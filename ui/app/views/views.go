@@ -1,6 +1,8 @@
 package views
 
 import (
+	"fmt"
+
 	"github.com/bluetuith-org/bluetooth-classic/api/appfeatures"
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
 	"github.com/darkhz/tview"
@@ -19,6 +21,7 @@ type AppData struct {
 	InitialFocus   *tview.Flex
 	MouseFunc      func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction)
 	BeforeDrawFunc func(t tcell.Screen) bool
+	AfterDrawFunc  func(t tcell.Screen)
 	InputCapture   func(event *tcell.EventKey) *tcell.EventKey
 }
 
@@ -26,6 +29,7 @@ type AppData struct {
 type AppBinder interface {
 	Session() bluetooth.Session
 	Features() *appfeatures.FeatureSet
+	RestartSession() error
 
 	QueueDraw(drawFunc func())
 	InstantDraw(drawFunc func())
@@ -53,6 +57,12 @@ type Views struct {
 	pages  *viewPages
 	layout *tview.Flex
 
+	// menuArea, menuSpacer and deviceArea hold the layouts of the main view,
+	// so that the theme can be re-applied to them.
+	menuArea   *tview.Flex
+	menuSpacer *tview.Box
+	deviceArea *tview.Flex
+
 	menu          *menuBarView
 	help          *helpView
 	status        *statusBarView
@@ -60,15 +70,26 @@ type Views struct {
 	device        *deviceView
 	adapter       *adapterView
 	filepicker    *filePickerView
+	remoteFiles   *remoteFilesView
 	progress      *progressView
 	player        *mediaPlayer
 	audioProfiles *audioProfilesView
 	network       *networkView
 
-	actions *viewActions
-	op      *viewOperation
-	kb      *keybindings.Keybindings
-	cfg     *config.Config
+	actions   *viewActions
+	op        *viewOperation
+	reconnect *deviceReconnector
+	notifier  *notifier
+	battery   *batteryWatcher
+	hooks     *deviceHooks
+	mpris     *mprisServer
+	logger    *eventLogger
+	defaults  *defaultDeviceConnector
+	daemon    *daemonWatcher
+	safe      *safeMode
+	agent     *pairingAgent
+	kb        *keybindings.Keybindings
+	cfg       *config.Config
 
 	app  AppBinder
 	auth *authorizer
@@ -85,6 +106,7 @@ func NewViews() *Views {
 		device:        &deviceView{},
 		adapter:       &adapterView{},
 		filepicker:    &filePickerView{},
+		remoteFiles:   &remoteFilesView{},
 		progress:      &progressView{},
 		player:        &mediaPlayer{},
 		audioProfiles: &audioProfilesView{},
@@ -107,6 +129,16 @@ func (v *Views) Initialize(binder AppBinder, cfg *config.Config) (*AppData, erro
 
 	v.actions = newViewActions(v)
 	v.op = newViewOperation(v)
+	v.reconnect = newDeviceReconnector(v)
+	v.notifier = newNotifier(v.cfg.Values.DesktopNotifications)
+	v.battery = newBatteryWatcher(v)
+	v.hooks = newDeviceHooks(v)
+	v.mpris = newMprisServer(v)
+	v.logger = newEventLogger(v)
+	v.defaults = newDefaultDeviceConnector(v)
+	v.daemon = newDaemonWatcher(v)
+	v.safe = newSafeMode(v)
+	v.agent = newPairingAgent(v)
 
 	v.pages = newViewPages()
 	v.layout = tview.NewFlex().
@@ -120,7 +152,7 @@ func (v *Views) Initialize(binder AppBinder, cfg *config.Config) (*AppData, erro
 		v.modals,
 		v.adapter,
 		v.device,
-		v.filepicker, v.progress,
+		v.filepicker, v.remoteFiles, v.progress,
 		v.player, v.audioProfiles,
 		v.network,
 	}
@@ -128,6 +160,7 @@ func (v *Views) Initialize(binder AppBinder, cfg *config.Config) (*AppData, erro
 	dontInit := map[viewInitializer]struct{}{}
 	if !v.app.Features().HasAny(appfeatures.FeatureSendFile, appfeatures.FeatureReceiveFile) {
 		dontInit[v.filepicker] = struct{}{}
+		dontInit[v.remoteFiles] = struct{}{}
 		dontInit[v.progress] = struct{}{}
 	}
 	if !v.app.Features().Has(appfeatures.FeatureMediaPlayer) {
@@ -151,8 +184,23 @@ func (v *Views) Initialize(binder AppBinder, cfg *config.Config) (*AppData, erro
 
 	v.menu.setHeader("", false)
 
+	if err := v.logger.start(); err != nil {
+		v.status.ErrorMessage(fmt.Errorf("cannot open the log file: %w", err))
+	}
+
+	if v.reconnect.enabled() {
+		go v.reconnect.event()
+	}
+	go v.battery.event()
+	go v.hooks.event()
+	go v.daemon.watch()
+	go v.safe.event()
+
+	v.startMpris()
+
 	v.kb.Initialize()
 	v.auth.setInitialized()
+	v.startAgent()
 
 	return &AppData{
 		Layout:       v.layout,
@@ -162,14 +210,18 @@ func (v *Views) Initialize(binder AppBinder, cfg *config.Config) (*AppData, erro
 		},
 		BeforeDrawFunc: func(t tcell.Screen) bool {
 			v.modals.resizeModal()
+			v.player.resizeProgress()
 			v.app.Suspend(t)
 
 			return false
 		},
+		AfterDrawFunc: func(t tcell.Screen) {
+			v.player.drawAlbumArt(t)
+		},
 		InputCapture: func(event *tcell.EventKey) *tcell.EventKey {
 			operation := v.kb.Key(event)
 
-			if e, ok := v.kb.IsNavigation(operation, event); ok {
+			if e, ok := v.kb.IsNavigation(operation, event); ok && !v.isTextInput(event) {
 				focused := v.app.GetFocused()
 				if focused != nil && focused.InputHandler() != nil {
 					focused.InputHandler()(e, nil)
@@ -190,6 +242,133 @@ func (v *Views) Initialize(binder AppBinder, cfg *config.Config) (*AppData, erro
 	}, nil
 }
 
+// Close stops the services which are run by the views, like the MPRIS2 server.
+func (v *Views) Close() {
+	if v.mpris != nil {
+		v.mpris.stop()
+	}
+
+	if v.agent != nil {
+		v.agent.stop()
+	}
+}
+
+// startAgent registers the pairing agent, which asks the user to enter the pincodes and passkeys
+// requested by devices.
+func (v *Views) startAgent() {
+	if err := v.agent.start(); err != nil {
+		v.status.ErrorMessage(fmt.Errorf("cannot register the pairing agent: %w", err))
+	}
+}
+
+// startMpris starts the MPRIS2 server, if it is enabled and media players are supported by the session.
+func (v *Views) startMpris() {
+	if !v.cfg.Values.Mpris || !v.app.Features().Has(appfeatures.FeatureMediaPlayer) {
+		return
+	}
+
+	if err := v.mpris.start(); err != nil {
+		v.status.ErrorMessage(fmt.Errorf("cannot start the MPRIS2 server: %w", err))
+	}
+}
+
+// restartSession restarts the session. The MPRIS2 server and the pairing agent are stopped along
+// with the session, and are started again once the session has been restarted, since the features
+// may have changed and the agent of the session replaces the default agent.
+func (v *Views) restartSession() error {
+	v.mpris.stop()
+	v.agent.stop()
+
+	if err := v.app.RestartSession(); err != nil {
+		return err
+	}
+
+	v.startMpris()
+	v.startAgent()
+
+	return nil
+}
+
+// reloadConfig reloads the configuration, and applies the updated values to all the views.
+func (v *Views) reloadConfig() error {
+	var err error
+
+	// The configuration values, the keybindings and the theme are read by the views
+	// while drawing, so they are replaced on the UI goroutine.
+	v.app.QueueDraw(func() {
+		noHelpDisplay := v.cfg.Values.NoHelpDisplay
+
+		if err = v.cfg.Reload(); err != nil {
+			return
+		}
+
+		v.kb = v.cfg.Values.Kb
+		v.kb.Initialize()
+
+		v.help.reload(noHelpDisplay)
+		v.reapplyTheme()
+	})
+	if err != nil {
+		return err
+	}
+
+	v.reconnect.reload()
+	v.battery.reload()
+	v.device.sortMode.Store(v.cfg.Values.DeviceSort)
+
+	return nil
+}
+
+// reloadTheme re-reads the theme, and re-applies it to the views.
+// The global theme configuration is replaced on the UI goroutine, since it is read while drawing.
+func (v *Views) reloadTheme() error {
+	var err error
+
+	v.app.QueueDraw(func() {
+		err = v.cfg.ReloadTheme()
+		v.reapplyTheme()
+	})
+
+	return err
+}
+
+// reapplyTheme re-applies the theme colors to the main views.
+// Since primitives store their colors when they are created, the colors are set again.
+// Modals are created when they are shown, and use the current theme.
+func (v *Views) reapplyTheme() {
+	background := theme.GetColor(theme.ThemeBackground)
+	menuBar := theme.GetColor(theme.ThemeMenuBar)
+	text := theme.GetColor(theme.ThemeText)
+
+	v.layout.SetBackgroundColor(background)
+	v.pages.SetBackgroundColor(background)
+	v.menuArea.SetBackgroundColor(background)
+	v.deviceArea.SetBackgroundColor(background)
+
+	v.menuSpacer.SetBackgroundColor(menuBar)
+	v.menu.bar.SetBackgroundColor(menuBar)
+	v.adapter.topStatus.SetBackgroundColor(menuBar)
+	v.adapter.topAdapterName.SetBackgroundColor(menuBar)
+	v.menu.modal.table.SetBorderColor(theme.GetColor(theme.ThemeBorder))
+	v.menu.modal.table.SetBackgroundColor(background)
+
+	v.device.table.SetBackgroundColor(background)
+
+	v.status.SetBackgroundColor(background)
+	v.status.InputField.SetLabelColor(text)
+	v.status.InputField.SetFieldTextColor(text)
+	v.status.InputField.SetBackgroundColor(background)
+	v.status.InputField.SetFieldBackgroundColor(background)
+	v.status.MessageBox.SetBackgroundColor(background)
+	v.status.Help.SetBackgroundColor(background)
+	v.status.Activity.SetTextColor(theme.GetColor(theme.ThemeStatusInfo))
+	v.status.Activity.SetBackgroundColor(background)
+
+	v.adapter.refreshHeader()
+	v.adapter.updateTopStatus()
+	v.device.list()
+}
+
 // Authorizer returns an authorization manager.
 func (v *Views) Authorizer() bluetooth.SessionAuthorizer {
 	return v.auth
@@ -197,13 +376,13 @@ func (v *Views) Authorizer() bluetooth.SessionAuthorizer {
 
 // arrangeViews arranges all the views and their layouts.
 func (v *Views) arrangeViews() *tview.Flex {
-	box := tview.NewBox().
+	v.menuSpacer = tview.NewBox().
 		SetBackgroundColor(theme.GetColor(theme.ThemeMenuBar))
 
 	menuArea := tview.NewFlex().
 		AddItem(v.adapter.topAdapterName, 0, 1, false).
 		AddItem(v.menu.bar, len(v.menu.bar.GetText(true)), 1, false).
-		AddItem(box, 1, 1, false).
+		AddItem(v.menuSpacer, 1, 1, false).
 		AddItem(v.adapter.topStatus, 0, 4, false)
 	menuArea.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 	menuArea.SetDrawFunc(func(_ tcell.Screen, x, y, width, height int) (int, int, int, int) {
@@ -221,19 +400,21 @@ func (v *Views) arrangeViews() *tview.Flex {
 		AddItem(nil, 1, 0, false).
 		AddItem(v.device.table, 0, 10, true)
 	flex.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	v.menuArea, v.deviceArea = menuArea, flex
 
 	v.pages.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 	v.pages.SetChangedFunc(func() {
 		page, _ := v.pages.GetFrontPage()
 
 		contexts := map[string]keybindings.Context{
-			devicePage.String():     keybindings.ContextDevice,
-			filePickerPage.String(): keybindings.ContextFiles,
-			progressPage.String():   keybindings.ContextProgress,
+			devicePage.String():      keybindings.ContextDevice,
+			filePickerPage.String():  keybindings.ContextFiles,
+			remoteFilesPage.String(): keybindings.ContextFiles,
+			progressPage.String():    keybindings.ContextProgress,
 		}
 
 		switch page {
-		case devicePage.String(), filePickerPage.String(), progressPage.String():
+		case devicePage.String(), filePickerPage.String(), remoteFilesPage.String(), progressPage.String():
 			v.pages.currentPage(page)
 			v.pages.currentContext(contexts[page])
 
@@ -285,6 +466,63 @@ func ignoreDefaultEvent(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
+// isTextInput returns whether the keyboard event is text which is typed into an input field or a search,
+// so that it is not handled as a navigation key, for example if the navigation keys are bound to letters.
+func (v *Views) isTextInput(event *tcell.EventKey) bool {
+	if event.Key() != tcell.KeyRune {
+		return false
+	}
+
+	if _, ok := v.app.GetFocused().(*tview.InputField); ok {
+		return true
+	}
+
+	return v.device.search.isActive() || v.filepicker.search.isActive()
+}
+
+// scrollTable moves the selection of the table by the configured number of lines if the mouse
+// wheel is scrolled over it, and returns whether the mouse event was handled. Rows which cannot be
+// selected are skipped, and the selection does not wrap around the ends of the table.
+func (v *Views) scrollTable(table *tview.Table, action tview.MouseAction, event *tcell.EventMouse) bool {
+	var step int
+
+	switch action {
+	case tview.MouseScrollUp:
+		step = -1
+
+	case tview.MouseScrollDown:
+		step = 1
+
+	default:
+		return false
+	}
+
+	if !table.InRect(event.Position()) {
+		return false
+	}
+
+	row, _ := table.GetSelection()
+	for range v.cfg.Values.MouseScrollLines {
+		next := row + step
+		for next >= 0 && next < table.GetRowCount() {
+			if cell := table.GetCell(next, 0); cell != nil && !cell.NotSelectable {
+				break
+			}
+
+			next += step
+		}
+		if next < 0 || next >= table.GetRowCount() {
+			break
+		}
+
+		row = next
+	}
+
+	table.Select(row, 0)
+
+	return true
+}
+
 // horizontalLine returns a box with a thick horizontal line.
 func horizontalLine() *tview.Box {
 	return tview.NewBox().
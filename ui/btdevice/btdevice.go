@@ -0,0 +1,122 @@
+package btdevice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+)
+
+// Find returns the device which matches the query, along with its adapter.
+// The devices are searched for in all adapters, or in the provided adapter, which
+// may be specified by either its name or its address.
+//
+// The query is matched in the following order:
+//   - The complete address of the device.
+//   - The name or alias of the device, ignoring case.
+//   - The beginning of the address of the device, or a part of the name or alias of the device, ignoring case.
+//
+// An error, which lists the matching devices, is returned if more than one device matches the query.
+func Find(session bluetooth.Session, adapter, query string) (bluetooth.DeviceData, bluetooth.AdapterData, error) {
+	type deviceMatch struct {
+		device  bluetooth.DeviceData
+		adapter bluetooth.AdapterData
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return bluetooth.DeviceData{}, bluetooth.AdapterData{}, fmt.Errorf("no device address or name was specified")
+	}
+
+	adapters, err := session.Adapters()
+	if err != nil {
+		return bluetooth.DeviceData{}, bluetooth.AdapterData{}, fmt.Errorf("no adapters were found: %w", err)
+	}
+
+	address, addressErr := bluetooth.ParseMAC(query)
+	addressPrefix := normalizeAddress(query)
+	name := strings.ToLower(query)
+
+	var exact, named, partial []deviceMatch
+	for _, a := range adapters {
+		if adapter != "" && a.UniqueName != adapter && a.Address.String() != adapter {
+			continue
+		}
+
+		devices, err := session.Adapter(a.AdapterAddress).Devices()
+		if err != nil {
+			continue
+		}
+
+		for _, device := range devices {
+			match := deviceMatch{device, a}
+			names := []string{
+				strings.ToLower(device.Name.Value()),
+				strings.ToLower(device.Alias.Value()),
+			}
+
+			switch {
+			case addressErr == nil && device.Address == address:
+				exact = append(exact, match)
+
+			case names[0] == name || names[1] == name:
+				named = append(named, match)
+
+			case addressPrefix != "" && strings.HasPrefix(normalizeAddress(device.Address.String()), addressPrefix),
+				strings.Contains(names[0], name) || strings.Contains(names[1], name):
+				partial = append(partial, match)
+			}
+		}
+	}
+
+	for _, matches := range [][]deviceMatch{exact, named, partial} {
+		switch len(matches) {
+		case 0:
+			continue
+
+		case 1:
+			return matches[0].device, matches[0].adapter, nil
+		}
+
+		candidates := make([]string, 0, len(matches))
+		for _, match := range matches {
+			candidates = append(candidates, fmt.Sprintf("  %s (%s, on %s)", DisplayName(match.device.DeviceEventData), match.device.Address.String(), match.adapter.UniqueName))
+		}
+
+		return bluetooth.DeviceData{}, bluetooth.AdapterData{}, fmt.Errorf(
+			"more than one device matches '%s':\n%s",
+			query, strings.Join(candidates, "\n"),
+		)
+	}
+
+	if addressErr == nil {
+		return bluetooth.DeviceData{}, bluetooth.AdapterData{}, fmt.Errorf("no device with address %s was found", address.String())
+	}
+
+	return bluetooth.DeviceData{}, bluetooth.AdapterData{}, fmt.Errorf("no device matching '%s' was found", query)
+}
+
+// normalizeAddress returns the hexadecimal digits of a (partial) device address in uppercase.
+// If the text cannot be a part of an address, an empty string is returned.
+func normalizeAddress(text string) string {
+	normalized := strings.ToUpper(strings.NewReplacer(":", "", "-", "").Replace(text))
+	if normalized == "" || strings.Trim(normalized, "0123456789ABCDEF") != "" {
+		return ""
+	}
+
+	return normalized
+}
+
+// DisplayName returns the name of the device, or its alias if it has no name.
+// If the device has neither, its address is returned.
+func DisplayName(device bluetooth.DeviceEventData) string {
+	if name, ok := device.Name.Get(); ok && name != "" {
+		return name
+	}
+
+	if alias, ok := device.Alias.Get(); ok && alias != "" {
+		return alias
+	}
+
+	return device.Address.String()
+}
@@ -0,0 +1,4 @@
+/*
+Package btdevice provides helpers to find Bluetooth devices and to display their names.
+*/
+package btdevice
@@ -2,14 +2,22 @@ package config
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/darkhz/bluetuith/ui/keybindings"
+	"github.com/darkhz/bluetuith/ui/theme"
 	"github.com/knadh/koanf/parsers/hjson"
 	"github.com/knadh/koanf/providers/cliflagv2"
 	"github.com/knadh/koanf/providers/file"
@@ -20,11 +28,14 @@ import (
 const (
 	configFile    = "bluetuith.conf"
 	oldConfigFile = "config"
+	stateFile     = "state.json"
 )
 
 // Config describes the configuration for the app.
 type Config struct {
-	path string
+	path   string
+	cliCtx *cli.Context
+	mu     sync.Mutex
 
 	Values Values
 }
@@ -49,11 +60,91 @@ func (c *Config) Load(k *koanf.Koanf, cliCtx *cli.Context) error {
 		return err
 	}
 
+	if err := c.loadState(k); err != nil {
+		return err
+	}
+
 	if err := k.Load(cliflagv2.Provider(cliCtx, "."), nil); err != nil {
 		return err
 	}
 
-	return k.UnmarshalWithConf("", &c.Values, koanf.UnmarshalConf{Tag: "koanf"})
+	c.cliCtx = cliCtx
+
+	return unmarshal(k, &c.Values)
+}
+
+// Reload re-reads and validates the configuration from the configuration file and
+// the command-line flags. If the configuration is invalid, the existing configuration
+// is retained and an error is returned. Values which cannot be decoded are reset to their
+// defaults, and are reported in the DecodeErrors of the new configuration.
+// Since the values and the global theme configuration are replaced, it should be called
+// from the UI goroutine, so that they are not read while they are being replaced.
+func (c *Config) Reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cfgfile, err := c.FilePath(configFile)
+	if err != nil {
+		return err
+	}
+
+	k := koanf.New(".")
+	if err := k.Load(file.Provider(cfgfile), hjson.Parser()); err != nil {
+		return err
+	}
+
+	if err := c.loadState(k); err != nil {
+		return err
+	}
+
+	if c.cliCtx != nil {
+		if err := k.Load(cliflagv2.Provider(c.cliCtx, "."), nil); err != nil {
+			return err
+		}
+	}
+
+	var values Values
+	if err := unmarshal(k, &values); err != nil {
+		return err
+	}
+
+	currentTheme := maps.Clone(theme.ThemeConfig)
+	if err := values.validateValues(); err != nil {
+		theme.ThemeConfig = currentTheme
+		return err
+	}
+
+	// The adapter cannot be changed at runtime, so retain the already selected adapter.
+	values.Adapter = c.Values.Adapter
+	values.SelectedAdapter = c.Values.SelectedAdapter
+
+	c.Values = values
+
+	return nil
+}
+
+// ReloadTheme re-reads the theme file, and applies the theme configuration on top of it.
+// If the theme file cannot be loaded, the built-in theme is used and the error is returned.
+// Since the global theme configuration is replaced, it should be called from the UI goroutine,
+// so that it is not read while it is being replaced.
+func (c *Config) ReloadTheme() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	currentTheme := maps.Clone(theme.ThemeConfig)
+	theme.ResetThemeConfig()
+
+	if err := c.Values.validateThemeFile(); err != nil {
+		theme.ThemeConfig = currentTheme
+		return err
+	}
+
+	if err := c.Values.validateTheme(); err != nil {
+		theme.ThemeConfig = currentTheme
+		return err
+	}
+
+	return c.Values.ThemeFileError
 }
 
 // ValidateValues validates the configuration values.
@@ -61,6 +152,35 @@ func (c *Config) ValidateValues() error {
 	return c.Values.validateValues()
 }
 
+// Check validates all configuration values which do not require a bluetooth session, and
+// returns every problem that was found instead of only the first one. Keybindings which cannot
+// be parsed, which are otherwise ignored, and errors in the theme file are reported as well.
+func (c *Config) Check() []error {
+	errs := slices.Clone(c.Values.DecodeErrors)
+
+	for _, validate := range c.Values.validators() {
+		if err := validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.Values.Kb != nil {
+		errs = append(errs, c.Values.Kb.InvalidBindings(c.Values.Keybindings)...)
+	}
+
+	if err := c.Values.ThemeFileError; err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// DecodeError returns a single error describing all values which could not be decoded,
+// or nil if all values were decoded.
+func (c *Config) DecodeError() error {
+	return errors.Join(c.Values.DecodeErrors...)
+}
+
 // ValidateSessionValues validates all configuration values that require a bluetooth session.
 func (c *Config) ValidateSessionValues(session bluetooth.Session) error {
 	return c.Values.validateSessionValues(session)
@@ -157,32 +277,200 @@ func (c *Config) GenerateAndSave(currentCfg *koanf.Koanf) (bool, error) {
 
 	cfg.Delete("generate")
 
+	return parsedOldCfg, c.write(cfg)
+}
+
+// Save stores the value of the provided key in the state file, which is overlaid on the
+// values of the configuration file when the configuration is loaded. The configuration file
+// itself is never modified, so that its comments and layout are preserved.
+func (c *Config) Save(key string, value any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path, err := c.StateFilePath(stateFile)
+	if err != nil {
+		return err
+	}
+
+	state, err := readState(path)
+	if err != nil {
+		return err
+	}
+
+	state[key] = value
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// loadState overlays the values from the state file on the provided configuration.
+func (c *Config) loadState(k *koanf.Koanf) error {
+	path, err := c.StateFilePath(stateFile)
+	if err != nil {
+		return err
+	}
+
+	state, err := readState(path)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range state {
+		// Setting a value merges it with any existing object values, so delete
+		// the key first to replace the value of the configuration file.
+		k.Delete(key)
+		if err := k.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readState reads the values from the state file at the provided path.
+func readState(path string) (map[string]any, error) {
+	state := make(map[string]any)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state, nil
+		}
+
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return state, nil
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("cannot parse the state file: %w", err)
+	}
+
+	return state, nil
+}
+
+// Dump returns the keybindings and theme of the current configuration, in the same format
+// as the configuration file. If minimal is set, only values that differ from the defaults are included.
+func (c *Config) Dump(minimal bool) ([]byte, error) {
+	kb := c.Values.Kb
+	if kb == nil {
+		kb = keybindings.NewKeybindings()
+	}
+
+	k := koanf.New(".")
+	if err := k.Set("keybindings", kb.Export(minimal)); err != nil {
+		return nil, err
+	}
+	if err := k.Set("theme", theme.ExportThemeConfig(minimal)); err != nil {
+		return nil, err
+	}
+
+	return hjson.Parser().Marshal(k.Raw())
+}
+
+// write marshals and writes the provided configuration to the configuration file.
+func (c *Config) write(cfg *koanf.Koanf) error {
 	data, err := hjson.Parser().Marshal(cfg.Raw())
 	if err != nil {
-		return parsedOldCfg, err
+		return err
 	}
 
 	conf, err := c.FilePath(configFile)
 	if err != nil {
-		return parsedOldCfg, err
+		return err
 	}
 
 	f, err := os.OpenFile(conf, os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 	if err != nil {
-		return parsedOldCfg, err
+		return err
 	}
 	defer f.Close()
 
-	_, err = f.Write(data)
-	if err != nil {
-		return parsedOldCfg, err
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// unmarshal decodes the configuration into the provided values. If the configuration cannot be decoded
+// as a whole, each value is decoded separately, and the values which cannot be decoded are removed from
+// the configuration, so that their defaults are used instead. An error describing the expected type is
+// recorded in the DecodeErrors of the values for each value that was reset.
+func unmarshal(k *koanf.Koanf, values *Values) error {
+	conf := koanf.UnmarshalConf{Tag: "koanf"}
+
+	values.DecodeErrors = nil
+	if err := k.UnmarshalWithConf("", values, conf); err == nil {
+		return nil
+	}
+
+	var decodeErrors []error
+
+	fields := reflect.TypeFor[Values]()
+	for i := range fields.NumField() {
+		field := fields.Field(i)
+
+		key := field.Tag.Get("koanf")
+		if key == "" || !k.Exists(key) {
+			continue
+		}
+
+		value := reflect.New(field.Type)
+		if err := k.UnmarshalWithConf(key, value.Interface(), conf); err == nil {
+			continue
+		}
+
+		decodeErrors = append(decodeErrors,
+			fmt.Errorf("the value of '%s' is %s, but %s is expected, the default value is used",
+				key, typeName(reflect.TypeOf(k.Get(key))), typeName(field.Type),
+			),
+		)
+		k.Delete(key)
 	}
 
-	if err := f.Sync(); err != nil {
-		return parsedOldCfg, err
+	*values = Values{}
+	if err := k.UnmarshalWithConf("", values, conf); err != nil {
+		return err
+	}
+
+	values.DecodeErrors = decodeErrors
+
+	return nil
+}
+
+// typeName returns a description of the provided type, as it is written in the configuration file.
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "empty"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+
+	case reflect.String:
+		return "a string"
+
+	case reflect.Slice, reflect.Array:
+		return "a list"
+
+	case reflect.Map, reflect.Struct:
+		return "an object"
 	}
 
-	return parsedOldCfg, nil
+	return "a " + t.String()
 }
 
 // parseOldConfig parses and stores values from the old configuration.
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/knadh/koanf/parsers/hjson"
+	"github.com/knadh/koanf/v2"
+)
+
+// rawConfig is a koanf provider which provides the configuration from memory.
+type rawConfig []byte
+
+func (r rawConfig) ReadBytes() ([]byte, error) {
+	return r, nil
+}
+
+func (r rawConfig) Read() (map[string]any, error) {
+	return hjson.Parser().Unmarshal(r)
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		errors []string
+		check  func(values Values) bool
+	}{
+		{
+			name:   "valid values",
+			config: `{battery-threshold: 20, mpris: true, favorite-devices: ["AA:BB:CC:DD:EE:FF"]}`,
+			check: func(values Values) bool {
+				return values.BatteryThreshold == 20 && values.Mpris &&
+					slices.Equal(values.FavoriteDevices, []string{"AA:BB:CC:DD:EE:FF"})
+			},
+		},
+		{
+			name:   "string instead of a number",
+			config: `{battery-threshold: "low", mpris: true}`,
+			errors: []string{
+				"the value of 'battery-threshold' is a string, but a number is expected, the default value is used",
+			},
+			check: func(values Values) bool {
+				return values.BatteryThreshold == 0 && values.Mpris
+			},
+		},
+		{
+			name:   "list instead of a string",
+			config: `{device-sort: ["name", "rssi"], theme-file: "theme.conf"}`,
+			errors: []string{
+				"the value of 'device-sort' is a list, but a string is expected, the default value is used",
+			},
+			check: func(values Values) bool {
+				return values.DeviceSort == "" && values.ThemeFile == "theme.conf"
+			},
+		},
+		{
+			name:   "multiple invalid values",
+			config: `{scan-timeout: {seconds: 10}, file-bookmarks: {home: "~"}, hook-timeout: 5}`,
+			errors: []string{
+				"the value of 'scan-timeout' is an object, but a number is expected, the default value is used",
+				"the value of 'file-bookmarks' is an object, but a list is expected, the default value is used",
+			},
+			check: func(values Values) bool {
+				return values.ScanTimeout == 0 && values.FileBookmarks == nil && values.HookTimeout == 5
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k := koanf.New(".")
+			if err := k.Load(rawConfig(test.config), hjson.Parser()); err != nil {
+				t.Fatalf("cannot load the configuration: %v", err)
+			}
+
+			values := Values{DecodeErrors: []error{nil}}
+			if err := unmarshal(k, &values); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			errors := make([]string, 0, len(values.DecodeErrors))
+			for _, err := range values.DecodeErrors {
+				errors = append(errors, err.Error())
+			}
+			if !slices.Equal(errors, test.errors) {
+				t.Errorf("got errors %q, want %q", errors, test.errors)
+			}
+
+			if !test.check(values) {
+				t.Errorf("unexpected values: %+v", values)
+			}
+		})
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		t    reflect.Type
+		want string
+	}{
+		{nil, "empty"},
+		{reflect.TypeFor[bool](), "a boolean"},
+		{reflect.TypeFor[int](), "a number"},
+		{reflect.TypeFor[uint32](), "a number"},
+		{reflect.TypeFor[float64](), "a number"},
+		{reflect.TypeFor[string](), "a string"},
+		{reflect.TypeFor[[]string](), "a list"},
+		{reflect.TypeFor[[2]int](), "a list"},
+		{reflect.TypeFor[map[string]any](), "an object"},
+		{reflect.TypeFor[struct{}](), "an object"},
+		{reflect.TypeFor[*int](), "a *int"},
+	}
+
+	for _, test := range tests {
+		if got := typeName(test.t); got != test.want {
+			t.Errorf("typeName(%v) = %q, want %q", test.t, got, test.want)
+		}
+	}
+}
+
+func TestValidateAgentCapability(t *testing.T) {
+	tests := []struct {
+		capability string
+		want       string
+		valid      bool
+	}{
+		{"", "KeyboardDisplay", true},
+		{"DisplayYesNo", "DisplayYesNo", true},
+		{"noinputnooutput", "NoInputNoOutput", true},
+		{"KeyboardOnly", "KeyboardOnly", true},
+		{"Keyboard", "Keyboard", false},
+	}
+
+	for _, test := range tests {
+		values := Values{AgentCapability: test.capability}
+		if err := values.validateAgentCapability(); (err == nil) != test.valid {
+			t.Errorf("validateAgentCapability(%q) returned error %v, want valid %v", test.capability, err, test.valid)
+		}
+
+		if values.AgentCapability != test.want {
+			t.Errorf("validateAgentCapability(%q) set %q, want %q", test.capability, values.AgentCapability, test.want)
+		}
+	}
+}
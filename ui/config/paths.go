@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StateDir returns the directory to store the application state in, which is
+// "$XDG_STATE_HOME/bluetuith", or "~/.local/state/bluetuith" if the variable is not set.
+// The directory is created if it does not exist.
+func StateDir() (string, error) {
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CacheDir returns the directory to store non-essential files in, which is
+// "$XDG_CACHE_HOME/bluetuith", or "~/.cache/bluetuith" if the variable is not set.
+// The directory is created if it does not exist.
+func CacheDir() (string, error) {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// StateFilePath returns the absolute path for the given file in the state directory.
+// Older versions stored such files in the configuration directory, so if the file only
+// exists there, it is moved to the state directory. If it cannot be moved, the old path
+// is returned instead.
+func (c *Config) StateFilePath(name string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) || c.path == "" {
+		return path, nil
+	}
+
+	oldPath := filepath.Join(c.path, name)
+	if _, err := os.Stat(oldPath); err != nil {
+		return path, nil
+	}
+
+	if err := os.Rename(oldPath, path); err != nil {
+		return oldPath, nil
+	}
+
+	return path, nil
+}
+
+// xdgDir returns the application directory within the base directory specified by the
+// environment variable. Relative paths are invalid according to the XDG Base Directory
+// specification, so if the variable is not set to an absolute path, the fallback path
+// within the home directory is used.
+func xdgDir(env, fallback string) (string, error) {
+	base := os.Getenv(env)
+	if base == "" || !filepath.IsAbs(base) {
+		homedir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		base = filepath.Join(homedir, fallback)
+	}
+
+	dir := filepath.Join(base, "bluetuith")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
@@ -3,51 +3,159 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/bluetuith-org/bluetooth-classic/api/bluetooth"
+	"github.com/knadh/koanf/parsers/hjson"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
 
+	"github.com/darkhz/bluetuith/ui/btdevice"
 	"github.com/darkhz/bluetuith/ui/keybindings"
 	"github.com/darkhz/bluetuith/ui/theme"
 )
 
+// AudioBackends lists the sound servers which can be used to manage the audio profiles of devices.
+// The first backend is the default, and selects the available sound server automatically.
+var AudioBackends = []string{
+	"auto",
+	"pulseaudio",
+	"pipewire",
+}
+
+// AgentCapabilities lists the IO capabilities which the pairing agent can be registered with.
+// The capability determines how BlueZ authenticates pairing requests, for example whether a passkey
+// is displayed, entered or not required. The first capability is the default.
+var AgentCapabilities = []string{
+	"KeyboardDisplay",
+	"DisplayOnly",
+	"DisplayYesNo",
+	"KeyboardOnly",
+	"NoInputNoOutput",
+}
+
 // Values describes the possible configuration values that a user can
 // modify and supply to the application.
 type Values struct {
-	Adapter       string            `koanf:"adapter"`
-	ReceiveDir    string            `koanf:"receive-dir"`
-	GsmApn        string            `koanf:"gsm-apn"`
-	GsmNumber     string            `koanf:"gsm-number"`
-	AdapterStates string            `koanf:"adapter-states"`
-	ConnectAddr   string            `koanf:"connect-bdaddr"`
-	NoWarning     bool              `koanf:"no-warning"`
-	NoHelpDisplay bool              `koanf:"no-help-display"`
-	ConfirmOnQuit bool              `koanf:"confirm-on-quit"`
-	Theme         map[string]string `koanf:"theme"`
-	Keybindings   map[string]string `koanf:"keybindings"`
+	Adapter                string            `koanf:"adapter"`
+	ReceiveDir             string            `koanf:"receive-dir"`
+	ReceiveDirPerDevice    bool              `koanf:"receive-dir-per-device"`
+	VerifyReceived         bool              `koanf:"verify-received"`
+	ReceiveAcceptTrusted   bool              `koanf:"receive-accept-trusted"`
+	GsmApn                 string            `koanf:"gsm-apn"`
+	GsmNumber              string            `koanf:"gsm-number"`
+	NetworkApns            map[string]string `koanf:"network-apns"`
+	AdapterStates          string            `koanf:"adapter-states"`
+	Power                  string            `koanf:"power"`
+	Pairable               string            `koanf:"pairable"`
+	Discoverable           string            `koanf:"discoverable"`
+	Scan                   string            `koanf:"scan"`
+	ConnectAddr            string            `koanf:"connect-bdaddr"`
+	RememberLastDevice     bool              `koanf:"remember-last-device"`
+	LastConnectedDevice    string            `koanf:"last-connected-device"`
+	NoWarning              bool              `koanf:"no-warning"`
+	NoHelpDisplay          bool              `koanf:"no-help-display"`
+	ConfirmOnQuit          bool              `koanf:"confirm-on-quit"`
+	ConfirmOnDisconnect    bool              `koanf:"confirm-on-disconnect"`
+	ConfirmOnBlock         bool              `koanf:"confirm-on-block"`
+	NoConfirmOnRemove      bool              `koanf:"no-confirm-on-remove"`
+	DesktopNotifications   bool              `koanf:"desktop-notifications"`
+	Mpris                  bool              `koanf:"mpris"`
+	AlbumArt               bool              `koanf:"album-art"`
+	BatteryThreshold       int               `koanf:"battery-threshold"`
+	DeviceSort             string            `koanf:"device-sort"`
+	MinRSSI                int               `koanf:"min-rssi"`
+	DiscoverableTimeout    int               `koanf:"discoverable-timeout"`
+	PairableTimeout        int               `koanf:"pairable-timeout"`
+	ScanTimeout            int               `koanf:"scan-timeout"`
+	ScanNameWait           int               `koanf:"scan-name-wait"`
+	AutoReconnect          string            `koanf:"auto-reconnect"`
+	ConnectRetries         int               `koanf:"connect-retries"`
+	ConnectRetryDelay      int               `koanf:"connect-retry-delay"`
+	KeySequenceTimeout     int               `koanf:"key-sequence-timeout"`
+	KeybindingPreset       string            `koanf:"keybinding-preset"`
+	FileBookmarks          []string          `koanf:"file-bookmarks"`
+	FilePickerRememberDir  bool              `koanf:"file-picker-remember-dir"`
+	FilePickerLastDir      string            `koanf:"file-picker-last-dir"`
+	LogFile                string            `koanf:"log-file"`
+	LogMaxSize             int               `koanf:"log-max-size"`
+	LogNoAddresses         bool              `koanf:"log-no-addresses"`
+	VendorFile             string            `koanf:"vendor-file"`
+	FavoriteDevices        []string          `koanf:"favorite-devices"`
+	DefaultDevices         map[string]string `koanf:"default-devices"`
+	PairingAllowlist       []string          `koanf:"pairing-allowlist"`
+	PairingBlocklist       []string          `koanf:"pairing-blocklist"`
+	SafeMode               bool              `koanf:"safe-mode"`
+	AgentCapability        string            `koanf:"agent-capability"`
+	ThemeFile              string            `koanf:"theme-file"`
+	ColorMode              string            `koanf:"color-mode"`
+	AudioBackend           string            `koanf:"audio-backend"`
+	DisableMouse           bool              `koanf:"disable-mouse"`
+	MouseScrollLines       int               `koanf:"mouse-scroll-lines"`
+	MenuRememberLast       bool              `koanf:"menu-remember-last"`
+	OnConnect              string            `koanf:"on-connect"`
+	OnDisconnect           string            `koanf:"on-disconnect"`
+	HookTimeout            int               `koanf:"hook-timeout"`
+	PreferredAudioProfiles map[string]string `koanf:"preferred-audio-profiles"`
+	Theme                  map[string]string `koanf:"theme"`
+	Keybindings            map[string]string `koanf:"keybindings"`
 
 	AdapterStatesMap      map[string]string
 	SelectedAdapter       *bluetooth.AdapterData
 	AutoConnectDeviceAddr bluetooth.MacAddress
+	AutoReconnectAddrs    []bluetooth.MacAddress
+	Vendors               map[string]string
 	Kb                    *keybindings.Keybindings
+	ThemeFileError        error
+	DecodeErrors          []error
 }
 
 // validateValues validates all configuration values.
 func (v *Values) validateValues() error {
-	for _, validate := range []func() error{
+	for _, validate := range v.validators() {
+		if err := validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validators returns the validators of all configuration values, in the order in which they are applied.
+func (v *Values) validators() []func() error {
+	return []func() error{
 		v.validateKeybindings,
 		v.validateAdapterStates,
 		v.validateConnectBDAddr,
+		v.validateAutoReconnect,
+		v.validateFavoriteDevices,
+		v.validateDefaultDevices,
+		v.validatePreferredAudioProfiles,
+		v.validatePairingLists,
+		v.validateAgentCapability,
 		v.validateReceiveDir,
+		v.validateFileBookmarks,
+		v.validateLogFile,
+		v.validateVendorFile,
 		v.validateGsm,
+		v.validateNetworkApns,
+		v.validateDeviceSort,
+		v.validateAdapterTimeouts,
+		v.validateBatteryThreshold,
+		v.validateHookTimeout,
+		v.validateConnectRetries,
+		v.validateMouseScrollLines,
+		v.validateMinRSSI,
+		v.validateScanNameWait,
+		v.validateColorMode,
+		v.validateAudioBackend,
+		v.validateThemeFile,
 		v.validateTheme,
-	} {
-		if err := validate(); err != nil {
-			return err
-		}
 	}
-
-	return nil
 }
 
 // validateSessionValues validates all configuration values that require a bluetooth session.
@@ -67,8 +175,15 @@ func (v *Values) validateSessionValues(session bluetooth.Session) error {
 // validateAdapter validates if the adapter specified by the user exists in the system.
 func (v *Values) validateAdapter(session bluetooth.Session) error {
 	adapters, err := session.Adapters()
+	if len(adapters) == 0 && v.Adapter == "" {
+		// The UI waits for an adapter to be added. Since sessions can report
+		// the absence of adapters as an error, the error is not returned here.
+		v.SelectedAdapter = &bluetooth.AdapterData{}
+		return nil
+	}
+
 	if err != nil {
-		return fmt.Errorf("no adapters were found: %w", err)
+		return fmt.Errorf("%s: The adapters could not be listed: %w", v.Adapter, err)
 	}
 
 	if v.Adapter == "" {
@@ -87,144 +202,234 @@ func (v *Values) validateAdapter(session bluetooth.Session) error {
 }
 
 // validateDeviceExists validates if a device specified by the user exists within any adapter in the system.
+// The device may be specified by its address or name, or a unique part of either.
 func (v *Values) validateDeviceExists(session bluetooth.Session) error {
-	if v.AutoConnectDeviceAddr.IsNil() {
+	if v.ConnectAddr == "" {
 		return nil
 	}
 
-	deviceAddr := v.AutoConnectDeviceAddr
-
-	adapters, err := session.Adapters()
+	device, adapter, err := btdevice.Find(session, v.Adapter, v.ConnectAddr)
 	if err != nil {
-		return fmt.Errorf("no adapters were found: %w", err)
+		return err
 	}
 
-	adapterlist := make([]string, 0, len(adapters))
-	for _, adapter := range adapters {
-		if v.Adapter != "" && adapter.UniqueName != v.Adapter {
-			continue
+	v.AutoConnectDeviceAddr = device.Address
+	v.SelectedAdapter = &adapter
+
+	return nil
+}
+
+// validateKeybindings validates the keybindings and the key sequence timeout.
+func (v *Values) validateKeybindings() error {
+	v.Kb = keybindings.NewKeybindings()
+
+	switch {
+	case v.KeySequenceTimeout < 0:
+		return fmt.Errorf("provided key sequence timeout '%d' is incorrect.\nThe timeout must be a positive number of milliseconds", v.KeySequenceTimeout)
+
+	case v.KeySequenceTimeout > 0:
+		v.Kb.SetSequenceTimeout(time.Duration(v.KeySequenceTimeout) * time.Millisecond)
+	}
+
+	if v.KeybindingPreset != "" {
+		preset := strings.ToLower(v.KeybindingPreset)
+		if !slices.Contains(keybindings.Presets, preset) {
+			return fmt.Errorf(
+				"provided keybinding preset '%s' is incorrect.\nValid presets are '%s'",
+				v.KeybindingPreset,
+				strings.Join(keybindings.Presets, ", "),
+			)
 		}
 
-		adapterlist = append(adapterlist, adapter.UniqueName)
-		devices, err := session.Adapter(adapter.AdapterAddress).Devices()
-		if err != nil {
-			continue
+		v.KeybindingPreset = preset
+		if err := v.Kb.ApplyPreset(preset); err != nil {
+			return err
 		}
+	}
+
+	return v.Kb.Validate(v.Keybindings)
+}
+
+// AdapterStates lists the adapter states which can be set on application launch,
+// in the order in which they are applied.
+var AdapterStates = []string{
+	"powered",
+	"pairable",
+	"discoverable",
+	"scan",
+}
+
+// validateAdapterStates validates the adapter states to be set on application launch.
+// The states are read from the 'adapter-states' option, and from the 'power', 'pairable',
+// 'discoverable' and 'scan' options, which take precedence. The result is stored in the
+// 'AdapterStatesMap' property, which maps each adapter state to "yes" or "no".
+// The states are applied in the order of [AdapterStates].
+func (v *Values) validateAdapterStates() error {
+	properties := make(map[string]string)
+
+	if v.AdapterStates != "" {
+		for ps := range strings.SplitSeq(v.AdapterStates, ",") {
+			property := strings.FieldsFunc(ps, func(r rune) bool {
+				return r == ' ' || r == ':'
+			})
+			if len(property) != 2 {
+				return fmt.Errorf(
+					"provided property:state format '%s' is incorrect",
+					ps,
+				)
+			}
+
+			if !slices.Contains(AdapterStates, property[0]) {
+				return fmt.Errorf(
+					"provided property '%s' is incorrect.\nValid properties are '%s'",
+					property[0],
+					strings.Join(AdapterStates, ", "),
+				)
+			}
 
-		for _, device := range devices {
-			if device.Address == deviceAddr {
-				v.SelectedAdapter = &adapter
-				return nil
+			state, err := parseAdapterState(property[0], property[1])
+			if err != nil {
+				return err
 			}
+
+			properties[property[0]] = state
 		}
+	}
 
-		if v.Adapter != "" && adapter.UniqueName == v.Adapter {
-			break
+	for property, state := range map[string]string{
+		"powered":      v.Power,
+		"pairable":     v.Pairable,
+		"discoverable": v.Discoverable,
+		"scan":         v.Scan,
+	} {
+		if state == "" {
+			continue
 		}
+
+		state, err := parseAdapterState(property, state)
+		if err != nil {
+			return err
+		}
+
+		properties[property] = state
 	}
 
-	return fmt.Errorf("no device with address %s found on adapters %s", deviceAddr.String(), strings.Join(adapterlist, ", "))
+	v.AdapterStatesMap = properties
+
+	return nil
 }
 
-// validateKeybindings validates the keybindings.
-func (v *Values) validateKeybindings() error {
-	v.Kb = keybindings.NewKeybindings()
-	if len(v.Keybindings) == 0 {
-		return nil
+// parseAdapterState normalizes the state of an adapter property to "yes" or "no".
+func parseAdapterState(property, state string) (string, error) {
+	switch strings.ToLower(state) {
+	case "yes", "y", "on":
+		return "yes", nil
+
+	case "no", "n", "off":
+		return "no", nil
 	}
 
-	return v.Kb.Validate(v.Keybindings)
+	return "", fmt.Errorf(
+		"provided state '%s' for property '%s' is incorrect.\nValid states are '%s'",
+		state, property,
+		strings.Join([]string{"yes", "no", "y", "n", "on", "off"}, ", "),
+	)
 }
 
-// validateAdapterStates validates the adapter states to be set on application launch.
-// The result is appended to the 'AdapterStatesMap' property, which includes:
-//   - A 'sequence' key with a comma-separated value of states to be toggled in order.
-//   - Each adapter state as subsequent keys, with values of "yes"/"no" to determine how each adapter state should be toggled.
-func (v *Values) validateAdapterStates() error {
-	if v.AdapterStates == "" {
+// validateConnectBDAddr validates the address or name of the device that has to be automatically connected
+// to on application launch. The device is resolved once a session is available.
+func (v *Values) validateConnectBDAddr() error {
+	v.ConnectAddr = strings.TrimSpace(v.ConnectAddr)
+
+	return nil
+}
+
+// validateAutoReconnect validates the addresses of the devices to automatically reconnect to.
+func (v *Values) validateAutoReconnect() error {
+	if v.AutoReconnect == "" {
 		return nil
 	}
 
-	properties := make(map[string]string)
-	propertyAndStates := strings.Split(v.AdapterStates, ",")
+	for address := range strings.SplitSeq(v.AutoReconnect, ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
 
-	propertyOptions := []string{
-		"powered",
-		"scan",
-		"discoverable",
-		"pairable",
+		deviceAddr, err := bluetooth.ParseMAC(address)
+		if err != nil {
+			return fmt.Errorf("invalid address format: %s", address)
+		}
+
+		v.AutoReconnectAddrs = append(v.AutoReconnectAddrs, deviceAddr)
 	}
 
-	stateOptions := []string{
-		"yes", "no",
-		"y", "n",
-		"on", "off",
+	return nil
+}
+
+// validateFavoriteDevices validates the addresses of the favorite devices.
+func (v *Values) validateFavoriteDevices() error {
+	favorites, err := parseAddressList(v.FavoriteDevices)
+	if err != nil {
+		return err
 	}
 
-	sequence := []string{}
+	v.FavoriteDevices = favorites
 
-	for _, ps := range propertyAndStates {
-		property := strings.FieldsFunc(ps, func(r rune) bool {
-			return r == ' ' || r == ':'
-		})
-		if len(property) != 2 {
-			return fmt.Errorf(
-				"provided property:state format '%s' is incorrect",
-				ps,
-			)
-		}
+	return nil
+}
 
-		for _, prop := range propertyOptions {
-			if property[0] == prop {
-				goto CheckState
-			}
+// validateDefaultDevices validates the addresses of the default devices for each adapter.
+func (v *Values) validateDefaultDevices() error {
+	for adapter, address := range v.DefaultDevices {
+		deviceAddr, err := bluetooth.ParseMAC(strings.TrimSpace(address))
+		if err != nil {
+			return fmt.Errorf("invalid address format for the default device of %s: %s", adapter, address)
 		}
-		return fmt.Errorf(
-			"provided property '%s' is incorrect.\nValid properties are '%s'",
-			property[0],
-			strings.Join(propertyOptions, ", "),
-		)
 
-	CheckState:
-		state := property[1]
-		switch state {
-		case "yes", "y", "on":
-			state = "yes"
+		v.DefaultDevices[adapter] = deviceAddr.String()
+	}
 
-		case "no", "n", "off":
-			state = "no"
+	return nil
+}
 
-		default:
-			return fmt.Errorf(
-				"provided state '%s' for property '%s' is incorrect.\nValid states are '%s'",
-				state, property[0],
-				strings.Join(stateOptions, ", "),
-			)
+// validatePreferredAudioProfiles validates the addresses of the devices with a preferred audio profile.
+func (v *Values) validatePreferredAudioProfiles() error {
+	profiles := make(map[string]string, len(v.PreferredAudioProfiles))
+	for address, profile := range v.PreferredAudioProfiles {
+		deviceAddr, err := bluetooth.ParseMAC(strings.TrimSpace(address))
+		if err != nil {
+			return fmt.Errorf("invalid address format for the preferred audio profile %s: %s", profile, address)
 		}
 
-		properties[property[0]] = state
-		sequence = append(sequence, property[0])
+		profiles[deviceAddr.String()] = strings.TrimSpace(profile)
 	}
 
-	properties["sequence"] = strings.Join(sequence, ",")
-	v.AdapterStatesMap = properties
+	v.PreferredAudioProfiles = profiles
 
 	return nil
 }
 
-// validateConnectBDAddr validates the device address that has to be automatically connected to on application
-// launch.
-func (v *Values) validateConnectBDAddr() error {
-	if v.ConnectAddr == "" {
-		return nil
+// validatePairingLists validates the addresses of the devices whose pairing and authorization
+// requests are automatically accepted or rejected.
+func (v *Values) validatePairingLists() error {
+	allowlist, err := parseAddressList(v.PairingAllowlist)
+	if err != nil {
+		return err
 	}
 
-	deviceAddr, err := bluetooth.ParseMAC(v.ConnectAddr)
+	blocklist, err := parseAddressList(v.PairingBlocklist)
 	if err != nil {
-		return fmt.Errorf("invalid address format: %s", v.ConnectAddr)
+		return err
 	}
 
-	v.AutoConnectDeviceAddr = deviceAddr
+	for _, address := range allowlist {
+		if slices.Contains(blocklist, address) {
+			return fmt.Errorf("%s: Device cannot be in both the pairing allowlist and blocklist", address)
+		}
+	}
+
+	v.PairingAllowlist, v.PairingBlocklist = allowlist, blocklist
 
 	return nil
 }
@@ -243,6 +448,119 @@ func (v *Values) validateReceiveDir() error {
 	return nil
 }
 
+// validateFileBookmarks validates the bookmarked directories of the file picker.
+// The directories are not checked for existence, since they may be on removable media.
+func (v *Values) validateFileBookmarks() error {
+	bookmarks := make([]string, 0, len(v.FileBookmarks))
+
+	for _, path := range v.FileBookmarks {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("provided file bookmark '%s' is incorrect.\nThe bookmarked directory must be an absolute path", path)
+		}
+
+		if path = filepath.Clean(path); !slices.Contains(bookmarks, path) {
+			bookmarks = append(bookmarks, path)
+		}
+	}
+
+	v.FileBookmarks = bookmarks
+
+	return nil
+}
+
+// validateLogFile validates the log file path and the maximum size of the log file.
+// If only a file name is provided, the log file is stored in the state directory.
+func (v *Values) validateLogFile() error {
+	if v.LogFile == "" {
+		return nil
+	}
+
+	if filepath.Base(v.LogFile) == v.LogFile {
+		stateDir, err := StateDir()
+		if err != nil {
+			return err
+		}
+
+		v.LogFile = filepath.Join(stateDir, v.LogFile)
+	}
+
+	if statpath, err := os.Stat(filepath.Dir(v.LogFile)); err != nil || !statpath.IsDir() {
+		return fmt.Errorf("%s: Directory is not accessible", filepath.Dir(v.LogFile))
+	}
+
+	if statpath, err := os.Stat(v.LogFile); err == nil && statpath.IsDir() {
+		return fmt.Errorf("provided log file '%s' is incorrect.\nThe log file must not be a directory", v.LogFile)
+	}
+
+	switch {
+	case v.LogMaxSize == 0:
+		v.LogMaxSize = 1024
+
+	case v.LogMaxSize < 0:
+		return fmt.Errorf("provided log size '%d' is incorrect.\nThe size must be a positive number of kilobytes", v.LogMaxSize)
+	}
+
+	return nil
+}
+
+// validateVendorFile loads the manufacturer names of device addresses from the vendor file, if specified.
+// Each line of the file holds the first three bytes of an address (the OUI), followed by the name of the
+// manufacturer, like "00:1A:7D cyber-blue(HK)Ltd". The IEEE "oui.txt" registry can also be used.
+func (v *Values) validateVendorFile() error {
+	v.Vendors = nil
+	if v.VendorFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(v.VendorFile)
+	if err != nil {
+		return fmt.Errorf("the vendor file '%s' could not be read: %w", v.VendorFile, err)
+	}
+
+	v.Vendors = make(map[string]string)
+	for line := range strings.Lines(string(data)) {
+		oui, name, ok := parseVendorLine(line)
+		if !ok {
+			continue
+		}
+
+		v.Vendors[oui] = name
+	}
+
+	return nil
+}
+
+// parseVendorLine parses a line of the vendor file, and returns the OUI as uppercase
+// hexadecimal digits without separators, and the name of the manufacturer.
+func parseVendorLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	index := strings.IndexFunc(line, unicode.IsSpace)
+	if index < 0 {
+		return "", "", false
+	}
+
+	prefix, name := line[:index], line[index:]
+
+	oui := strings.ToUpper(strings.NewReplacer(":", "", "-", "").Replace(prefix))
+	if len(oui) != 6 || strings.Trim(oui, "0123456789ABCDEF") != "" {
+		return "", "", false
+	}
+
+	name = strings.TrimSpace(name)
+	for _, suffix := range []string{"(hex)", "(base 16)"} {
+		name = strings.TrimSpace(strings.TrimPrefix(name, suffix))
+	}
+	if name == "" {
+		return "", "", false
+	}
+
+	return oui, name, true
+}
+
 // validateGsm validates the GSM number and APN for the DUN network type.
 func (v *Values) validateGsm() error {
 	if v.GsmNumber == "" && v.GsmApn == "" {
@@ -259,11 +577,260 @@ func (v *Values) validateGsm() error {
 	return nil
 }
 
+// validateNetworkApns validates the addresses of the devices with a stored APN for DUN connections.
+func (v *Values) validateNetworkApns() error {
+	apns := make(map[string]string, len(v.NetworkApns))
+	for address, apn := range v.NetworkApns {
+		deviceAddr, err := bluetooth.ParseMAC(strings.TrimSpace(address))
+		if err != nil {
+			return fmt.Errorf("invalid address format for the APN %s: %s", apn, address)
+		}
+
+		apns[deviceAddr.String()] = strings.TrimSpace(apn)
+	}
+
+	v.NetworkApns = apns
+
+	return nil
+}
+
+// validateDeviceSort validates the sort mode of the devices list.
+func (v *Values) validateDeviceSort() error {
+	sortOptions := []string{
+		"connected",
+		"rssi",
+		"name",
+	}
+
+	if v.DeviceSort == "" {
+		v.DeviceSort = sortOptions[0]
+		return nil
+	}
+
+	if !slices.Contains(sortOptions, v.DeviceSort) {
+		return fmt.Errorf(
+			"provided device sort mode '%s' is incorrect.\nValid sort modes are '%s'",
+			v.DeviceSort,
+			strings.Join(sortOptions, ", "),
+		)
+	}
+
+	return nil
+}
+
+// validateBatteryThreshold validates the battery percentage below which
+// connected devices are alerted for.
+func (v *Values) validateBatteryThreshold() error {
+	if v.BatteryThreshold == 0 {
+		v.BatteryThreshold = 15
+		return nil
+	}
+
+	if v.BatteryThreshold < 1 || v.BatteryThreshold > 100 {
+		return fmt.Errorf("provided battery threshold '%d' is incorrect.\nThe threshold must be a percentage between 1 and 100", v.BatteryThreshold)
+	}
+
+	return nil
+}
+
+// validateHookTimeout validates the time after which the device hook commands are stopped.
+func (v *Values) validateHookTimeout() error {
+	if v.HookTimeout == 0 {
+		v.HookTimeout = 10
+		return nil
+	}
+
+	if v.HookTimeout < 0 {
+		return fmt.Errorf("provided hook timeout '%d' is incorrect.\nThe timeout must be a positive number of seconds", v.HookTimeout)
+	}
+
+	return nil
+}
+
+// validateConnectRetries validates the number of times a failed connection is retried,
+// and the initial delay between the connection attempts.
+func (v *Values) validateConnectRetries() error {
+	if v.ConnectRetries < 0 {
+		return fmt.Errorf("provided connect retries '%d' is incorrect.\nThe number of retries must be zero or a positive number", v.ConnectRetries)
+	}
+
+	if v.ConnectRetryDelay == 0 {
+		v.ConnectRetryDelay = 2
+		return nil
+	}
+
+	if v.ConnectRetryDelay < 0 {
+		return fmt.Errorf("provided connect retry delay '%d' is incorrect.\nThe delay must be a positive number of seconds", v.ConnectRetryDelay)
+	}
+
+	return nil
+}
+
+// validateMouseScrollLines validates the number of lines that the selection moves for each mouse wheel event.
+func (v *Values) validateMouseScrollLines() error {
+	if v.MouseScrollLines == 0 {
+		v.MouseScrollLines = 1
+		return nil
+	}
+
+	if v.MouseScrollLines < 0 {
+		return fmt.Errorf("provided mouse scroll lines '%d' is incorrect.\nThe number of lines must be a positive number", v.MouseScrollLines)
+	}
+
+	return nil
+}
+
+// validateMinRSSI validates the signal strength below which discovered devices are hidden.
+func (v *Values) validateMinRSSI() error {
+	if v.MinRSSI < -127 || v.MinRSSI > 0 {
+		return fmt.Errorf("provided minimum RSSI '%d' is incorrect.\nThe minimum RSSI must be between -127 and 0 dBm (0 shows all devices)", v.MinRSSI)
+	}
+
+	return nil
+}
+
+// validateScanNameWait validates the time for which newly discovered devices without a name are hidden.
+func (v *Values) validateScanNameWait() error {
+	if v.ScanNameWait < 0 {
+		return fmt.Errorf("provided scan name wait '%d' is incorrect.\nThe wait must be zero or a positive number of milliseconds", v.ScanNameWait)
+	}
+
+	return nil
+}
+
+// validateAdapterTimeouts validates the discoverable, pairable and scan timeouts.
+func (v *Values) validateAdapterTimeouts() error {
+	for name, timeout := range map[string]int{
+		"discoverable": v.DiscoverableTimeout,
+		"pairable":     v.PairableTimeout,
+		"scan":         v.ScanTimeout,
+	} {
+		if timeout < 0 {
+			return fmt.Errorf("provided %s timeout '%d' is incorrect.\nThe timeout must be zero or a positive number of seconds", name, timeout)
+		}
+	}
+
+	return nil
+}
+
+// validateColorMode validates the color mode of the theme.
+func (v *Values) validateColorMode() error {
+	if v.ColorMode == "" {
+		v.ColorMode = string(theme.ColorModeAuto)
+		return nil
+	}
+
+	modes := make([]string, 0, len(theme.ColorModes))
+	for _, mode := range theme.ColorModes {
+		if strings.EqualFold(v.ColorMode, string(mode)) {
+			v.ColorMode = string(mode)
+			return nil
+		}
+
+		modes = append(modes, string(mode))
+	}
+
+	return fmt.Errorf(
+		"provided color mode '%s' is incorrect.\nValid color modes are '%s'",
+		v.ColorMode,
+		strings.Join(modes, ", "),
+	)
+}
+
+// validateAudioBackend validates the sound server which manages the audio profiles of devices.
+func (v *Values) validateAudioBackend() error {
+	if v.AudioBackend == "" {
+		v.AudioBackend = AudioBackends[0]
+		return nil
+	}
+
+	for _, backend := range AudioBackends {
+		if strings.EqualFold(v.AudioBackend, backend) {
+			v.AudioBackend = backend
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"provided audio backend '%s' is incorrect.\nValid audio backends are '%s'",
+		v.AudioBackend,
+		strings.Join(AudioBackends, ", "),
+	)
+}
+
+// validateAgentCapability validates the IO capability which the pairing agent is registered with.
+func (v *Values) validateAgentCapability() error {
+	if v.AgentCapability == "" {
+		v.AgentCapability = AgentCapabilities[0]
+		return nil
+	}
+
+	for _, capability := range AgentCapabilities {
+		if strings.EqualFold(v.AgentCapability, capability) {
+			v.AgentCapability = capability
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"provided agent capability '%s' is incorrect.\nValid agent capabilities are '%s'",
+		v.AgentCapability,
+		strings.Join(AgentCapabilities, ", "),
+	)
+}
+
+// validateThemeFile loads the theme from the theme file, if specified.
+// If the theme file cannot be loaded, the built-in theme is used, and the error
+// is stored so that it can be shown as a warning.
+func (v *Values) validateThemeFile() error {
+	v.ThemeFileError = nil
+	if v.ThemeFile == "" {
+		return nil
+	}
+
+	k := koanf.New(".")
+	if err := k.Load(file.Provider(v.ThemeFile), hjson.Parser()); err != nil {
+		v.ThemeFileError = fmt.Errorf("the theme file '%s' could not be read, the built-in theme is used: %w", v.ThemeFile, err)
+		return nil
+	}
+
+	themeConfig := make(map[string]string, len(k.All()))
+	for context, color := range k.All() {
+		themeConfig[context] = fmt.Sprint(color)
+	}
+
+	if err := theme.ParseThemeConfig(themeConfig, true); err != nil {
+		v.ThemeFileError = fmt.Errorf("the theme file '%s' could not be loaded, the built-in theme is used: %w", v.ThemeFile, err)
+	}
+
+	return nil
+}
+
 // validateTheme validates the theme configuration.
+// Unknown theme contexts are ignored, so that existing configurations can still be loaded.
 func (v *Values) validateTheme() error {
 	if len(v.Theme) == 0 {
 		return nil
 	}
 
-	return theme.ParseThemeConfig(v.Theme)
+	return theme.ParseThemeConfig(v.Theme, false)
+}
+
+// parseAddressList validates and normalizes a list of device addresses.
+// Duplicate addresses are removed.
+func parseAddressList(addresses []string) ([]string, error) {
+	parsed := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		deviceAddr, err := bluetooth.ParseMAC(strings.TrimSpace(address))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address format: %s", address)
+		}
+
+		if address = deviceAddr.String(); !slices.Contains(parsed, address) {
+			parsed = append(parsed, address)
+		}
+	}
+
+	return parsed, nil
 }
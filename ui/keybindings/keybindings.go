@@ -3,7 +3,11 @@ package keybindings
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -19,6 +23,7 @@ type Key string
 // The different application keybinding types.
 const (
 	KeyMenu                        Key = "Menu"
+	KeyMenuDevice                  Key = "MenuDevice"
 	KeySelect                      Key = "Select"
 	KeyCancel                      Key = "Cancel"
 	KeySuspend                     Key = "Suspend"
@@ -26,20 +31,49 @@ const (
 	KeySwitch                      Key = "Switch"
 	KeyClose                       Key = "Close"
 	KeyHelp                        Key = "Help"
+	KeyHelpContext                 Key = "HelpContext"
+	KeyFind                        Key = "Find"
+	KeyMessageLog                  Key = "MessageLog"
+	KeyMessageLogClear             Key = "MessageLogClear"
 	KeyAdapterChange               Key = "AdapterChange"
 	KeyAdapterTogglePower          Key = "AdapterTogglePower"
 	KeyAdapterToggleDiscoverable   Key = "AdapterToggleDiscoverable"
 	KeyAdapterTogglePairable       Key = "AdapterTogglePairable"
 	KeyAdapterToggleScan           Key = "AdapterToggleScan"
+	KeyAdapterRename               Key = "AdapterRename"
+	KeyAdapterReset                Key = "AdapterReset"
+	KeyAdapterInfo                 Key = "AdapterInfo"
+	KeyConfigReload                Key = "ConfigReload"
+	KeyThemeReload                 Key = "ThemeReload"
+	KeyRefresh                     Key = "Refresh"
 	KeyDeviceSendFiles             Key = "DeviceSendFiles"
+	KeyDeviceBrowseFiles           Key = "DeviceBrowseFiles"
 	KeyDeviceNetwork               Key = "DeviceNetwork"
+	KeyDeviceNetworkDisconnect     Key = "DeviceNetworkDisconnect"
 	KeyDeviceConnect               Key = "DeviceConnect"
+	KeyDeviceConnectLast           Key = "DeviceConnectLast"
 	KeyDevicePair                  Key = "DevicePair"
 	KeyDeviceTrust                 Key = "DeviceTrust"
+	KeyDeviceTrustConnect          Key = "DeviceTrustConnect"
 	KeyDeviceBlock                 Key = "DeviceBlock"
 	KeyDeviceAudioProfiles         Key = "DeviceAudioProfiles"
+	KeyDeviceAudioHighQuality      Key = "DeviceAudioHighQuality"
+	KeyDeviceAudioHeadset          Key = "DeviceAudioHeadset"
+	KeyDeviceAudioDefault          Key = "DeviceAudioDefault"
 	KeyDeviceInfo                  Key = "DeviceInfo"
+	KeyDeviceProfiles              Key = "DeviceProfiles"
+	KeyDeviceProfileDisconnect     Key = "DeviceProfileDisconnect"
+	KeyDeviceCopyAddress           Key = "DeviceCopyAddress"
+	KeyDeviceCopyInfo              Key = "DeviceCopyInfo"
+	KeyDeviceFavorite              Key = "DeviceFavorite"
+	KeyDeviceSetDefault            Key = "DeviceSetDefault"
 	KeyDeviceRemove                Key = "DeviceRemove"
+	KeyDeviceIdentify              Key = "DeviceIdentify"
+	KeyDeviceSelect                Key = "DeviceSelect"
+	KeyDeviceBatch                 Key = "DeviceBatch"
+	KeyDeviceSort                  Key = "DeviceSort"
+	KeyDeviceMinRSSI               Key = "DeviceMinRSSI"
+	KeyDeviceRename                Key = "DeviceRename"
 	KeyPlayerShow                  Key = "PlayerShow"
 	KeyPlayerHide                  Key = "PlayerHide"
 	KeyFilebrowserDirForward       Key = "FilebrowserDirForward"
@@ -49,23 +83,39 @@ const (
 	KeyFilebrowserSelectAll        Key = "FilebrowserSelectAll"
 	KeyFilebrowserRefresh          Key = "FilebrowserRefresh"
 	KeyFilebrowserToggleHidden     Key = "FilebrowserToggleHidden"
+	KeyFilebrowserFilter           Key = "FilebrowserFilter"
+	KeyFilebrowserBookmarks        Key = "FilebrowserBookmarks"
+	KeyFilebrowserGoTo             Key = "FilebrowserGoTo"
+	KeyFilebrowserSort             Key = "FilebrowserSort"
+	KeyFilebrowserSortReverse      Key = "FilebrowserSortReverse"
+	KeyFilebrowserToggleBookmark   Key = "FilebrowserToggleBookmark"
 	KeyFilebrowserConfirmSelection Key = "FilebrowserConfirmSelection"
+	KeyFilebrowserDownload         Key = "FilebrowserDownload"
+	KeyFilebrowserUpload           Key = "FilebrowserUpload"
 	KeyProgressView                Key = "ProgressView"
 	KeyProgressTransferSuspend     Key = "ProgressTransferSuspend"
 	KeyProgressTransferResume      Key = "ProgressTransferResume"
 	KeyProgressTransferCancel      Key = "ProgressTransferCancel"
+	KeyProgressTransferToggleAll   Key = "ProgressTransferToggleAll"
+	KeyProgressQueueFront          Key = "ProgressQueueFront"
+	KeyProgressQueueCancel         Key = "ProgressQueueCancel"
 	KeyPlayerTogglePlay            Key = "PlayerTogglePlay"
 	KeyPlayerNext                  Key = "PlayerNext"
 	KeyPlayerPrevious              Key = "PlayerPrevious"
 	KeyPlayerSeekForward           Key = "PlayerSeekForward"
 	KeyPlayerSeekBackward          Key = "PlayerSeekBackward"
 	KeyPlayerStop                  Key = "PlayerStop"
+	KeyPlayerSeekTo                Key = "PlayerSeekTo"
+	KeyPlayerToggleRepeat          Key = "PlayerToggleRepeat"
+	KeyPlayerToggleShuffle         Key = "PlayerToggleShuffle"
 	KeyNavigateUp                  Key = "NavigateUp"
 	KeyNavigateDown                Key = "NavigateDown"
 	KeyNavigateRight               Key = "NavigateRight"
 	KeyNavigateLeft                Key = "NavigateLeft"
 	KeyNavigateTop                 Key = "NavigateTop"
 	KeyNavigateBottom              Key = "NavigateBottom"
+	KeyNavigateFirst               Key = "NavigateFirst"
+	KeyNavigateLast                Key = "NavigateLast"
 )
 
 // Context describes the context where the keybinding is
@@ -76,16 +126,23 @@ type Context string
 const (
 	ContextApp      Context = "App"
 	ContextDevice   Context = "Device"
+	ContextPlayer   Context = "Player"
 	ContextFiles    Context = "Files"
 	ContextProgress Context = "Progress"
 )
 
+// DefaultSequenceTimeout is the default time to wait for the second key of a key sequence.
+const DefaultSequenceTimeout = 500 * time.Millisecond
+
 // KeyData stores the metadata for the key.
+// A key can be bound to multiple keybindings and key sequences,
+// where each key sequence is triggered by pressing its keybindings in order.
 type KeyData struct {
-	Title   string
-	Context Context
-	Kb      Keybinding
-	Global  bool
+	Title     string
+	Context   Context
+	Kb        []Keybinding
+	Sequences [][2]Keybinding
+	Global    bool
 }
 
 // Keybinding stores the keybinding.
@@ -99,8 +156,23 @@ type Keybinding struct {
 type Keybindings struct {
 	keyData        map[Key]*KeyData
 	contextKeys    map[Context]map[Keybinding]Key
+	sequenceKeys   map[Context]map[[2]Keybinding]Key
 	navigationKeys map[Key]Keybinding
 	translateKeys  map[string]string
+
+	sequence keySequence
+}
+
+// keySequence stores the state of a key sequence that is being typed.
+type keySequence struct {
+	timeout time.Duration
+
+	event     *tcell.EventKey
+	prefix    *Keybinding
+	pending   *Keybinding
+	pendingAt time.Time
+
+	mu sync.Mutex
 }
 
 // NewKeybindings returns a new keybindings configuration.
@@ -125,11 +197,27 @@ func (k *Keybindings) Initialize() {
 		if k.contextKeys[key.Context] == nil {
 			k.contextKeys[key.Context] = make(map[Keybinding]Key)
 		}
+		for _, kb := range key.Kb {
+			k.contextKeys[key.Context][kb] = keyName
+		}
 
-		k.contextKeys[key.Context][key.Kb] = keyName
+		if k.sequenceKeys[key.Context] == nil {
+			k.sequenceKeys[key.Context] = make(map[[2]Keybinding]Key)
+		}
+		for _, seq := range key.Sequences {
+			k.sequenceKeys[key.Context][seq] = keyName
+		}
 	}
 }
 
+// SetSequenceTimeout sets the time to wait for the second key of a key sequence.
+func (k *Keybindings) SetSequenceTimeout(timeout time.Duration) {
+	k.sequence.mu.Lock()
+	defer k.sequence.mu.Unlock()
+
+	k.sequence.timeout = timeout
+}
+
 // Key returns the operation name for the provided keyID
 // and the keyboard event.
 func (k *Keybindings) Key(event *tcell.EventKey, keyContexts ...Context) Key {
@@ -144,15 +232,38 @@ func (k *Keybindings) Key(event *tcell.EventKey, keyContexts ...Context) Key {
 	}
 
 	kb := Keybinding{event.Key(), ch, mod}
+	keyContexts = append(keyContexts, ContextApp, ContextDevice)
 
-	if key, ok := k.checkContexts(kb, keyContexts); ok {
-		return key
+	k.sequence.mu.Lock()
+	defer k.sequence.mu.Unlock()
+
+	// The same event may be checked multiple times by different handlers,
+	// so the key sequence state is only updated once for each event.
+	if k.sequence.event != event {
+		k.sequence.event = event
+		k.sequence.prefix = nil
+
+		if k.sequence.pending != nil && time.Since(k.sequence.pendingAt) <= k.sequence.timeout {
+			k.sequence.prefix = k.sequence.pending
+		}
+
+		k.sequence.pending = nil
+	}
+
+	if prefix := k.sequence.prefix; prefix != nil {
+		if key, ok := k.checkSequenceContexts([2]Keybinding{*prefix, kb}, keyContexts); ok {
+			return key
+		}
 	}
 
-	if key, ok := k.checkContexts(kb, []Context{
-		ContextApp,
-		ContextDevice,
-	}); ok {
+	if k.isSequencePrefix(kb, keyContexts) {
+		k.sequence.pending = &kb
+		k.sequence.pendingAt = time.Now()
+
+		return ""
+	}
+
+	if key, ok := k.checkContexts(kb, keyContexts); ok {
 		return key
 	}
 
@@ -177,6 +288,29 @@ func (k *Keybindings) Name(kb Keybinding) string {
 	return tcell.NewEventKey(kb.Key, kb.Rune, kb.Mod).Name()
 }
 
+// KeyName formats and returns the names of all the keybindings
+// and key sequences associated with the provided key.
+func (k *Keybindings) KeyName(key Key) string {
+	data := k.keyData[key]
+
+	names := make([]string, 0, len(data.Kb)+len(data.Sequences))
+	for _, kb := range data.Kb {
+		names = append(names, k.Name(kb))
+	}
+	for _, seq := range data.Sequences {
+		names = append(names, k.Name(seq[0])+" "+k.Name(seq[1]))
+	}
+
+	return strings.Join(names, "/")
+}
+
+// IsBound checks whether any keybinding or key sequence is associated with the provided key.
+func (k *Keybindings) IsBound(key Key) bool {
+	data := k.keyData[key]
+
+	return data != nil && (data.Kb != nil || data.Sequences != nil)
+}
+
 // IsNavigation checks whether the provided key is a navigation key.
 func (k *Keybindings) IsNavigation(pressed Key, event *tcell.EventKey) (*tcell.EventKey, bool) {
 	kb := Keybinding{event.Key(), event.Rune(), event.Modifiers()}
@@ -192,17 +326,10 @@ func (k *Keybindings) IsNavigation(pressed Key, event *tcell.EventKey) (*tcell.E
 	return tcell.NewEventKey(n.Key, n.Rune, n.Mod), true
 }
 
-// Validate validates the keybindings from the configuration.
+// Validate validates the keybindings from the configuration, and checks whether
+// the resulting keybindings, including those of an applied preset, conflict.
 func (k *Keybindings) Validate(kbMap map[string]string) error {
-	if len(kbMap) == 0 {
-		return nil
-	}
-
-	keyNames := make(map[string]tcell.Key)
-	for key, names := range tcell.KeyNames {
-		keyNames[names] = key
-	}
-
+	keyNames := getKeyNames()
 	for keyType, key := range kbMap {
 		k.checkBindings(keyType, key, keyNames)
 	}
@@ -211,16 +338,18 @@ func (k *Keybindings) Validate(kbMap map[string]string) error {
 
 	for keyType, keydata := range k.keyData {
 		for existing, data := range k.keyData {
-			if data.Kb == keydata.Kb && data.Title != keydata.Title {
-				if data.Context == keydata.Context || data.Global || keydata.Global {
-					goto KeyError
-				}
+			if data.Title == keydata.Title {
+				continue
+			}
 
+			kb, ok := keydata.overlaps(data)
+			if !ok {
 				continue
+			}
 
-			KeyError:
-				if _, ok := keyErrors[keydata.Kb]; !ok {
-					keyErrors[keydata.Kb] = fmt.Sprintf("- %s will override %s (%s)", keyType, existing, k.Name(keydata.Kb))
+			if data.Context == keydata.Context || data.Global || keydata.Global {
+				if _, ok := keyErrors[kb]; !ok {
+					keyErrors[kb] = fmt.Sprintf("- %s will override %s (%s)", keyType, existing, k.Name(kb))
 				}
 			}
 		}
@@ -238,6 +367,83 @@ func (k *Keybindings) Validate(kbMap map[string]string) error {
 	return nil
 }
 
+// InvalidBindings returns an error for each key type and keybinding from the configuration
+// which cannot be parsed. Such keybindings are ignored by [Keybindings.Validate].
+func (k *Keybindings) InvalidBindings(kbMap map[string]string) []error {
+	var errs []error
+
+	keyNames := getKeyNames()
+	check := NewKeybindings()
+
+	for _, keyType := range slices.Sorted(maps.Keys(kbMap)) {
+		if err := check.checkBindings(keyType, kbMap[keyType], keyNames); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// getKeyNames returns a map of key names to their respective keys.
+func getKeyNames() map[string]tcell.Key {
+	keyNames := make(map[string]tcell.Key, len(tcell.KeyNames))
+	for key, names := range tcell.KeyNames {
+		keyNames[names] = key
+	}
+
+	return keyNames
+}
+
+// Export returns the keybindings in the format used by the configuration.
+// If minimal is set, only keybindings that differ from the defaults are returned.
+func (k *Keybindings) Export(minimal bool) map[string]string {
+	defaults := NewKeybindings()
+
+	kbMap := make(map[string]string, len(k.keyData))
+	for key, data := range k.keyData {
+		if minimal && slices.Equal(defaults.keyData[key].Kb, data.Kb) && data.Sequences == nil {
+			continue
+		}
+
+		names := make([]string, 0, len(data.Kb)+len(data.Sequences))
+		for _, kb := range data.Kb {
+			names = append(names, k.configName(kb))
+		}
+		for _, seq := range data.Sequences {
+			names = append(names, k.configName(seq[0])+" "+k.configName(seq[1]))
+		}
+
+		kbMap[string(key)] = strings.Join(names, ", ")
+	}
+
+	return kbMap
+}
+
+// overlaps returns the first keybinding which cannot be distinguished from the provided
+// key's keybindings. This is the case if both keys have an equal keybinding or key sequence,
+// or if a keybinding of one key is the first keybinding of the other key's key sequence.
+func (d *KeyData) overlaps(data *KeyData) (Keybinding, bool) {
+	for _, kb := range d.Kb {
+		if slices.Contains(data.Kb, kb) {
+			return kb, true
+		}
+
+		for _, seq := range data.Sequences {
+			if seq[0] == kb {
+				return kb, true
+			}
+		}
+	}
+
+	for _, seq := range d.Sequences {
+		if slices.Contains(data.Sequences, seq) || slices.Contains(data.Kb, seq[0]) {
+			return seq[0], true
+		}
+	}
+
+	return Keybinding{}, false
+}
+
 // checkContexts checks whether a keybinding exists within the provided keybinding context.
 func (k *Keybindings) checkContexts(kb Keybinding, contexts []Context) (Key, bool) {
 	for _, context := range contexts {
@@ -250,17 +456,85 @@ func (k *Keybindings) checkContexts(kb Keybinding, contexts []Context) (Key, boo
 	return "", false
 }
 
-// checkBindings validates the provided keybinding.
-//
-//gocyclo:ignore
+// checkSequenceContexts checks whether a key sequence exists within the provided keybinding context.
+func (k *Keybindings) checkSequenceContexts(seq [2]Keybinding, contexts []Context) (Key, bool) {
+	for _, context := range contexts {
+		if operation, ok := k.sequenceKeys[context][seq]; ok {
+			return operation, true
+		}
+	}
+
+	return "", false
+}
+
+// isSequencePrefix checks whether the keybinding is the first key of a key sequence
+// within the provided keybinding context.
+func (k *Keybindings) isSequencePrefix(kb Keybinding, contexts []Context) bool {
+	for _, context := range contexts {
+		for seq := range k.sequenceKeys[context] {
+			if seq[0] == kb {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkBindings validates the provided comma-separated list of keybindings and key sequences.
+// A key sequence is specified as two keybindings separated by a space, for example "g g".
+// An empty list unbinds the key, so that it cannot be triggered.
 func (k *Keybindings) checkBindings(keyType, key string, keyNames map[string]tcell.Key) error {
-	var runes []rune
-	var keys []tcell.Key
+	var keybindings []Keybinding
+	var sequences [][2]Keybinding
 
-	if _, ok := k.keyData[Key(keyType)]; !ok {
+	data, ok := k.keyData[Key(keyType)]
+	if !ok {
 		return fmt.Errorf("config: Invalid key type %s", keyType)
 	}
 
+	if strings.TrimSpace(key) == "" {
+		data.Kb, data.Sequences = nil, nil
+		return nil
+	}
+
+	bindings := []string{key}
+	if strings.TrimSpace(key) != "," {
+		bindings = strings.Split(key, ",")
+	}
+
+	for _, binding := range bindings {
+		if parts := strings.Fields(binding); len(parts) == 2 {
+			first, err := k.parseBinding(keyType, parts[0], keyNames)
+			if err == nil {
+				next, err := k.parseBinding(keyType, parts[1], keyNames)
+				if err == nil {
+					sequences = append(sequences, [2]Keybinding{first, next})
+					continue
+				}
+			}
+		}
+
+		keybinding, err := k.parseBinding(keyType, binding, keyNames)
+		if err != nil {
+			return err
+		}
+
+		keybindings = append(keybindings, keybinding)
+	}
+
+	data.Kb, data.Sequences = keybindings, sequences
+
+	return nil
+}
+
+// parseBinding parses the provided keybinding.
+//
+//gocyclo:ignore
+func (k *Keybindings) parseBinding(keyType, key string, keyNames map[string]tcell.Key) (Keybinding, error) {
+	var runes []rune
+	var keys []tcell.Key
+
 	keybinding := Keybinding{
 		Key:  tcell.KeyRune,
 		Rune: ' ',
@@ -298,10 +572,16 @@ func (k *Keybindings) checkBindings(keyType, key string, keyNames map[string]tce
 		case "Shift":
 			keybinding.Mod |= tcell.ModShift
 
-		case "Space", "Plus":
-			keybinding.Rune = ' '
-			if token == "Plus" {
+		case "Space", "Plus", "Comma":
+			switch token {
+			case "Space":
+				keybinding.Rune = ' '
+
+			case "Plus":
 				keybinding.Rune = '+'
+
+			case "Comma":
+				keybinding.Rune = ','
 			}
 
 			runes = append(runes, keybinding.Rune)
@@ -316,7 +596,7 @@ func (k *Keybindings) checkBindings(keyType, key string, keyNames map[string]tce
 	}
 
 	if keys != nil && runes != nil || len(runes) > 1 || len(keys) > 1 {
-		return fmt.Errorf("config: More than one key entered for %s (%s)", keyType, key)
+		return keybinding, fmt.Errorf("config: More than one key entered for %s (%s)", keyType, key)
 	}
 
 	if keybinding.Mod&tcell.ModShift != 0 {
@@ -355,17 +635,61 @@ func (k *Keybindings) checkBindings(keyType, key string, keyNames map[string]tce
 	}
 
 	if keys == nil && runes == nil {
-		return fmt.Errorf("config: No key specified or invalid keybinding for %s (%s)", keyType, key)
+		return keybinding, fmt.Errorf("config: No key specified or invalid keybinding for %s (%s)", keyType, key)
 	}
 
-	k.keyData[Key(keyType)].Kb = keybinding
+	return keybinding, nil
+}
+
+// configName formats and returns the key's name, such that it can be parsed
+// from the configuration.
+func (k *Keybindings) configName(kb Keybinding) string {
+	var keyname string
 
-	return nil
+	switch kb.Key {
+	case tcell.KeyRune:
+		switch kb.Rune {
+		case ' ':
+			keyname = "Space"
+
+		case '+':
+			keyname = "Plus"
+
+		case ',':
+			keyname = "Comma"
+
+		default:
+			keyname = string(kb.Rune)
+		}
+
+	default:
+		keyname = tcell.KeyNames[kb.Key]
+		if kb.Mod&tcell.ModCtrl != 0 {
+			keyname = strings.TrimPrefix(keyname, "Ctrl-")
+		}
+	}
+
+	for _, mod := range []struct {
+		mask tcell.ModMask
+		name string
+	}{
+		{tcell.ModShift, "Shift"},
+		{tcell.ModAlt, "Alt"},
+		{tcell.ModCtrl, "Ctrl"},
+	} {
+		if kb.Mod&mod.mask != 0 {
+			keyname = mod.name + "+" + keyname
+		}
+	}
+
+	return keyname
 }
 
 // initKeys initializes and stores the key types and contexts.
 func (k *Keybindings) initKeys() {
 	k.contextKeys = make(map[Context]map[Keybinding]Key)
+	k.sequenceKeys = make(map[Context]map[[2]Keybinding]Key)
+	k.sequence.timeout = DefaultSequenceTimeout
 
 	k.navigationKeys = map[Key]Keybinding{
 		KeyNavigateUp:     {tcell.KeyUp, ' ', tcell.ModNone},
@@ -374,6 +698,8 @@ func (k *Keybindings) initKeys() {
 		KeyNavigateLeft:   {tcell.KeyLeft, ' ', tcell.ModNone},
 		KeyNavigateTop:    {tcell.KeyPgUp, ' ', tcell.ModNone},
 		KeyNavigateBottom: {tcell.KeyPgDn, ' ', tcell.ModNone},
+		KeyNavigateFirst:  {tcell.KeyHome, ' ', tcell.ModNone},
+		KeyNavigateLast:   {tcell.KeyEnd, ' ', tcell.ModNone},
 	}
 
 	k.translateKeys = map[string]string{
@@ -396,249 +722,480 @@ func (k *Keybindings) initData() {
 		KeySwitch: {
 			Title:   "Switch",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyTab, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyTab, ' ', tcell.ModNone}},
 			Global:  true,
 		},
 		KeyClose: {
 			Title:   "Close",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyEscape, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyEscape, ' ', tcell.ModNone}},
 			Global:  true,
 		},
 		KeyQuit: {
 			Title:   "Quit",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyRune, 'Q', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'Q', tcell.ModNone}},
 			Global:  true,
 		},
 		KeyMenu: {
 			Title:   "Menu",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyRune, 'm', tcell.ModAlt},
+			Kb:      []Keybinding{{tcell.KeyRune, 'm', tcell.ModAlt}},
+		},
+		KeyMenuDevice: {
+			Title:   "Device Menu",
+			Context: ContextApp,
+			Kb:      []Keybinding{{tcell.KeyRune, 'd', tcell.ModAlt}},
 		},
 		KeySelect: {
 			Title:   "Select",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyEnter, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyEnter, ' ', tcell.ModNone}},
 			Global:  true,
 		},
 		KeyCancel: {
 			Title:   "Cancel",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyCtrlX, ' ', tcell.ModCtrl},
+			Kb:      []Keybinding{{tcell.KeyCtrlX, ' ', tcell.ModCtrl}},
 			Global:  true,
 		},
 		KeySuspend: {
 			Title:   "Suspend",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyCtrlZ, ' ', tcell.ModCtrl},
+			Kb:      []Keybinding{{tcell.KeyCtrlZ, ' ', tcell.ModCtrl}},
 			Global:  true,
 		},
 		KeyHelp: {
 			Title:   "Help",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyRune, '?', tcell.ModShift},
+			Kb:      []Keybinding{{tcell.KeyRune, '?', tcell.ModShift}},
 			Global:  true,
 		},
+		KeyHelpContext: {
+			Title:   "Context Help",
+			Context: ContextApp,
+			Kb:      []Keybinding{{tcell.KeyF1, ' ', tcell.ModNone}},
+			Global:  true,
+		},
+		KeyFind: {
+			Title:   "Find",
+			Context: ContextApp,
+			Kb:      []Keybinding{{tcell.KeyCtrlF, ' ', tcell.ModCtrl}},
+		},
 		KeyNavigateUp: {
 			Title:   "Navigate Up",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyUp, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyUp, ' ', tcell.ModNone}},
 		},
 		KeyNavigateDown: {
 			Title:   "Navigate Down",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyDown, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyDown, ' ', tcell.ModNone}},
 		},
 		KeyNavigateRight: {
 			Title:   "Navigate Right",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyRight, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRight, ' ', tcell.ModNone}},
 		},
 		KeyNavigateLeft: {
 			Title:   "Navigate Left",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyLeft, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyLeft, ' ', tcell.ModNone}},
 		},
 		KeyNavigateTop: {
 			Title:   "Navigate Top",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyPgUp, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyPgUp, ' ', tcell.ModNone}},
 		},
 		KeyNavigateBottom: {
 			Title:   "Navigate Bottom",
 			Context: ContextApp,
-			Kb:      Keybinding{tcell.KeyPgDn, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyPgDn, ' ', tcell.ModNone}},
+		},
+		KeyNavigateFirst: {
+			Title:   "Navigate First",
+			Context: ContextApp,
+			Kb:      []Keybinding{{tcell.KeyHome, ' ', tcell.ModNone}},
+		},
+		KeyNavigateLast: {
+			Title:   "Navigate Last",
+			Context: ContextApp,
+			Kb:      []Keybinding{{tcell.KeyEnd, ' ', tcell.ModNone}},
 		},
 		KeyAdapterTogglePower: {
 			Title:   "Power",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'o', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'o', tcell.ModNone}},
 		},
 		KeyAdapterToggleDiscoverable: {
 			Title:   "Discoverable",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'S', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'S', tcell.ModNone}},
 		},
 		KeyAdapterTogglePairable: {
 			Title:   "Pairable",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'P', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'P', tcell.ModNone}},
 		},
 		KeyAdapterToggleScan: {
 			Title:   "Scan",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 's', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 's', tcell.ModNone}},
+		},
+		KeyAdapterRename: {
+			Title:   "Rename",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'R', tcell.ModNone}},
+		},
+		KeyAdapterReset: {
+			Title:   "Reset",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'X', tcell.ModNone}},
+		},
+		KeyAdapterInfo: {
+			Title:   "Adapter Info",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'I', tcell.ModNone}},
+		},
+		KeyMessageLog: {
+			Title:   "Message Log",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'L', tcell.ModNone}},
+		},
+		KeyMessageLogClear: {
+			Title:   "Clear Message Log",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'C', tcell.ModNone}},
+		},
+		KeyConfigReload: {
+			Title:   "Reload Config",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyCtrlL, ' ', tcell.ModCtrl}},
+		},
+		KeyThemeReload: {
+			Title:   "Reload Theme",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyCtrlT, ' ', tcell.ModCtrl}},
+		},
+		KeyRefresh: {
+			Title:   "Refresh",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyF5, ' ', tcell.ModNone}},
 		},
 		KeyAdapterChange: {
 			Title:   "Change",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'a', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'a', tcell.ModNone}},
 		},
 		KeyDeviceConnect: {
 			Title:   "Connect",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'c', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'c', tcell.ModNone}},
+		},
+		KeyDeviceConnectLast: {
+			Title:   "Connect Last",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'c', tcell.ModAlt}},
 		},
 		KeyDevicePair: {
 			Title:   "Pair",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'p', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'p', tcell.ModNone}},
 		},
 		KeyDeviceTrust: {
 			Title:   "Trust",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 't', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 't', tcell.ModNone}},
+		},
+		KeyDeviceTrustConnect: {
+			Title:   "Trust and Connect",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'T', tcell.ModNone}},
 		},
 		KeyDeviceBlock: {
 			Title:   "Block",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'b', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'b', tcell.ModNone}},
 		},
 		KeyDeviceSendFiles: {
 			Title:   "Send",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'f', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'f', tcell.ModNone}},
+		},
+		KeyDeviceBrowseFiles: {
+			Title:   "Browse Files",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'F', tcell.ModNone}},
 		},
 		KeyDeviceNetwork: {
 			Title:   "Network Options",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'n', tcell.ModNone}},
+		},
+		KeyDeviceNetworkDisconnect: {
+			Title:   "Disconnect Network",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'N', tcell.ModNone}},
 		},
 		KeyDeviceAudioProfiles: {
 			Title:   "Audio Profiles",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'A', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'A', tcell.ModNone}},
+		},
+		KeyDeviceAudioHighQuality: {
+			Title:   "High Quality Audio",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'J', tcell.ModNone}},
+		},
+		KeyDeviceAudioHeadset: {
+			Title:   "Headset Audio",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'H', tcell.ModNone}},
+		},
+		KeyDeviceAudioDefault: {
+			Title:   "Set Default Output",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'K', tcell.ModNone}},
 		},
 		KeyDeviceInfo: {
 			Title:   "Info",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'i', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'i', tcell.ModNone}},
+		},
+		KeyDeviceProfiles: {
+			Title:   "Profiles",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'E', tcell.ModNone}},
+		},
+		KeyDeviceProfileDisconnect: {
+			Title:   "Disconnect Profile",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'x', tcell.ModNone}},
+		},
+		KeyDeviceFavorite: {
+			Title:   "Favorite",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'v', tcell.ModNone}},
+		},
+		KeyDeviceSetDefault: {
+			Title:   "Set Default",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'D', tcell.ModNone}},
+		},
+		KeyDeviceCopyAddress: {
+			Title:   "Copy Address",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'y', tcell.ModNone}},
+		},
+		KeyDeviceCopyInfo: {
+			Title:   "Copy Information",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'Y', tcell.ModNone}},
 		},
 		KeyDeviceRemove: {
 			Title:   "Remove",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'd', tcell.ModNone}},
+		},
+		KeyDeviceIdentify: {
+			Title:   "Identify",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'w', tcell.ModNone}},
+		},
+		KeyDeviceSelect: {
+			Title:   "Select",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, ' ', tcell.ModNone}},
+		},
+		KeyDeviceBatch: {
+			Title:   "Batch Actions",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'B', tcell.ModNone}},
+		},
+		KeyDeviceRename: {
+			Title:   "Rename",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'e', tcell.ModNone}},
+		},
+		KeyDeviceSort: {
+			Title:   "Sort Devices",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'O', tcell.ModNone}},
+		},
+		KeyDeviceMinRSSI: {
+			Title:   "Signal Filter",
+			Context: ContextDevice,
+			Kb:      []Keybinding{{tcell.KeyRune, 'V', tcell.ModNone}},
 		},
 		KeyPlayerShow: {
 			Title:   "Show Media Player",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'm', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'm', tcell.ModNone}},
 		},
 		KeyPlayerHide: {
 			Title:   "Hide Media Player",
 			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, 'M', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'M', tcell.ModNone}},
 		},
 		KeyPlayerTogglePlay: {
 			Title:   "Play/Pause",
-			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, ' ', tcell.ModNone},
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, ' ', tcell.ModNone}},
 		},
 		KeyPlayerNext: {
 			Title:   "Next",
-			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, '>', tcell.ModNone},
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, '>', tcell.ModNone}},
 		},
 		KeyPlayerPrevious: {
 			Title:   "Previous",
-			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, '<', tcell.ModNone},
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, '<', tcell.ModNone}},
 		},
 		KeyPlayerSeekForward: {
 			Title:   "Seek Forward",
-			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRight, ' ', tcell.ModNone},
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRight, ' ', tcell.ModNone}},
 		},
 		KeyPlayerSeekBackward: {
 			Title:   "Seek Backward",
-			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyLeft, ' ', tcell.ModNone},
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyLeft, ' ', tcell.ModNone}},
 		},
 		KeyPlayerStop: {
 			Title:   "Stop",
-			Context: ContextDevice,
-			Kb:      Keybinding{tcell.KeyRune, ']', tcell.ModNone},
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, ']', tcell.ModNone}},
+		},
+		KeyPlayerSeekTo: {
+			Title:   "Seek To",
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, 'j', tcell.ModNone}},
+		},
+		KeyPlayerToggleRepeat: {
+			Title:   "Repeat",
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, 'r', tcell.ModNone}},
+		},
+		KeyPlayerToggleShuffle: {
+			Title:   "Shuffle",
+			Context: ContextPlayer,
+			Kb:      []Keybinding{{tcell.KeyRune, 'u', tcell.ModNone}},
 		},
 		KeyFilebrowserConfirmSelection: {
 			Title:   "Confirm Selection",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyCtrlS, ' ', tcell.ModCtrl},
+			Kb:      []Keybinding{{tcell.KeyCtrlS, ' ', tcell.ModCtrl}},
 		},
 		KeyFilebrowserDirForward: {
 			Title:   "Go Forward",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyRight, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRight, ' ', tcell.ModNone}},
 		},
 		KeyFilebrowserDirBack: {
 			Title:   "Go Back",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyLeft, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyLeft, ' ', tcell.ModNone}},
 		},
 		KeyFilebrowserSelect: {
 			Title:   "Select",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyRune, ' ', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, ' ', tcell.ModNone}},
 		},
 		KeyFilebrowserInvertSelection: {
 			Title:   "Invert Selection",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyRune, 'a', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'a', tcell.ModNone}},
 		},
 		KeyFilebrowserSelectAll: {
 			Title:   "Select All",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyRune, 'A', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'A', tcell.ModNone}},
 		},
 		KeyFilebrowserRefresh: {
 			Title:   "Refresh",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyCtrlR, ' ', tcell.ModCtrl},
+			Kb:      []Keybinding{{tcell.KeyCtrlR, ' ', tcell.ModCtrl}},
 		},
 		KeyFilebrowserToggleHidden: {
 			Title:   "Hidden",
 			Context: ContextFiles,
-			Kb:      Keybinding{tcell.KeyRune, 'h', tcell.ModCtrl},
+			Kb:      []Keybinding{{tcell.KeyCtrlH, ' ', tcell.ModCtrl}},
+		},
+		KeyFilebrowserFilter: {
+			Title:   "Filter",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, '/', tcell.ModNone}},
+		},
+		KeyFilebrowserSort: {
+			Title:   "Sort",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 's', tcell.ModNone}},
+		},
+		KeyFilebrowserSortReverse: {
+			Title:   "Reverse Sort",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 'S', tcell.ModNone}},
+		},
+		KeyFilebrowserGoTo: {
+			Title:   "Go To Path",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 'g', tcell.ModNone}},
+		},
+		KeyFilebrowserBookmarks: {
+			Title:   "Bookmarks",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 'b', tcell.ModNone}},
+		},
+		KeyFilebrowserToggleBookmark: {
+			Title:   "Toggle Bookmark",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 'B', tcell.ModNone}},
+		},
+		KeyFilebrowserDownload: {
+			Title:   "Download",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 'D', tcell.ModNone}},
+		},
+		KeyFilebrowserUpload: {
+			Title:   "Upload",
+			Context: ContextFiles,
+			Kb:      []Keybinding{{tcell.KeyRune, 'U', tcell.ModNone}},
 		},
 		KeyProgressTransferResume: {
 			Title:   "Resume Transfer",
 			Context: ContextProgress,
-			Kb:      Keybinding{tcell.KeyRune, 'g', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'g', tcell.ModNone}},
 		},
 		KeyProgressTransferCancel: {
 			Title:   "Cancel Transfer",
 			Context: ContextProgress,
-			Kb:      Keybinding{tcell.KeyRune, 'x', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'x', tcell.ModNone}},
+		},
+		KeyProgressTransferToggleAll: {
+			Title:   "Suspend/Resume All",
+			Context: ContextProgress,
+			Kb:      []Keybinding{{tcell.KeyRune, 'Z', tcell.ModNone}},
+		},
+		KeyProgressQueueFront: {
+			Title:   "Move To Front",
+			Context: ContextProgress,
+			Kb:      []Keybinding{{tcell.KeyRune, 'f', tcell.ModNone}},
+		},
+		KeyProgressQueueCancel: {
+			Title:   "Cancel Queue",
+			Context: ContextProgress,
+			Kb:      []Keybinding{{tcell.KeyRune, 'X', tcell.ModNone}},
 		},
 		KeyProgressView: {
 			Title:   "View Downloads",
 			Context: ContextProgress,
-			Kb:      Keybinding{tcell.KeyRune, 'v', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'v', tcell.ModNone}},
 		},
 		KeyProgressTransferSuspend: {
 			Title:   "Suspend Transfer",
 			Context: ContextProgress,
-			Kb:      Keybinding{tcell.KeyRune, 'z', tcell.ModNone},
+			Kb:      []Keybinding{{tcell.KeyRune, 'z', tcell.ModNone}},
 		},
 	}
 }
@@ -0,0 +1,156 @@
+package keybindings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keyPress describes a key that is pressed, and the key which is expected to be returned
+// for each handler that checks the event.
+type keyPress struct {
+	ch       rune
+	contexts []Context
+	checks   int
+	expire   bool
+	want     Key
+}
+
+func newTestKeybindings(t *testing.T, preset string, kbMap map[string]string) *Keybindings {
+	t.Helper()
+
+	k := NewKeybindings()
+	if err := k.ApplyPreset(preset); err != nil {
+		t.Fatalf("cannot apply the %q preset: %v", preset, err)
+	}
+	if err := k.Validate(kbMap); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	k.Initialize()
+
+	return k
+}
+
+func TestKeySequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		preset  string
+		kbMap   map[string]string
+		presses []keyPress
+	}{
+		{
+			name: "single key without sequences",
+			presses: []keyPress{
+				{ch: 'g', contexts: []Context{ContextFiles}, want: KeyFilebrowserGoTo},
+			},
+		},
+		{
+			name:   "prefix is swallowed and the sequence is matched",
+			preset: "vim",
+			presses: []keyPress{
+				{ch: 'g', want: ""},
+				{ch: 'g', want: KeyNavigateFirst},
+			},
+		},
+		{
+			name:   "preset moves the conflicting keybinding",
+			preset: "vim",
+			presses: []keyPress{
+				{ch: ':', contexts: []Context{ContextFiles}, want: KeyFilebrowserGoTo},
+			},
+		},
+		{
+			name:   "key after the prefix which completes no sequence",
+			preset: "vim",
+			presses: []keyPress{
+				{ch: 'g', want: ""},
+				{ch: 'G', want: KeyNavigateLast},
+			},
+		},
+		{
+			name:   "sequence is matched only once",
+			preset: "vim",
+			presses: []keyPress{
+				{ch: 'g', want: ""},
+				{ch: 'g', want: KeyNavigateFirst},
+				{ch: 'g', want: ""},
+			},
+		},
+		{
+			name:   "each event is checked by multiple handlers",
+			preset: "vim",
+			presses: []keyPress{
+				{ch: 'g', checks: 3, want: ""},
+				{ch: 'g', checks: 3, want: KeyNavigateFirst},
+			},
+		},
+		{
+			name:   "prefix expires after the timeout",
+			preset: "vim",
+			presses: []keyPress{
+				{ch: 'g', want: ""},
+				{ch: 'G', expire: true, want: KeyNavigateLast},
+				{ch: 'g', want: ""},
+				{ch: 'g', expire: true, want: ""},
+			},
+		},
+		{
+			name:  "sequence from the configuration",
+			kbMap: map[string]string{"Quit": "Q, q q"},
+			presses: []keyPress{
+				{ch: 'q', want: ""},
+				{ch: 'q', want: KeyQuit},
+				{ch: 'Q', want: KeyQuit},
+			},
+		},
+		{
+			name:  "sequence in another context is ignored",
+			kbMap: map[string]string{"FilebrowserGoTo": "z z"},
+			presses: []keyPress{
+				{ch: 'z', want: ""},
+				{ch: 'z', want: ""},
+				{ch: 'z', contexts: []Context{ContextFiles}, want: ""},
+				{ch: 'z', contexts: []Context{ContextFiles}, want: KeyFilebrowserGoTo},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k := newTestKeybindings(t, test.preset, test.kbMap)
+
+			for i, press := range test.presses {
+				if press.expire {
+					k.sequence.pendingAt = time.Now().Add(-2 * k.sequence.timeout)
+				}
+
+				event := tcell.NewEventKey(tcell.KeyRune, press.ch, tcell.ModNone)
+				for check := range max(press.checks, 1) {
+					if got := k.Key(event, press.contexts...); got != press.want {
+						t.Fatalf("press %d (%q), check %d: got key %q, want %q", i, press.ch, check, got, press.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSequencePrefix(t *testing.T) {
+	k := newTestKeybindings(t, "vim", nil)
+
+	g := Keybinding{tcell.KeyRune, 'g', tcell.ModNone}
+	if !k.isSequencePrefix(g, []Context{ContextApp}) {
+		t.Errorf("'g' is not a sequence prefix in the %s context", ContextApp)
+	}
+	if k.isSequencePrefix(g, []Context{ContextFiles}) {
+		t.Errorf("'g' is a sequence prefix in the %s context", ContextFiles)
+	}
+
+	if key, ok := k.checkSequenceContexts([2]Keybinding{g, g}, []Context{ContextFiles, ContextApp}); !ok || key != KeyNavigateFirst {
+		t.Errorf("got key %q (%v), want %q", key, ok, KeyNavigateFirst)
+	}
+	if _, ok := k.checkSequenceContexts([2]Keybinding{g, g}, []Context{ContextFiles}); ok {
+		t.Errorf("'g g' is matched in the %s context", ContextFiles)
+	}
+}
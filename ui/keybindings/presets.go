@@ -0,0 +1,68 @@
+package keybindings
+
+import "fmt"
+
+// Presets lists the names of the built-in keybinding presets.
+// The first preset is the default, and does not change any keybindings.
+var Presets = []string{
+	"default",
+	"vim",
+}
+
+// presets maps the built-in keybinding presets to the keybindings which they change,
+// in the configuration format. Keys which are not listed keep their default keybindings.
+//
+// The "vim" preset keeps the default navigation keys, and adds:
+//
+//	NavigateUp              k
+//	NavigateDown            j
+//	NavigateLeft            h
+//	NavigateRight           l
+//	NavigateFirst           g g
+//	NavigateLast            G
+//	NavigateTop             Ctrl+u (one page up)
+//	NavigateBottom          Ctrl+d (one page down)
+//
+// Since 'j' and 'g' are then used for navigation, the keys which are bound to them
+// by default are changed to:
+//
+//	PlayerSeekTo            :
+//	FilebrowserGoTo         :
+//	ProgressTransferResume  r
+var presets = map[string]map[Key]string{
+	"vim": {
+		KeyNavigateUp:             "Up, k",
+		KeyNavigateDown:           "Down, j",
+		KeyNavigateLeft:           "Left, h",
+		KeyNavigateRight:          "Right, l",
+		KeyNavigateFirst:          "Home, g g",
+		KeyNavigateLast:           "End, G",
+		KeyNavigateTop:            "PgUp, Ctrl+u",
+		KeyNavigateBottom:         "PgDn, Ctrl+d",
+		KeyPlayerSeekTo:           ":",
+		KeyFilebrowserGoTo:        ":",
+		KeyProgressTransferResume: "r",
+	},
+}
+
+// ApplyPreset changes the keybindings to the ones of the provided preset.
+// Keybindings from the configuration must be validated afterwards, so that they override the preset.
+func (k *Keybindings) ApplyPreset(name string) error {
+	if name == "" || name == Presets[0] {
+		return nil
+	}
+
+	preset, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("config: Invalid keybinding preset %s", name)
+	}
+
+	keyNames := getKeyNames()
+	for keyType, binding := range preset {
+		if err := k.checkBindings(string(keyType), binding, keyNames); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
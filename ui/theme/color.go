@@ -13,7 +13,7 @@ func ColorWrap(elementName Context, elementContent string, attributes ...string)
 		attr = attributes[0]
 	}
 
-	return fmt.Sprintf("[%s%s]%s[-:-:-]", ThemeConfig[elementName], attr, elementContent)
+	return fmt.Sprintf("[%s%s]%s[-:-:-]", colorTag(ThemeConfig[elementName]), attr, elementContent)
 }
 
 // ColorName returns the name of the provided color.
@@ -45,7 +45,22 @@ func GetColor(themeContext Context) tcell.Color {
 		return tcell.Color16
 	}
 
-	return tcell.GetColor(color)
+	return downsample(tcell.GetColor(color))
+}
+
+// colorTag returns the name of the color to be used within a color tag.
+// If the color can be downsampled to a named color, the name of the downsampled
+// color is returned. Otherwise, the color is left to be downsampled by the terminal.
+func colorTag(color string) string {
+	if colorPalette == nil {
+		return color
+	}
+
+	if name := downsample(tcell.GetColor(color)).Name(); name != "" {
+		return name
+	}
+
+	return color
 }
 
 // isLightColor checks if the given color is a light color.
@@ -0,0 +1,70 @@
+package theme
+
+import "github.com/gdamore/tcell/v2"
+
+// ColorMode describes the number of colors with which the theme is displayed.
+type ColorMode string
+
+// The different color modes.
+const (
+	ColorModeAuto      ColorMode = "auto"
+	ColorModeTrueColor ColorMode = "truecolor"
+	ColorMode256       ColorMode = "256"
+	ColorMode16        ColorMode = "16"
+)
+
+// ColorModes lists all the color modes.
+var ColorModes = []ColorMode{ColorModeAuto, ColorModeTrueColor, ColorMode256, ColorMode16}
+
+// colorPalette holds the colors to which theme colors are downsampled.
+// If it is empty, theme colors are displayed as is.
+var colorPalette []tcell.Color
+
+// SetColorMode sets the color mode of the theme. If the mode is automatic,
+// it is detected from the number of colors supported by the terminal.
+func SetColorMode(mode ColorMode, colors int) {
+	if mode == ColorModeAuto {
+		switch {
+		case colors >= 1<<24:
+			mode = ColorModeTrueColor
+
+		case colors >= 256:
+			mode = ColorMode256
+
+		default:
+			mode = ColorMode16
+		}
+	}
+
+	var size int
+
+	switch mode {
+	case ColorMode256:
+		size = 256
+
+	case ColorMode16:
+		size = 16
+
+	default:
+		colorPalette = nil
+		return
+	}
+
+	colorPalette = make([]tcell.Color, 0, size)
+	for index := range size {
+		colorPalette = append(colorPalette, tcell.PaletteColor(index))
+	}
+}
+
+// downsample returns the nearest color from the color palette of the current color mode.
+func downsample(color tcell.Color) tcell.Color {
+	if colorPalette == nil || !color.Valid() {
+		return color
+	}
+
+	if !color.IsRGB() && int(color-tcell.ColorValid) < len(colorPalette) {
+		return color
+	}
+
+	return tcell.FindColor(color, colorPalette)
+}
@@ -2,6 +2,9 @@ package theme
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 )
 
 // Context describes the type of context to apply the color into.
@@ -28,11 +31,15 @@ const (
 	ThemeDeviceProperty           Context = "DeviceProperty"
 	ThemeDevicePropertyConnected  Context = "DevicePropertyConnected"
 	ThemeDevicePropertyDiscovered Context = "DevicePropertyDiscovered"
+	ThemeDeviceSignalStrong       Context = "DeviceSignalStrong"
+	ThemeDeviceSignalMedium       Context = "DeviceSignalMedium"
+	ThemeDeviceSignalWeak         Context = "DeviceSignalWeak"
 	ThemeMenu                     Context = "Menu"
 	ThemeMenuBar                  Context = "MenuBar"
 	ThemeMenuItem                 Context = "MenuItem"
 	ThemeProgressBar              Context = "ProgressBar"
 	ThemeProgressText             Context = "ProgressText"
+	ThemeProgressPaused           Context = "ProgressPaused"
 )
 
 // ThemeConfig stores a list of color for the modifier elements.
@@ -58,20 +65,82 @@ var ThemeConfig = map[Context]string{
 	ThemeDeviceProperty:           "grey",
 	ThemeDevicePropertyConnected:  "green",
 	ThemeDevicePropertyDiscovered: "orange",
+	ThemeDeviceSignalStrong:       "green",
+	ThemeDeviceSignalMedium:       "yellow",
+	ThemeDeviceSignalWeak:         "red",
 
 	ThemeMenu:     "white",
 	ThemeMenuBar:  "default",
 	ThemeMenuItem: "white",
 
-	ThemeProgressBar:  "white",
-	ThemeProgressText: "white",
+	ThemeProgressBar:    "white",
+	ThemeProgressText:   "white",
+	ThemeProgressPaused: "yellow",
+}
+
+// defaultThemeConfig stores the default colors for the modifier elements.
+var defaultThemeConfig = maps.Clone(ThemeConfig)
+
+// ResetThemeConfig resets the theme configuration to the default colors.
+func ResetThemeConfig() {
+	ThemeConfig = maps.Clone(defaultThemeConfig)
+}
+
+// ExportThemeConfig returns the theme configuration in the format used by the configuration.
+// If minimal is set, only colors that differ from the defaults are returned.
+func ExportThemeConfig(minimal bool) map[string]string {
+	themeConfig := make(map[string]string, len(ThemeConfig))
+	for context, color := range ThemeConfig {
+		if minimal && defaultThemeConfig[context] == color {
+			continue
+		}
+
+		themeConfig[string(context)] = color
+	}
+
+	return themeConfig
 }
 
 // ParseThemeConfig parses the theme configuration.
-func ParseThemeConfig(themeConfig map[string]string) error {
+// The configuration is only applied if all theme colors are valid, otherwise an error listing
+// all the incorrect colors is returned. If strict is set, the theme contexts must be known
+// as well, otherwise unknown contexts are ignored, so that configurations which were written
+// for other versions can still be loaded.
+func ParseThemeConfig(themeConfig map[string]string, strict bool) error {
+	var unknown, incorrect []string
+
 	for context, color := range themeConfig {
+		if _, ok := defaultThemeConfig[Context(context)]; !ok {
+			if strict {
+				unknown = append(unknown, context)
+			}
+
+			continue
+		}
+
 		if !isValidElementColor(color) {
-			return fmt.Errorf("theme configuration is incorrect for %s (%s)", context, color)
+			incorrect = append(incorrect, context+" ("+color+")")
+		}
+	}
+
+	if len(unknown) > 0 || len(incorrect) > 0 {
+		var errs []string
+
+		if len(unknown) > 0 {
+			slices.Sort(unknown)
+			errs = append(errs, "unknown theme contexts: "+strings.Join(unknown, ", "))
+		}
+		if len(incorrect) > 0 {
+			slices.Sort(incorrect)
+			errs = append(errs, "incorrect theme colors: "+strings.Join(incorrect, ", "))
+		}
+
+		return fmt.Errorf("theme configuration is incorrect:\n%s", strings.Join(errs, "\n"))
+	}
+
+	for context, color := range themeConfig {
+		if _, ok := defaultThemeConfig[Context(context)]; !ok {
+			continue
 		}
 
 		switch color {